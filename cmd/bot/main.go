@@ -1,7 +1,11 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"cos-ai-bot/internal/bot"
 	"cos-ai-bot/internal/config"
@@ -23,7 +27,7 @@ func main() {
 	}
 
 	// Логируем информацию о конфигурации
-	log.Printf("OpenRouter API Key loaded: %d characters", len(cfg.OpenRouterAPIKey))
+	log.Printf("LLM provider: %s, OpenRouter API Key loaded: %d characters", cfg.LLMProvider, len(cfg.OpenRouterAPIKey))
 
 	// Инициализируем API клиент
 	if err := database.InitDB(); err != nil {
@@ -31,8 +35,12 @@ func main() {
 	}
 	defer database.CloseDB()
 
+	// Останавливаемся по SIGINT/SIGTERM, давая начатой обработке завершиться
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Запускаем бота
-	if err := bot.Run(cfg.BotToken, cfg.OpenRouterAPIKey); err != nil {
+	if err := bot.Run(ctx, cfg); err != nil {
 		log.Fatalf("Failed to run bot: %v", err)
 	}
 }
@@ -7,4 +7,5 @@ toolchain go1.23.11
 require (
 	github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.5.1
 	github.com/joho/godotenv v1.5.1
+	github.com/lib/pq v1.10.9
 )
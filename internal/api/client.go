@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -31,7 +32,7 @@ func NewClient(baseURL string) *Client {
 }
 
 // SearchProducts выполняет поиск продуктов
-func (c *Client) SearchProducts(query string, limit, offset int, brandIDs, ingredientIDs, functionIDs, highlightIDs []int) ([]models.APIProduct, error) {
+func (c *Client) SearchProducts(ctx context.Context, query string, limit, offset int, brandIDs, ingredientIDs, functionIDs, highlightIDs []int) ([]models.APIProduct, error) {
 	params := url.Values{}
 	params.Set("query", query)
 	params.Set("limit", strconv.Itoa(limit))
@@ -50,7 +51,7 @@ func (c *Client) SearchProducts(query string, limit, offset int, brandIDs, ingre
 		params.Set("highlight_ids", formatIntArray(highlightIDs))
 	}
 
-	req, err := http.NewRequest("GET", c.baseURL+"/products/search?"+params.Encode(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/products/search?"+params.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -64,11 +65,11 @@ func (c *Client) SearchProducts(query string, limit, offset int, brandIDs, ingre
 }
 
 // GetProduct получает продукт по ID
-func (c *Client) GetProduct(id int) (*models.APIProductDetail, error) {
+func (c *Client) GetProduct(ctx context.Context, id int) (*models.APIProductDetail, error) {
 	params := url.Values{}
 	params.Set("id", strconv.Itoa(id))
 
-	req, err := http.NewRequest("GET", c.baseURL+"/api/products?"+params.Encode(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/products?"+params.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -82,11 +83,11 @@ func (c *Client) GetProduct(id int) (*models.APIProductDetail, error) {
 }
 
 // GetIngredient получает ингредиент по ID
-func (c *Client) GetIngredient(id int) (*models.APIIngredient, error) {
+func (c *Client) GetIngredient(ctx context.Context, id int) (*models.APIIngredient, error) {
 	params := url.Values{}
 	params.Set("id", strconv.Itoa(id))
 
-	req, err := http.NewRequest("GET", c.baseURL+"/api/ingredients?"+params.Encode(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/ingredients?"+params.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -100,8 +101,8 @@ func (c *Client) GetIngredient(id int) (*models.APIIngredient, error) {
 }
 
 // GetUserProducts получает продукты пользователя
-func (c *Client) GetUserProducts(userID int64) ([]models.APIUserProduct, error) {
-	req, err := http.NewRequest("GET", c.baseURL+"/user/products", nil)
+func (c *Client) GetUserProducts(ctx context.Context, userID int64) ([]models.APIUserProduct, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/user/products", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -117,11 +118,11 @@ func (c *Client) GetUserProducts(userID int64) ([]models.APIUserProduct, error)
 }
 
 // AddUserProduct добавляет продукт пользователю
-func (c *Client) AddUserProduct(userID int64, productID int) error {
+func (c *Client) AddUserProduct(ctx context.Context, userID int64, productID int) error {
 	params := url.Values{}
 	params.Set("id", strconv.Itoa(productID))
 
-	req, err := http.NewRequest("POST", c.baseURL+"/api/user/products?"+params.Encode(), nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/user/products?"+params.Encode(), nil)
 	if err != nil {
 		return err
 	}
@@ -133,11 +134,11 @@ func (c *Client) AddUserProduct(userID int64, productID int) error {
 }
 
 // RemoveUserProduct удаляет продукт из коллекции пользователя
-func (c *Client) RemoveUserProduct(userID int64, productID int) error {
+func (c *Client) RemoveUserProduct(ctx context.Context, userID int64, productID int) error {
 	params := url.Values{}
 	params.Set("id", strconv.Itoa(productID))
 
-	req, err := http.NewRequest("DELETE", c.baseURL+"/api/user/products?"+params.Encode(), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+"/api/user/products?"+params.Encode(), nil)
 	if err != nil {
 		return err
 	}
@@ -149,8 +150,8 @@ func (c *Client) RemoveUserProduct(userID int64, productID int) error {
 }
 
 // GetUserProfile получает профиль пользователя
-func (c *Client) GetUserProfile(userID int64) (*models.APIUserProfile, error) {
-	req, err := http.NewRequest("GET", c.baseURL+"/user/profile", nil)
+func (c *Client) GetUserProfile(ctx context.Context, userID int64) (*models.APIUserProfile, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/user/profile", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -169,13 +170,13 @@ func (c *Client) GetUserProfile(userID int64) (*models.APIUserProfile, error) {
 }
 
 // UpdateUserProfile обновляет профиль пользователя
-func (c *Client) UpdateUserProfile(userID int64, profile *models.APIUserProfileUpdate) error {
+func (c *Client) UpdateUserProfile(ctx context.Context, userID int64, profile *models.APIUserProfileUpdate) error {
 	jsonData, err := json.Marshal(profile)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequest("PUT", c.baseURL+"/user/profile", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "PUT", c.baseURL+"/user/profile", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return err
 	}
@@ -191,8 +192,8 @@ func (c *Client) UpdateUserProfile(userID int64, profile *models.APIUserProfileU
 }
 
 // EmptyUserProfile очищает профиль пользователя
-func (c *Client) EmptyUserProfile(userID int64) error {
-	req, err := http.NewRequest("POST", c.baseURL+"/user/profile/empty", nil)
+func (c *Client) EmptyUserProfile(ctx context.Context, userID int64) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/user/profile/empty", nil)
 	if err != nil {
 		return err
 	}
@@ -207,13 +208,13 @@ func (c *Client) EmptyUserProfile(userID int64) error {
 }
 
 // AddProduct добавляет новый продукт
-func (c *Client) AddProduct(userID int64, product *models.APIProductCreate) error {
+func (c *Client) AddProduct(ctx context.Context, userID int64, product *models.APIProductCreate) error {
 	jsonData, err := json.Marshal(product)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequest("POST", c.baseURL+"/api/products", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/products", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Классы ошибок LLM API. Проверяются через errors.Is.
+var (
+	ErrRateLimited  = errors.New("превышен лимит запросов к нейросети")
+	ErrUnauthorized = errors.New("ошибка аутентификации в API нейросети")
+	ErrOverloaded   = errors.New("нейросеть перегружена или недоступна")
+	ErrTimeout      = errors.New("истекло время ожидания ответа нейросети")
+	ErrBadRequest   = errors.New("нейросеть отклонила запрос")
+)
+
+// LLMError ошибка запроса к LLM API с классом и деталями ответа
+type LLMError struct {
+	Kind       error         // один из ErrRateLimited, ErrUnauthorized, ErrOverloaded, ErrTimeout, ErrBadRequest
+	Model      string        // модель, к которой относился запрос
+	StatusCode int           // HTTP статус, если ответ был получен
+	RetryAfter time.Duration // значение Retry-After, если сервер его прислал
+	Message    string        // текст ошибки от сервера
+}
+
+func (e *LLMError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%v (модель %s, статус %d): %s", e.Kind, e.Model, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("%v (модель %s): %s", e.Kind, e.Model, e.Message)
+}
+
+func (e *LLMError) Unwrap() error {
+	return e.Kind
+}
+
+// Retryable сообщает, имеет ли смысл повторить запрос к той же модели
+func (e *LLMError) Retryable() bool {
+	return e.Kind == ErrRateLimited || e.Kind == ErrOverloaded || e.Kind == ErrTimeout
+}
+
+// classifyStatus определяет класс ошибки по HTTP статусу
+func classifyStatus(status int) error {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status == http.StatusUnauthorized || status == http.StatusForbidden || status == http.StatusPaymentRequired:
+		return ErrUnauthorized
+	case status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout:
+		return ErrTimeout
+	case status >= 500:
+		return ErrOverloaded
+	default:
+		return ErrBadRequest
+	}
+}
+
+// newStatusError создает ошибку по ответу сервера с неуспешным статусом
+func newStatusError(model string, resp *http.Response, body []byte) *LLMError {
+	return &LLMError{
+		Kind:       classifyStatus(resp.StatusCode),
+		Model:      model,
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		Message:    string(body),
+	}
+}
+
+// newAPIError создает ошибку по объекту error из тела ответа.
+// OpenRouter кладет в code HTTP-подобный код ошибки.
+func newAPIError(model string, apiErr *Error) *LLMError {
+	kind := ErrBadRequest
+	if apiErr.Code != 0 {
+		kind = classifyStatus(apiErr.Code)
+	}
+	return &LLMError{Kind: kind, Model: model, StatusCode: apiErr.Code, Message: apiErr.Message}
+}
+
+// classifyTransportError превращает сетевые ошибки в LLMError.
+// Отмена контекста возвращается как есть: это не сбой API.
+func classifyTransportError(ctx context.Context, model string, err error) error {
+	if ctx.Err() == context.Canceled {
+		return ctx.Err()
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return &LLMError{Kind: ErrTimeout, Model: model, Message: err.Error()}
+	}
+	return &LLMError{Kind: ErrOverloaded, Model: model, Message: err.Error()}
+}
+
+// parseRetryAfter разбирает Retry-After в виде числа секунд или HTTP-даты
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
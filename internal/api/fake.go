@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// FakeLLMProvider детерминированный провайдер без обращения к сети.
+// Используется в тестах и при локальной разработке без API ключа.
+type FakeLLMProvider struct {
+	mu        sync.Mutex
+	responses map[string]string // ответ по имени модели
+	err       error
+	requests  []ChatRequest
+}
+
+// NewFakeLLMProvider создает фейковый провайдер
+func NewFakeLLMProvider() *FakeLLMProvider {
+	return &FakeLLMProvider{responses: make(map[string]string)}
+}
+
+// SetResponse задает ответ для модели
+func (f *FakeLLMProvider) SetResponse(model, content string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[model] = content
+}
+
+// SetError заставляет провайдер возвращать ошибку на каждый запрос
+func (f *FakeLLMProvider) SetError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+// Requests возвращает копию всех полученных запросов
+func (f *FakeLLMProvider) Requests() []ChatRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]ChatRequest(nil), f.requests...)
+}
+
+// ChatCompletion возвращает заданный ответ или сводку запроса.
+// Один и тот же запрос всегда дает один и тот же ответ.
+func (f *FakeLLMProvider) ChatCompletion(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, req)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &ChatResponse{Model: req.Model, Content: f.content(req)}, nil
+}
+
+// ChatCompletionStream отдает тот же ответ, что и ChatCompletion, по словам
+func (f *FakeLLMProvider) ChatCompletionStream(ctx context.Context, req ChatRequest, onDelta DeltaFunc) (*ChatResponse, error) {
+	response, err := f.ChatCompletion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if onDelta != nil {
+		for _, word := range strings.SplitAfter(response.Content, " ") {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			onDelta(word)
+		}
+	}
+
+	return response, nil
+}
+
+// content вычисляет ответ на запрос; вызывается под f.mu.
+// Если запрошен ответ по JSON-схеме, возвращается пример, подходящий под схему.
+func (f *FakeLLMProvider) content(req ChatRequest) string {
+	if content, ok := f.responses[req.Model]; ok {
+		return content
+	}
+	if req.ResponseFormat != nil && req.ResponseFormat.JSONSchema != nil {
+		var schema map[string]any
+		if err := json.Unmarshal(req.ResponseFormat.JSONSchema.Schema, &schema); err == nil {
+			data, _ := json.Marshal(schemaExample(schema))
+			return string(data)
+		}
+	}
+
+	hash := sha256.New()
+	for _, message := range req.Messages {
+		fmt.Fprintf(hash, "%s\x00%s\x00", message.Role, message.Content)
+	}
+
+	return fmt.Sprintf("## Тестовый ответ\n\nМодель: %s\nСообщений: %d\nКонтрольная сумма: %x",
+		req.Model, len(req.Messages), hash.Sum(nil)[:8])
+}
+
+// schemaExample строит минимальное значение, подходящее под JSON-схему:
+// объект со всеми свойствами, массив из одного элемента, первое значение enum
+func schemaExample(schema map[string]any) any {
+	if values, ok := schema["enum"].([]any); ok && len(values) > 0 {
+		return values[0]
+	}
+
+	schemaType := schema["type"]
+	if types, ok := schemaType.([]any); ok && len(types) > 0 {
+		schemaType = types[0]
+	}
+	switch schemaType {
+	case "object":
+		object := map[string]any{}
+		properties, _ := schema["properties"].(map[string]any)
+		for name, property := range properties {
+			if property, ok := property.(map[string]any); ok {
+				object[name] = schemaExample(property)
+			}
+		}
+		return object
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		return []any{schemaExample(items)}
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	case "null":
+		return nil
+	default:
+		return "Тестовый ответ"
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Роли сообщений в диалоге с моделью
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// ChatRequest запрос к языковой модели в формате chat completion
+type ChatRequest struct {
+	Model          string
+	FallbackModels []string // модели, которые пробуются по порядку, если Model недоступна
+	Messages       []Message
+	MaxTokens      int
+	Temperature    float64
+	ResponseFormat *ResponseFormat // формат ответа; nil — свободный текст
+}
+
+// ResponseFormat требует от модели ответ в виде JSON по схеме
+// (response_format с type json_schema в OpenAI-совместимых API)
+type ResponseFormat struct {
+	Type       string      `json:"type"`
+	JSONSchema *JSONSchema `json:"json_schema,omitempty"`
+}
+
+// JSONSchema именованная JSON-схема ответа
+type JSONSchema struct {
+	Name   string          `json:"name"`
+	Strict bool            `json:"strict"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+// NewJSONSchemaFormat формат ответа со строгим соблюдением схемы
+func NewJSONSchemaFormat(name string, schema json.RawMessage) *ResponseFormat {
+	return &ResponseFormat{
+		Type:       "json_schema",
+		JSONSchema: &JSONSchema{Name: name, Strict: true, Schema: schema},
+	}
+}
+
+// ChatResponse ответ языковой модели
+type ChatResponse struct {
+	Model   string
+	Content string
+	Cached  bool // ответ взят из кэша, модель не вызывалась
+}
+
+// LLMProvider провайдер языковой модели с семантикой chat completion
+type LLMProvider interface {
+	ChatCompletion(ctx context.Context, req ChatRequest) (*ChatResponse, error)
+}
+
+// DeltaFunc получает очередной фрагмент ответа при потоковой генерации
+type DeltaFunc func(delta string)
+
+// StreamingLLMProvider провайдер, умеющий отдавать ответ по мере генерации.
+// onDelta вызывается для каждого фрагмента, итоговый ответ содержит весь текст.
+type StreamingLLMProvider interface {
+	LLMProvider
+	ChatCompletionStream(ctx context.Context, req ChatRequest, onDelta DeltaFunc) (*ChatResponse, error)
+}
@@ -0,0 +1,222 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAIClient клиент для любого OpenAI-совместимого endpoint /chat/completions
+type OpenAIClient struct {
+	apiKey  string
+	baseURL string
+	headers map[string]string
+	client  *http.Client
+}
+
+// ChatCompletionRequest структура запроса к /chat/completions
+type ChatCompletionRequest struct {
+	Model          string          `json:"model"`
+	Messages       []Message       `json:"messages"`
+	MaxTokens      int             `json:"max_tokens,omitempty"`
+	Temperature    float64         `json:"temperature"` // без omitempty: 0 — осознанная настройка, а не «по умолчанию»
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+	Stream         bool            `json:"stream,omitempty"`
+}
+
+// Message структура сообщения диалога
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatCompletionResponse структура ответа /chat/completions
+type ChatCompletionResponse struct {
+	Model   string   `json:"model"`
+	Choices []Choice `json:"choices"`
+	Error   *Error   `json:"error,omitempty"`
+}
+
+// Choice структура выбора из ответа
+type Choice struct {
+	Message Message `json:"message"`
+}
+
+// ChatCompletionChunk фрагмент потокового ответа (Server-Sent Events)
+type ChatCompletionChunk struct {
+	Model   string        `json:"model"`
+	Choices []ChunkChoice `json:"choices"`
+	Error   *Error        `json:"error,omitempty"`
+}
+
+// ChunkChoice приращение ответа во фрагменте потока
+type ChunkChoice struct {
+	Delta Message `json:"delta"`
+}
+
+// Error структура ошибки
+type Error struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	Code    int    `json:"code,omitempty"`
+}
+
+// NewOpenAIClient создает клиент OpenAI-совместимого API
+func NewOpenAIClient(baseURL, apiKey string) *OpenAIClient {
+	return &OpenAIClient{
+		apiKey:  apiKey,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		headers: map[string]string{},
+		client: &http.Client{
+			Timeout: 120 * time.Second, // Генерация длинного ответа может занимать до 2 минут
+		},
+	}
+}
+
+// ChatCompletion отправляет диалог модели и возвращает ее ответ
+func (c *OpenAIClient) ChatCompletion(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	resp, err := c.send(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, classifyTransportError(ctx, req.Model, err)
+	}
+
+	var response ChatCompletionResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("ошибка парсинга ответа: %v", err)
+	}
+
+	if response.Error != nil {
+		return nil, newAPIError(req.Model, response.Error)
+	}
+
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("пустой ответ от нейросети")
+	}
+
+	model := response.Model
+	if model == "" {
+		model = req.Model
+	}
+
+	return &ChatResponse{Model: model, Content: response.Choices[0].Message.Content}, nil
+}
+
+// ChatCompletionStream запрашивает ответ с stream: true и разбирает поток
+// Server-Sent Events, передавая каждый фрагмент текста в onDelta
+func (c *OpenAIClient) ChatCompletionStream(ctx context.Context, req ChatRequest, onDelta DeltaFunc) (*ChatResponse, error) {
+	resp, err := c.send(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	model := req.Model
+	var content strings.Builder
+	reader := bufio.NewReader(resp.Body)
+
+	for {
+		line, readErr := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+
+		// Пустые строки разделяют события, строки с ":" — комментарии (keep-alive)
+		if strings.HasPrefix(line, "data:") {
+			payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			if payload == "[DONE]" {
+				break
+			}
+
+			var chunk ChatCompletionChunk
+			if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+				return nil, fmt.Errorf("ошибка парсинга фрагмента потока: %v", err)
+			}
+			if chunk.Error != nil {
+				return nil, newAPIError(req.Model, chunk.Error)
+			}
+			if chunk.Model != "" {
+				model = chunk.Model
+			}
+			for _, choice := range chunk.Choices {
+				if choice.Delta.Content == "" {
+					continue
+				}
+				content.WriteString(choice.Delta.Content)
+				if onDelta != nil {
+					onDelta(choice.Delta.Content)
+				}
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, classifyTransportError(ctx, req.Model, readErr)
+		}
+	}
+
+	if content.Len() == 0 {
+		return nil, fmt.Errorf("пустой ответ от нейросети")
+	}
+
+	return &ChatResponse{Model: model, Content: content.String()}, nil
+}
+
+// send выполняет запрос к /chat/completions и проверяет статус ответа.
+// При успехе вызывающий обязан закрыть тело ответа.
+func (c *OpenAIClient) send(ctx context.Context, req ChatRequest, stream bool) (*http.Response, error) {
+	jsonData, err := json.Marshal(ChatCompletionRequest{
+		Model:          req.Model,
+		Messages:       req.Messages,
+		MaxTokens:      req.MaxTokens,
+		Temperature:    req.Temperature,
+		ResponseFormat: req.ResponseFormat,
+		Stream:         stream,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка маршалинга запроса: %v", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания запроса: %v", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	}
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	for name, value := range c.headers {
+		httpReq.Header.Set(name, value)
+	}
+
+	// Логируем запрос (без API ключа для безопасности)
+	fmt.Printf("LLM запрос: POST %s, модель %s, stream=%t, API ключ длиной %d символов\n", c.baseURL+"/chat/completions", req.Model, stream, len(c.apiKey))
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, classifyTransportError(ctx, req.Model, err)
+	}
+
+	if resp.StatusCode != 200 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, newStatusError(req.Model, resp, body)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy параметры повторных попыток запроса к LLM
+type RetryPolicy struct {
+	MaxAttempts int           // попыток на одну модель, включая первую
+	BaseDelay   time.Duration // задержка перед второй попыткой
+	MaxDelay    time.Duration // верхняя граница задержки
+}
+
+// RetryingProvider повторяет неудачные запросы с экспоненциальной задержкой
+// и переходит к резервным моделям из ChatRequest.FallbackModels
+type RetryingProvider struct {
+	provider LLMProvider
+	policy   RetryPolicy
+}
+
+// NewRetryingProvider оборачивает провайдер повторными попытками
+func NewRetryingProvider(provider LLMProvider, policy RetryPolicy) *RetryingProvider {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return &RetryingProvider{provider: provider, policy: policy}
+}
+
+// ChatCompletion выполняет запрос с повторами и резервными моделями
+func (p *RetryingProvider) ChatCompletion(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	return p.do(ctx, req, func(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+		return p.provider.ChatCompletion(ctx, req)
+	})
+}
+
+// ChatCompletionStream выполняет потоковый запрос с повторами.
+// Повтор возможен только пока пользователю не отдан ни один фрагмент,
+// иначе текст продублировался бы.
+func (p *RetryingProvider) ChatCompletionStream(ctx context.Context, req ChatRequest, onDelta DeltaFunc) (*ChatResponse, error) {
+	streaming, ok := p.provider.(StreamingLLMProvider)
+	if !ok {
+		response, err := p.ChatCompletion(ctx, req)
+		if err == nil && onDelta != nil {
+			onDelta(response.Content)
+		}
+		return response, err
+	}
+
+	emitted := false
+	return p.do(ctx, req, func(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+		response, err := streaming.ChatCompletionStream(ctx, req, func(delta string) {
+			emitted = true
+			if onDelta != nil {
+				onDelta(delta)
+			}
+		})
+		if err != nil && emitted {
+			return nil, fmt.Errorf("%w: %w", ErrStreamInterrupted, err)
+		}
+		return response, err
+	})
+}
+
+// ErrStreamInterrupted оборачивает ошибку потока, оборвавшегося после выдачи части текста
+var ErrStreamInterrupted = errors.New("генерация прервалась на середине ответа")
+
+// do перебирает основную и резервные модели, повторяя временные ошибки
+func (p *RetryingProvider) do(ctx context.Context, req ChatRequest, call func(context.Context, ChatRequest) (*ChatResponse, error)) (*ChatResponse, error) {
+	models := append([]string{req.Model}, req.FallbackModels...)
+
+	var lastErr error
+	for modelIndex, model := range models {
+		attemptReq := req
+		attemptReq.Model = model
+		attemptReq.FallbackModels = nil
+
+		for attempt := 1; attempt <= p.policy.MaxAttempts; attempt++ {
+			response, err := call(ctx, attemptReq)
+			if err == nil {
+				if modelIndex > 0 {
+					fmt.Printf("LLM: ответ получен от резервной модели %s\n", model)
+				}
+				return response, nil
+			}
+			if errors.Is(err, ErrStreamInterrupted) {
+				return nil, err
+			}
+			lastErr = err
+
+			var llmErr *LLMError
+			if !errors.As(err, &llmErr) {
+				// Отмена контекста и прочие ошибки не лечатся повтором
+				return nil, err
+			}
+			if llmErr.Kind == ErrUnauthorized {
+				// Неверный ключ одинаково сломан для всех моделей
+				return nil, err
+			}
+			if !llmErr.Retryable() || attempt == p.policy.MaxAttempts {
+				break
+			}
+
+			delay := p.backoff(attempt, llmErr.RetryAfter)
+			fmt.Printf("LLM: попытка %d/%d для модели %s не удалась (%v), повтор через %s\n", attempt, p.policy.MaxAttempts, model, llmErr.Kind, delay)
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, err
+			}
+		}
+
+		if modelIndex+1 < len(models) {
+			fmt.Printf("LLM: модель %s недоступна (%v), переходим к %s\n", model, lastErr, models[modelIndex+1])
+		}
+	}
+
+	return nil, lastErr
+}
+
+// backoff вычисляет задержку перед следующей попыткой.
+// Retry-After от сервера имеет приоритет; иначе задержка растет экспоненциально
+// со случайным разбросом, чтобы клиенты не повторяли запросы синхронно.
+func (p *RetryingProvider) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		if p.policy.MaxDelay > 0 && retryAfter > p.policy.MaxDelay {
+			return p.policy.MaxDelay
+		}
+		return retryAfter
+	}
+
+	delay := p.policy.BaseDelay << (attempt - 1)
+	if p.policy.MaxDelay > 0 && (delay > p.policy.MaxDelay || delay <= 0) {
+		delay = p.policy.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	// Случайная задержка от половины до полной
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// sleepContext ждет delay или отмены контекста
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
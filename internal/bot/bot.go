@@ -1,504 +1,448 @@
 package bot
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"html"
 	"log"
-	"strconv"
 	"strings"
+	"time"
 
+	"cos-ai-bot/internal/api"
+	"cos-ai-bot/internal/config"
 	"cos-ai-bot/internal/database"
+	"cos-ai-bot/internal/i18n"
+	"cos-ai-bot/internal/markdown"
 	"cos-ai-bot/internal/models"
+	"cos-ai-bot/internal/questionnaire"
+	"cos-ai-bot/internal/routine"
 	"cos-ai-bot/internal/services"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-var userStates = make(map[int64]*models.UserState)        // userID -> состояние анкеты (fallback)
 var recommendationService *services.RecommendationService // сервис рекомендаций
 
+// recommendationEmoji значок каждого типа рекомендаций в меню, заголовках и истории
+var recommendationEmoji = map[services.RecommendationType]string{
+	services.RecommendationAnketa:   "📊",
+	services.RecommendationProducts: "🧴",
+	services.RecommendationGeneral:  "🧩",
+}
+
 // deleteMessage удаляет сообщение
 func deleteMessage(bot *tgbotapi.BotAPI, chatID int64, messageID int) {
 	deleteMsg := tgbotapi.NewDeleteMessage(chatID, messageID)
 	bot.Send(deleteMsg)
 }
 
-// Маппинг технических кодов в человекочитаемые значения
-var valueMapping = map[string]string{
-	// Типы кожи
-	"skin_dry":       "Сухая",
-	"skin_oily":      "Жирная",
-	"skin_normal":    "Нормальная",
-	"skin_sensitive": "Чувствительная",
-	"skin_combined":  "Комбинированная",
-	"skin_unknown":   "Не знаю",
-
-	// Возраст
-	"age_18_minus": "До 18 лет",
-	"age_18_24":    "18-24 года",
-	"age_25_34":    "25-34 года",
-	"age_35_44":    "35-44 года",
-	"age_45_plus":  "45+ лет",
-	"age_ignore":   "Не учитывать",
-
-	// Пол
-	"gender_male":   "Мужчина",
-	"gender_female": "Женщина",
-	"gender_other":  "Другое",
-	"gender_ignore": "Не учитывать",
-
-	// Беременность/лактация
-	"pregnancy":               "Беременность",
-	"lactation":               "Лактация",
-	"pregnancy_and_lactation": "Беременность и лактация",
-	"none_of_above":           "Ничего из перечисленного",
-	"pregnancy_ignore":        "Не учитывать",
-
-	// Цели
-	"goal_hydration":  "Увлажнение и питание",
-	"goal_tone":       "Выравнивание тона",
-	"goal_antiage":    "Антивозрастной уход",
-	"goal_texture":    "Улучшение текстуры",
-	"goal_refresh":    "Освежить и поддерживать",
-	"goal_minimalism": "Минимализм, только базовый уход",
-	"goal_other":      "Другое",
-
-	// Бюджет
-	"budget_low":     "До 1000₽",
-	"budget_medium":  "1000-3000₽",
-	"budget_high":    "3000-5000₽",
-	"budget_premium": "5000₽+",
-	"budget_ignore":  "Не важно",
-
-	// Аллергии
-	"allergies_none":          "Нет аллергий",
-	"allergies_nickel":        "Аллергия на никель",
-	"allergies_lanolin":       "Аллергия на ланолин",
-	"allergies_fragrance":     "Аллергия на отдушки",
-	"allergies_preservatives": "Аллергия на консерванты",
-	"allergies_other":         "Другое",
-
-	// Климат
-	"climate_dry":       "Сухой",
-	"climate_humid":     "Влажный",
-	"climate_hot":       "Жаркий",
-	"climate_cold":      "Холодный",
-	"climate_temperate": "Переменный / умеренный",
-	"climate_polluted":  "Загрязнённый (город, смог, пыль)",
-	"climate_multiple":  "Живу в нескольких климатах (путешествую/переезды)",
-	"climate_unknown":   "Не знаю",
-
-	// Тип кожи по Фицпатрику
-	"fitzpatrick_1":       "I – очень светлая, всегда обгорает",
-	"fitzpatrick_2":       "II – светлая, обгорает, но может немного загорать",
-	"fitzpatrick_3":       "III – светло-смуглая, легко загорает",
-	"fitzpatrick_4":       "IV – смуглая, редко обгорает",
-	"fitzpatrick_5":       "V – тёмная, почти не обгорает",
-	"fitzpatrick_6":       "VI – очень тёмная, никогда не обгорает",
-	"fitzpatrick_unknown": "Не знаю / Не хочу указывать",
-
-	// Образ жизни
-	"lifestyle_stress":   "Частые стрессы",
-	"lifestyle_sleep":    "Недосып / сбитый режим",
-	"lifestyle_screen":   "Много экранного времени",
-	"lifestyle_sweat":    "Часто потею (спорт, жара и т.д.)",
-	"lifestyle_computer": "Работаю за компьютером",
-	"lifestyle_active":   "Активно двигаюсь в течение дня",
-	"lifestyle_outdoor":  "Регулярно на улице",
-	"lifestyle_passive":  "Пассивный / домашний образ жизни",
-	"lifestyle_other":    "Другое",
-
-	// Питание
-	"diet_vegan":       "Веганство",
-	"diet_vegetarian":  "Вегетарианство",
-	"diet_halal":       "Халяль",
-	"diet_keto":        "Кето / Палео / Низкоуглеводная",
-	"diet_gluten_free": "Безглютеновая",
-	"diet_no_alcohol":  "Я избегаю спирта в составе",
-	"diet_no_animal":   "Я избегаю компонентов животного происхождения",
-	"diet_none":        "Нет особых ограничений",
-	"diet_other":       "Другое",
-}
-
-// convertToHumanReadable преобразует технический код в человекочитаемое значение
-func convertToHumanReadable(value string) string {
-	if humanReadable, exists := valueMapping[value]; exists {
-		return humanReadable
+// Run запускает бота и работает до отмены ctx.
+// После отмены бот перестает принимать обновления и ждет завершения
+// начатой обработки не дольше cfg.ShutdownTimeout.
+func Run(ctx context.Context, cfg *config.Config) error {
+	bot, err := tgbotapi.NewBotAPI(cfg.BotToken)
+	if err != nil {
+		return fmt.Errorf("ошибка создания бота: %v", err)
 	}
-	return value // Если маппинг не найден, возвращаем исходное значение
-}
-
-// formatRecommendationForTelegram форматирует текст рекомендации для красивого отображения в Telegram
-func formatRecommendationForTelegram(text string) string {
-	// Заменяем Markdown разметку на Telegram форматирование
-	formatted := text
-
-	// Сначала заменяем жирный текст, чтобы избежать конфликтов
-	formatted = strings.ReplaceAll(formatted, "**", "<b>")
-
-	// Заменяем курсив
-	formatted = strings.ReplaceAll(formatted, "*", "<i>")
-
-	// Заменяем заголовки
-	formatted = strings.ReplaceAll(formatted, "### ", "🔹 <b>")
-	formatted = strings.ReplaceAll(formatted, "#### ", "🔸 <b>")
-	formatted = strings.ReplaceAll(formatted, "## ", "🔹 <b>")
-	formatted = strings.ReplaceAll(formatted, "# ", "🔹 <b>")
-
-	// Заменяем горизонтальные линии
-	formatted = strings.ReplaceAll(formatted, "---", "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	formatted = strings.ReplaceAll(formatted, "--", "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-
-	// Добавляем эмодзи для списков
-	formatted = strings.ReplaceAll(formatted, "1. ", "1️⃣ ")
-	formatted = strings.ReplaceAll(formatted, "2. ", "2️⃣ ")
-	formatted = strings.ReplaceAll(formatted, "3. ", "3️⃣ ")
-	formatted = strings.ReplaceAll(formatted, "4. ", "4️⃣ ")
-	formatted = strings.ReplaceAll(formatted, "5. ", "5️⃣ ")
-	formatted = strings.ReplaceAll(formatted, "6. ", "6️⃣ ")
-	formatted = strings.ReplaceAll(formatted, "7. ", "7️⃣ ")
-	formatted = strings.ReplaceAll(formatted, "8. ", "8️⃣ ")
-	formatted = strings.ReplaceAll(formatted, "9. ", "9️⃣ ")
-
-	// Добавляем эмодзи для подпунктов
-	formatted = strings.ReplaceAll(formatted, "   * ", "   • ")
-	formatted = strings.ReplaceAll(formatted, "   - ", "   • ")
-
-	// Добавляем эмодзи для важных слов
-	formatted = strings.ReplaceAll(formatted, "Зачем:", "💡 <b>Зачем:</b>")
-	formatted = strings.ReplaceAll(formatted, "Средство:", "🧴 <b>Средство:</b>")
-	formatted = strings.ReplaceAll(formatted, "Тип кожи:", "👤 <b>Тип кожи:</b>")
-	formatted = strings.ReplaceAll(formatted, "Цель:", "🎯 <b>Цель:</b>")
-
-	// Добавляем эмодзи для этапов ухода
-	formatted = strings.ReplaceAll(formatted, "Очищение", "🧼 <b>Очищение</b>")
-	formatted = strings.ReplaceAll(formatted, "Тоник", "💧 <b>Тоник</b>")
-	formatted = strings.ReplaceAll(formatted, "Активный уход", "⚡ <b>Активный уход</b>")
-	formatted = strings.ReplaceAll(formatted, "Увлажнение", "💧 <b>Увлажнение</b>")
-	formatted = strings.ReplaceAll(formatted, "Солнцезащита", "☀️ <b>Солнцезащита</b>")
-	formatted = strings.ReplaceAll(formatted, "Двойное очищение", "🔄 <b>Двойное очищение</b>")
-
-	// Добавляем эмодзи для времени
-	formatted = strings.ReplaceAll(formatted, "Утренний уход", "🌅 <b>Утренний уход</b>")
-	formatted = strings.ReplaceAll(formatted, "Вечерний уход", "🌙 <b>Вечерний уход</b>")
-
-	// Добавляем эмодзи для ингредиентов
-	formatted = strings.ReplaceAll(formatted, "салициловая кислота", "<b>салициловая кислота</b>")
-	formatted = strings.ReplaceAll(formatted, "ниацинамид", "<b>ниацинамид</b>")
-	formatted = strings.ReplaceAll(formatted, "гиалуроновая кислота", "<b>гиалуроновая кислота</b>")
-	formatted = strings.ReplaceAll(formatted, "азулаиновая кислота", "<b>азулаиновая кислота</b>")
-	formatted = strings.ReplaceAll(formatted, "оксид цинка", "<b>оксид цинка</b>")
-
-	// Исправляем двойные теги
-	formatted = strings.ReplaceAll(formatted, "<b><b>", "<b>")
-	formatted = strings.ReplaceAll(formatted, "</b></b>", "</b>")
-	formatted = strings.ReplaceAll(formatted, "<i><i>", "<i>")
-	formatted = strings.ReplaceAll(formatted, "</i></i>", "</i>")
-
-	// Валидация и исправление HTML тегов
-	formatted = validateAndFixHTMLTags(formatted)
-
-	return formatted
-}
 
-// validateAndFixHTMLTags проверяет и исправляет HTML теги
-func validateAndFixHTMLTags(text string) string {
-	// Подсчитываем открывающие и закрывающие теги
-	bOpenCount := strings.Count(text, "<b>")
-	bCloseCount := strings.Count(text, "</b>")
-	iOpenCount := strings.Count(text, "<i>")
-	iCloseCount := strings.Count(text, "</i>")
+	bot.Debug = true
+	log.Printf("Бот запущен: %s", bot.Self.UserName)
 
-	// Добавляем недостающие закрывающие теги
-	if bOpenCount > bCloseCount {
-		text += strings.Repeat("</b>", bOpenCount-bCloseCount)
+	// Незавершенные анкеты, настройки пользователей, история рекомендаций
+	// и составленный пользователями уход переживают перезапуск при хранилище postgres
+	db, err := openDatabase(ctx, cfg)
+	if err != nil {
+		return err
 	}
-	if iOpenCount > iCloseCount {
-		text += strings.Repeat("</i>", iOpenCount-iCloseCount)
+	if db != nil {
+		defer db.Close()
 	}
+	sessions = newSessionStore(db, cfg.SessionTTL)
+	userPreferences = newPreferenceStore(db)
+	recommendationHistory = newHistoryStore(db)
+	userRoutines = newRoutineStore(db)
+	cache := newLLMCacheStore(db, cfg.LLMCacheTTL)
 
-	return text
-}
-
-// Run запускает бота
-func Run(token, openRouterAPIKey string) error {
-	bot, err := tgbotapi.NewBotAPI(token)
+	// Инициализируем сервис рекомендаций
+	provider, err := services.NewLLMProvider(cfg, cache)
 	if err != nil {
-		return fmt.Errorf("ошибка создания бота: %v", err)
+		return err
+	}
+	recommendationService = services.NewRecommendationService(provider, services.ModelsFromConfig(cfg), recommendationHistory, userRoutines)
+	log.Printf("Сервис рекомендаций инициализирован")
+	go cleanupExpired(ctx, sessions, cfg.SessionCleanupInterval, "брошенных анкет")
+	log.Printf("Хранилище анкет: %s, срок жизни %s", cfg.SessionStore, cfg.SessionTTL)
+	if cache != nil {
+		go cleanupExpired(ctx, cache, cfg.SessionCleanupInterval, "устаревших ответов модели")
+		go logCacheStats(ctx, cfg.LLMCacheStatsInterval)
+		log.Printf("Кэш ответов модели: %s, срок жизни %s", cfg.SessionStore, cfg.LLMCacheTTL)
 	}
 
-	bot.Debug = true
-	log.Printf("Бот запущен: %s", bot.Self.UserName)
+	// Обработчики получают собственный контекст: сигнал остановки не прерывает
+	// начатые запросы сразу, а дает им время завершиться
+	workCtx, cancelWork := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelWork()
 
-	// Инициализируем сервис рекомендаций
-	recommendationService = services.NewRecommendationService(openRouterAPIKey)
-	log.Printf("Сервис рекомендаций инициализирован")
+	dispatcher := NewDispatcher(workCtx, func(ctx context.Context, update tgbotapi.Update) {
+		handleUpdate(ctx, bot, update)
+	}, cfg.Workers, cfg.QueueSize)
+	log.Printf("Диспетчер обновлений: %d воркеров, очередь %d", cfg.Workers, cfg.QueueSize)
 
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
+	switch cfg.UpdateMode {
+	case config.UpdateModeWebhook:
+		err = runWebhook(ctx, bot, cfg, dispatcher)
+	default:
+		err = runPolling(ctx, bot, dispatcher)
+	}
 
-	updates := bot.GetUpdatesChan(u)
+	// Генерации рекомендаций идут вне очереди диспетчера и ждутся отдельно,
+	// но в пределах того же общего времени
+	log.Printf("Ожидаем завершения обработки обновлений (до %s)", cfg.ShutdownTimeout)
+	deadline := time.Now().Add(cfg.ShutdownTimeout)
+	if !dispatcher.WaitTimeout(cfg.ShutdownTimeout) || !generations.WaitTimeout(time.Until(deadline)) {
+		log.Printf("Обработка не завершилась вовремя, прерываем незавершенные запросы")
+		cancelWork()
+		dispatcher.Wait()
+		generations.Wait()
+	}
+	log.Printf("Бот остановлен")
 
-	for update := range updates {
-		if update.Message == nil && update.CallbackQuery == nil && update.InlineQuery == nil {
-			continue
-		}
+	return err
+}
 
-		if update.Message != nil {
-			handleMessage(bot, update.Message)
-		}
+// handleUpdate направляет обновление в соответствующий обработчик
+func handleUpdate(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) {
+	ctx = withUserLanguage(ctx, update.SentFrom())
 
-		if update.CallbackQuery != nil {
-			handleCallbackQuery(bot, update.CallbackQuery)
-		}
+	if update.Message != nil {
+		handleMessage(ctx, bot, update.Message)
+	}
 
-		if update.InlineQuery != nil {
-			handleInlineQuery(bot, update.InlineQuery)
-		}
+	if update.CallbackQuery != nil {
+		handleCallbackQuery(ctx, bot, update.CallbackQuery)
 	}
 
-	return nil
+	if update.InlineQuery != nil {
+		handleInlineQuery(ctx, bot, update.InlineQuery)
+	}
 }
 
 // handleMessage обрабатывает входящие сообщения
-func handleMessage(bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
+func handleMessage(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
 	text := message.Text
 
 	log.Printf("[%s] %s", message.From.UserName, text)
 
 	// Обработка команд
 	if message.IsCommand() {
-		handleCommand(bot, message)
+		// Команда отменяет ожидание названия ухода
+		setRoutineNamePending(message.Chat.ID, false)
+		handleCommand(ctx, bot, message)
+		return
+	}
+
+	// Название ухода, которое бот попросил прислать
+	if handleRoutineNameInput(ctx, bot, message) {
 		return
 	}
 
 	// Обработка парсинга URL
 	if strings.Contains(text, "incidecoder.com") {
-		handleIncidecoderURL(bot, message)
+		handleIncidecoderURL(ctx, bot, message)
 		return
 	}
 
 	// Обработка формы
-	handleFormInput(bot, message)
+	handleFormInput(ctx, bot, message)
 }
 
 // handleCommand обрабатывает команды
-func handleCommand(bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
+func handleCommand(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
 	command := message.Command()
 
+	loc := i18n.FromContext(ctx)
+
 	switch command {
 	case "start":
 		// Отправляем фото с приветственным сообщением
-		photo := tgbotapi.NewPhoto(chatID, tgbotapi.FilePath("images/01.png"))
-		photo.Caption = `✨ Я — твой умный бьюти-бот, созданный, чтобы наконец навести порядок в косметичке. Этот бот - часть проекта Cos AI, созданного для того, чтобы помочь тебе собрать персонализированный уход за кожей.
-Хочешь попробовать? Давай начнем с небольшой анкеты 💬👇`
-		photo.ParseMode = "HTML"
-
-		// Создаем клавиатуру с кнопками
-		keyboard := tgbotapi.NewInlineKeyboardMarkup(
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("📋 Анкета", "start_form"),
-			),
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("🤖 Рекомендации", "recommendations"),
-			),
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("🧴 Мои продукты", "my_products"),
-			),
-		)
-		photo.ReplyMarkup = keyboard
-		bot.Send(photo)
+		sendMainMenu(ctx, bot, chatID)
 
 	case "help":
-		helpText := `Доступные команды:
-/start - Начать работу с ботом
-/help - Показать эту справку
-/form - Заполнить форму подбора ухода
-/myproducts - Показать мои продукты
-
-🔍 Для поиска продуктов используйте inline режим:
-@cosmetics_lab_ai_bot add [название продукта]`
-		msg := tgbotapi.NewMessage(chatID, helpText)
+		msg := tgbotapi.NewMessage(chatID, loc.T("help.text"))
 		bot.Send(msg)
 
 	case "form":
+		// Предлагаем продолжить незавершенную анкету, если она есть
+		if state, ok := getFormSession(ctx, chatID); ok && state.Step > 0 {
+			showResumeForm(ctx, bot, chatID, state)
+			return
+		}
+
 		// Инициализируем новое состояние для анкеты
-		newState := &models.UserState{Step: 1}
-		saveUserState(chatID, newState)
-		ShowSkincareFormStep(bot, chatID, 1)
+		newState := &models.UserState{}
+		skincareForm.Start(newState)
+		saveUserState(ctx, chatID, newState)
+		ShowSkincareFormStep(ctx, bot, chatID, newState)
 
 	case "myproducts":
 		// Показываем продукты пользователя
-		handleMyProductsCommand(bot, message)
+		handleMyProductsCommand(ctx, bot, message)
+
+	case "language":
+		handleLanguageCommand(ctx, bot, message)
 
 	default:
-		msg := tgbotapi.NewMessage(chatID, "Неизвестная команда. Используйте /help для справки.")
+		msg := tgbotapi.NewMessage(chatID, loc.T("command.unknown"))
 		bot.Send(msg)
 	}
 }
 
 // handleCallbackQuery обрабатывает нажатия на inline кнопки
-func handleCallbackQuery(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+func handleCallbackQuery(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
 	chatID := callback.Message.Chat.ID
 	data := callback.Data
 
 	log.Printf("[CALLBACK] %s: %s", callback.From.UserName, data)
 
-	// Удаляем предыдущее сообщение с кнопками
-	deleteMessage(bot, chatID, callback.Message.MessageID)
-
-	// Отвечаем на callback
-	callbackAnswer := tgbotapi.NewCallback(callback.ID, "")
-	bot.Request(callbackAnswer)
-
-	switch {
-	case data == "start_form":
-		handleAnketa(bot, callback)
-
-	case strings.HasPrefix(data, "skin_") || strings.HasPrefix(data, "age_") ||
-		strings.HasPrefix(data, "gender_") || strings.HasPrefix(data, "pregnancy_") ||
-		strings.HasPrefix(data, "goal_") || strings.HasPrefix(data, "climate_") ||
-		strings.HasPrefix(data, "fitzpatrick_") || strings.HasPrefix(data, "lifestyle_") ||
-		strings.HasPrefix(data, "diet_") || strings.HasPrefix(data, "allergies_"):
-		handleFormCallback(bot, callback)
-
-	case strings.HasPrefix(data, "product_"):
-		handleProductSelection(bot, callback)
-
-	case strings.HasPrefix(data, "add_product_"):
-		handleAddProductToCollection(bot, callback)
+	// Обработчики in-place маршрутов сами обновляют сообщение и отвечают на callback
+	if route, err := callbackRouter.Match(data); err != nil || !inPlaceRoutes[route] {
+		// Удаляем предыдущее сообщение с кнопками
+		deleteMessage(bot, chatID, callback.Message.MessageID)
 
-	case strings.HasPrefix(data, "remove_product_"):
-		handleRemoveProductFromCollection(bot, callback)
-
-	case data == "recommendations":
-		handleRecommendations(bot, callback)
-
-	case data == "recommendations_anketa":
-		handleRecommendationsAnketa(bot, callback)
+		// Отвечаем на callback
+		callbackAnswer := tgbotapi.NewCallback(callback.ID, "")
+		bot.Request(callbackAnswer)
+	}
 
-	case data == "recommendations_products":
-		handleRecommendationsProducts(bot, callback)
+	if err := callbackRouter.Dispatch(ctx, bot, callback); err != nil {
+		log.Printf("Ошибка маршрутизации callback: %v", err)
+	}
+}
 
-	case data == "recommendations_general":
-		handleRecommendationsGeneral(bot, callback)
+// handleRetakeAnketa начинает анкету заново, сбрасывая незавершенную сессию
+func handleRetakeAnketa(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
 
-	case data == "my_products":
-		handleMyProducts(bot, callback)
+	// Сбрасываем незавершенную анкету при начале заново
+	deleteFormSession(ctx, chatID)
+	handleStartFormNew(ctx, bot, callback)
+}
 
-	case data == "delete_products":
-		handleDeleteProducts(bot, callback)
+// handleStartFormNew начинает заполнение анкеты с первого шага
+func handleStartFormNew(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
 
-	case data == "delete_anketa":
-		handleDeleteAnketa(bot, callback)
+	// Инициализируем новое состояние для анкеты
+	newState := &models.UserState{}
+	skincareForm.Start(newState)
+	saveUserState(ctx, chatID, newState)
+	ShowSkincareFormStep(ctx, bot, chatID, newState)
+}
 
-	case data == "retake_anketa":
-		// Очищаем локальное состояние при начале анкеты заново
-		delete(userStates, chatID)
-		// Инициализируем новое состояние для анкеты
-		newState := &models.UserState{Step: 1}
-		saveUserState(chatID, newState)
-		ShowSkincareFormStep(bot, chatID, 1)
+// showResumeForm предлагает продолжить анкету с сохраненного шага или начать заново
+func showResumeForm(ctx context.Context, bot *tgbotapi.BotAPI, chatID int64, state *models.UserState) {
+	loc := i18n.FromContext(ctx)
+	msg := tgbotapi.NewMessage(chatID, loc.T("form.resume", state.Step))
 
-	case data == "start_form_new":
-		// Инициализируем новое состояние для анкеты
-		newState := &models.UserState{Step: 1}
-		saveUserState(chatID, newState)
-		ShowSkincareFormStep(bot, chatID, 1)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("form.resume.continue"), routeAnketaResume.Data()),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("form.resume.restart"), routeAnketaRetake.Data()),
+		),
+	)
+	msg.ReplyMarkup = keyboard
+	bot.Send(msg)
+}
 
-	case data == "back_to_start":
-		handleBackToStart(bot, callback)
+// handleResumeForm показывает шаг, на котором пользователь остановился
+func handleResumeForm(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
 
-	default:
-		log.Printf("Неизвестный callback: %s", data)
+	state, ok := getFormSession(ctx, chatID)
+	if !ok || state.Step == 0 {
+		// Сессия истекла между показом кнопки и нажатием
+		handleStartFormNew(ctx, bot, callback)
+		return
 	}
+
+	// Продлеваем сессию, раз пользователь вернулся к анкете
+	saveUserState(ctx, chatID, state)
+	ShowSkincareFormStep(ctx, bot, chatID, state)
 }
 
 // handleFormCallback обрабатывает ответы на форму
-func handleFormCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+func handleFormCallback(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
 	chatID := callback.Message.Chat.ID
-	data := callback.Data
+	data := params.String("code")
 
 	log.Printf("Обработка callback для пользователя %d: %s", chatID, data)
 
 	// Получаем текущее состояние пользователя (с fallback)
-	state := getUserState(chatID)
+	state := getUserState(ctx, chatID)
 	log.Printf("Текущее состояние пользователя %d: шаг %d", chatID, state.Step)
 
-	// Обновляем состояние в зависимости от ответа
-	switch {
-	case strings.HasPrefix(data, "skin_"):
-		state.SkinType = data
-		state.Step = 2
-		log.Printf("Пользователь %d выбрал тип кожи: %s, переходим к шагу 2", chatID, data)
-	case strings.HasPrefix(data, "age_"):
-		state.Age = data
-		state.Step = 3
-		log.Printf("Пользователь %d выбрал возраст: %s, переходим к шагу 3", chatID, data)
-	case strings.HasPrefix(data, "gender_"):
-		state.Gender = data
-		// Если выбран мужской пол, автоматически пропускаем вопрос о беременности
-		if data == "gender_male" {
-			state.Pregnancy = "none_of_above"
-			state.Step = 5 // переходим сразу к вопросу о проблемах
-			log.Printf("Пользователь %d выбрал пол: %s, автоматически пропускаем беременность, переходим к шагу 5", chatID, data)
-		} else {
-			state.Step = 4 // переходим к вопросу о беременности
-			log.Printf("Пользователь %d выбрал пол: %s, переходим к шагу 4", chatID, data)
+	// Движок анкеты проверяет, что ответ относится к текущему вопросу,
+	// и переходит к следующему с учетом правил пропуска
+	if err := skincareForm.Answer(state, data); err != nil {
+		log.Printf("Ответ %s пользователя %d не принят: %v", data, chatID, err)
+		// Кнопка из устаревшего сообщения: сообщение уже удалено, повторяем текущий вопрос
+		if errors.Is(err, questionnaire.ErrUnknownOption) || errors.Is(err, questionnaire.ErrUnexpectedInput) {
+			ShowSkincareFormStep(ctx, bot, chatID, state)
 		}
-	case strings.HasPrefix(data, "pregnancy_"):
-		state.Pregnancy = data
-		state.Step = 5
-		log.Printf("Пользователь %d выбрал беременность: %s, переходим к шагу 5", chatID, data)
-	case strings.HasPrefix(data, "goal_"):
-		state.Goal = data
-		state.Step = 7
-		log.Printf("Пользователь %d выбрал цель: %s, переходим к шагу 7", chatID, data)
-	case strings.HasPrefix(data, "climate_"):
-		state.Climate = data
-		state.Step = 8
-		log.Printf("Пользователь %d выбрал климат: %s, переходим к шагу 8", chatID, data)
-	case strings.HasPrefix(data, "fitzpatrick_"):
-		state.Fitzpatrick = data
-		state.Step = 9
-		log.Printf("Пользователь %d выбрал тип кожи по Фицпатрику: %s, переходим к шагу 9", chatID, data)
-	case strings.HasPrefix(data, "lifestyle_"):
-		state.Lifestyle = data
-		state.Step = 10
-		log.Printf("Пользователь %d выбрал образ жизни: %s, переходим к шагу 10", chatID, data)
-	case strings.HasPrefix(data, "diet_"):
-		state.Diet = data
-		state.Step = 11
-		log.Printf("Пользователь %d выбрал питание: %s, переходим к шагу 11", chatID, data)
-	case strings.HasPrefix(data, "allergies_"):
-		state.Allergies = data
-		state.Step = 12
-		log.Printf("Пользователь %d выбрал аллергии: %s, завершаем форму", chatID, data)
-		// Форма завершена, показываем результаты
-		showFormResults(bot, callback.Message, state)
 		return
-	default:
-		log.Printf("Неизвестный callback data: %s", data)
+	}
+	log.Printf("Пользователь %d ответил %s, переходим к шагу %d", chatID, data, state.Step)
+
+	if skincareForm.Done(state) {
+		// Форма завершена, показываем результаты
+		finishForm(ctx, bot, callback.Message, state)
 		return
 	}
 
 	// Сохраняем состояние (с fallback)
-	saveUserState(chatID, state)
+	saveUserState(ctx, chatID, state)
 	log.Printf("Состояние пользователя %d сохранено: шаг %d", chatID, state.Step)
 
 	// Показываем следующий шаг
-	ShowSkincareFormStep(bot, chatID, state.Step)
+	ShowSkincareFormStep(ctx, bot, chatID, state)
+}
+
+// handleFormToggle отмечает или снимает вариант в вопросе с несколькими ответами,
+// обновляя клавиатуру в том же сообщении
+func handleFormToggle(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
+	chatID := callback.Message.Chat.ID
+	code := params.String("code")
+
+	state := getUserState(ctx, chatID)
+	question, _ := skincareForm.Step(state.Step)
+
+	err := skincareForm.Toggle(state, code)
+	if errors.Is(err, questionnaire.ErrTooManySelected) {
+		bot.Request(tgbotapi.NewCallback(callback.ID, i18n.FromContext(ctx).N("form.too_many", question.MaxSelected)))
+		return
+	}
+	if err != nil {
+		log.Printf("Выбор %s пользователя %d не принят: %v", code, chatID, err)
+		replaceStaleFormMessage(ctx, bot, callback, state)
+		return
+	}
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+	saveUserState(ctx, chatID, state)
+
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, callback.Message.MessageID, questionKeyboard(i18n.FromContext(ctx), question, state))
+	if _, err := bot.Send(edit); err != nil {
+		log.Printf("Ошибка обновления клавиатуры анкеты: %v", err)
+	}
+}
+
+// handleFormDone подтверждает выбор в вопросе с несколькими ответами
+func handleFormDone(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+
+	state := getUserState(ctx, chatID)
+	question, _ := skincareForm.Step(state.Step)
+
+	err := skincareForm.Submit(state)
+	if errors.Is(err, questionnaire.ErrTooFewSelected) {
+		loc := i18n.FromContext(ctx)
+		text := loc.T("form.too_few.single")
+		if question.MinSelected > 1 {
+			text = loc.N("form.too_few", question.MinSelected)
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, text))
+		return
+	}
+	if err != nil {
+		log.Printf("Подтверждение выбора пользователя %d не принято: %v", chatID, err)
+		replaceStaleFormMessage(ctx, bot, callback, state)
+		return
+	}
+	log.Printf("Пользователь %d подтвердил выбор, переходим к шагу %d", chatID, state.Step)
+
+	deleteMessage(bot, chatID, callback.Message.MessageID)
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+	if skincareForm.Done(state) {
+		finishForm(ctx, bot, callback.Message, state)
+		return
+	}
+
+	saveUserState(ctx, chatID, state)
+	ShowSkincareFormStep(ctx, bot, chatID, state)
+}
+
+// handleFormBack возвращает к предыдущему вопросу анкеты
+func handleFormBack(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	state := getUserState(ctx, chatID)
+
+	// При изменении одного ответа возвращаемся к просмотру анкеты без изменений
+	if state.Editing {
+		deleteFormSession(ctx, chatID)
+		showProfile(ctx, bot, chatID)
+		return
+	}
+
+	if err := skincareForm.Back(state); err != nil {
+		log.Printf("Пользователь %d не может вернуться назад: %v", chatID, err)
+		if errors.Is(err, questionnaire.ErrNoPrevious) {
+			ShowSkincareFormStep(ctx, bot, chatID, state)
+		}
+		return
+	}
+	log.Printf("Пользователь %d вернулся к шагу %d", chatID, state.Step)
+
+	saveUserState(ctx, chatID, state)
+	ShowSkincareFormStep(ctx, bot, chatID, state)
+}
+
+// handleFormSkip пропускает текущий вопрос анкеты
+func handleFormSkip(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	state := getUserState(ctx, chatID)
+
+	if err := skincareForm.Skip(state); err != nil {
+		log.Printf("Пользователь %d не может пропустить вопрос: %v", chatID, err)
+		return
+	}
+	log.Printf("Пользователь %d пропустил вопрос, переходим к шагу %d", chatID, state.Step)
+
+	if skincareForm.Done(state) {
+		finishForm(ctx, bot, callback.Message, state)
+		return
+	}
+
+	saveUserState(ctx, chatID, state)
+	ShowSkincareFormStep(ctx, bot, chatID, state)
+}
+
+// replaceStaleFormMessage убирает сообщение с вопросом, который уже не актуален,
+// и повторяет текущий вопрос, если анкета еще заполняется
+func replaceStaleFormMessage(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, state *models.UserState) {
+	chatID := callback.Message.Chat.ID
+
+	deleteMessage(bot, chatID, callback.Message.MessageID)
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+	if _, ok := skincareForm.Step(state.Step); ok {
+		ShowSkincareFormStep(ctx, bot, chatID, state)
+	}
 }
 
 // handleFormInput обрабатывает текстовые ответы на форму
-func handleFormInput(bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
+func handleFormInput(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
 	text := message.Text
 
 	log.Printf("Обработка текстового ввода для пользователя %d: '%s'", chatID, text)
 
 	// Получаем текущее состояние пользователя (с fallback)
-	state := getUserState(chatID)
+	state := getUserState(ctx, chatID)
 	log.Printf("Текущее состояние пользователя %d: шаг %d, Concerns: '%s'", chatID, state.Step, state.Concerns)
 
 	// Проверяем, что мы действительно в процессе заполнения формы
@@ -508,114 +452,98 @@ func handleFormInput(bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
 		return
 	}
 
-	// Обрабатываем текстовые ответы
-	switch state.Step {
-	case 5:
-		state.Concerns = text
-		state.Step = 6
-		log.Printf("Пользователь %d ввел проблемы: %s, переходим к шагу 6", chatID, text)
-	default:
-		// Не ожидаем текстовый ввод на этом шаге
-		log.Printf("Пользователь %d отправил текст на шаге %d, но это не ожидается", chatID, state.Step)
+	// Обрабатываем текстовый ответ
+	if err := skincareForm.AnswerText(state, text); err != nil {
+		log.Printf("Текст пользователя %d на шаге %d не принят: %v", chatID, state.Step, err)
+		// Если текст на этом шаге вообще не ожидается, молча игнорируем его
+		if reply := freeTextErrorMessage(i18n.FromContext(ctx), err); reply != "" {
+			bot.Send(tgbotapi.NewMessage(chatID, reply))
+		}
+		return
+	}
+	log.Printf("Пользователь %d ответил текстом, переходим к шагу %d", chatID, state.Step)
+
+	if skincareForm.Done(state) {
+		finishForm(ctx, bot, message, state)
 		return
 	}
 
 	// Сохраняем состояние (с fallback)
-	saveUserState(chatID, state)
+	saveUserState(ctx, chatID, state)
 	log.Printf("Состояние пользователя %d сохранено: шаг %d", chatID, state.Step)
 
 	// Показываем следующий шаг
 	log.Printf("Показываем шаг %d для пользователя %d", state.Step, chatID)
-	ShowSkincareFormStep(bot, chatID, state.Step)
+	ShowSkincareFormStep(ctx, bot, chatID, state)
 }
 
 // showFormResults показывает результаты заполнения формы
-func showFormResults(bot *tgbotapi.BotAPI, message *tgbotapi.Message, state *models.UserState) {
+func showFormResults(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, state *models.UserState) {
 	chatID := message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	var resultText strings.Builder
+	resultText.WriteString(loc.T("form.results.header"))
+	for _, question := range skincareForm.Questions() {
+		answer := question.Display(loc, state)
+		if answer == "" {
+			answer = loc.T("form.results.empty")
+		}
+		resultText.WriteString(loc.T("form.results.line", question.Emoji, question.Title(loc), answer))
+	}
+	resultText.WriteString(loc.T("form.results.footer"))
 
-	resultText := fmt.Sprintf(`✅ Форма заполнена! Вот ваши данные:
-
-👤 Тип кожи: %s
-📅 Возраст: %s
-🚻 Пол: %s
-🤱 Беременность/лактация: %s
-💭 Проблемы: %s
-🎯 Цель: %s
-🌍 Климат: %s
-☀️ Тип кожи по Фицпатрику: %s
-🏃 Образ жизни: %s
-🥗 Питание: %s
-⚠️ Аллергии: %s
-
-Теперь я могу подобрать для вас подходящие средства!`,
-		convertToHumanReadable(state.SkinType),
-		convertToHumanReadable(state.Age),
-		convertToHumanReadable(state.Gender),
-		convertToHumanReadable(state.Pregnancy),
-		state.Concerns, // уже человекочитаемое (текстовый ввод)
-		convertToHumanReadable(state.Goal),
-		convertToHumanReadable(state.Climate),
-		convertToHumanReadable(state.Fitzpatrick),
-		convertToHumanReadable(state.Lifestyle),
-		convertToHumanReadable(state.Diet),
-		convertToHumanReadable(state.Allergies))
-
-	// Отправляем фото с результатами анкеты
-	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FilePath("images/12.png"))
-	photo.Caption = resultText
-	photo.ParseMode = "HTML"
-
-	// Создаем клавиатуру с кнопками
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+	// Создаем клавиатуру с кнопками: изменение отдельных ответов и действия с анкетой
+	rows := editAnswerRows(loc, state)
+	rows = append(rows,
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🗑️ Удалить анкету", "delete_anketa"),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("anketa.delete"), routeAnketaDelete.Data()),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🔄 Пройти заново", "retake_anketa"),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("anketa.retake"), routeAnketaRetake.Data()),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "back_to_start"),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("button.back"), routeStart.Data()),
 		),
 	)
-	photo.ReplyMarkup = keyboard
-	bot.Send(photo)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	// Отправляем фото с результатами анкеты
+	sendPhotoWithText(bot, chatID, tgbotapi.FilePath("images/12.png"), resultText.String(), &keyboard)
 
-	// Сохраняем финальное состояние анкеты в API
+	// Сохраняем заполненную анкету в API
 	log.Printf("Сохраняем финальное состояние анкеты пользователя %d", chatID)
-	saveUserState(chatID, state)
+	if err := saveUserProfile(ctx, chatID, state); err != nil {
+		log.Printf("Ошибка сохранения анкеты в API: %v", err)
+		// Сессию оставляем, чтобы ответы не потерялись
+		saveUserState(ctx, chatID, state)
+	}
 }
 
 // handleIncidecoderURL обрабатывает URL с Incidecoder
-func handleIncidecoderURL(bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
+func handleIncidecoderURL(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
+	loc := i18n.FromContext(ctx)
 
-	msg := tgbotapi.NewMessage(chatID, "Парсинг продукта с Incidecoder...")
+	msg := tgbotapi.NewMessage(chatID, loc.T("incidecoder.parsing"))
 	bot.Send(msg)
 
 	// TODO: Реализовать парсинг через API или создать отдельный сервис
 	// Пока что просто сообщаем, что функция в разработке
-	infoMsg := tgbotapi.NewMessage(chatID, "Функция парсинга продуктов с Incidecoder будет реализована в следующих версиях.")
+	infoMsg := tgbotapi.NewMessage(chatID, loc.T("incidecoder.todo"))
 	bot.Send(infoMsg)
 }
 
 // handleProductSelection обрабатывает выбор продукта
-func handleProductSelection(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+func handleProductSelection(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
 	chatID := callback.Message.Chat.ID
-	data := callback.Data
-
-	// Извлекаем ID продукта
-	productIDStr := strings.TrimPrefix(data, "product_")
-	productID, err := strconv.Atoi(productIDStr)
-	if err != nil {
-		errorMsg := tgbotapi.NewMessage(chatID, "Ошибка: неверный ID продукта")
-		bot.Send(errorMsg)
-		return
-	}
+	productID := params.Int("id")
+	loc := i18n.FromContext(ctx)
 
 	// Получаем детальную информацию о продукте через API
-	product, err := database.GetProduct(productID)
+	product, err := database.GetProduct(ctx, productID)
 	if err != nil {
-		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка получения продукта: %v", err))
+		errorMsg := tgbotapi.NewMessage(chatID, loc.T("product.load_failed", err))
 		bot.Send(errorMsg)
 		return
 	}
@@ -625,114 +553,106 @@ func handleProductSelection(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQue
 	productText.WriteString(fmt.Sprintf("🧴 <b>%s %s</b>\n\n", product.Brand, product.Title))
 
 	if product.Details != "" {
-		productText.WriteString(fmt.Sprintf("📝 <b>Описание:</b>\n%s\n\n", product.Details))
+		productText.WriteString(loc.T("product.description", product.Details))
 	}
 
 	if len(product.Ingredients) > 0 {
-		productText.WriteString("🧪 <b>Ингредиенты:</b>\n")
+		productText.WriteString(loc.T("product.ingredients"))
 		for i, ingredient := range product.Ingredients {
 			if i >= 10 { // Ограничиваем количество ингредиентов
-				productText.WriteString(fmt.Sprintf("... и еще %d ингредиентов", len(product.Ingredients)-10))
+				productText.WriteString(loc.N("product.more_ingredients", len(product.Ingredients)-10))
 				break
 			}
 			productText.WriteString(fmt.Sprintf("• %s\n", ingredient.Name))
 		}
 	}
 
+	// Для беременных и кормящих отмечаем ограниченные активы
+	writeSafetyFlags(&productText, loc, services.NewPregnancySafety(getUserState(ctx, chatID)).Check(product))
+
 	// Создаем клавиатуру с действиями
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("➕ Добавить в коллекцию", fmt.Sprintf("add_product_%d", product.ID)),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("product.add"), routeProductAdd.Data(product.ID)),
 		),
 	)
 
-	msg := tgbotapi.NewMessage(chatID, productText.String())
-	msg.ParseMode = "HTML"
-	msg.ReplyMarkup = keyboard
-	bot.Send(msg)
+	sendLongMessage(bot, chatID, productText.String(), &keyboard)
 }
 
 // handleAddProductToCollection обрабатывает добавление продукта в коллекцию пользователя
-func handleAddProductToCollection(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+func handleAddProductToCollection(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
 	chatID := callback.Message.Chat.ID
-	data := callback.Data
+	productID := params.Int("id")
 
-	// Извлекаем ID продукта
-	productIDStr := strings.TrimPrefix(data, "add_product_")
-	productID, err := strconv.Atoi(productIDStr)
-	if err != nil {
-		errorMsg := tgbotapi.NewMessage(chatID, "Ошибка: неверный ID продукта")
-		bot.Send(errorMsg)
-		return
-	}
+	loc := i18n.FromContext(ctx)
 
 	// Добавляем продукт в коллекцию пользователя через API
-	err = database.AddUserProduct(chatID, productID)
+	err := database.AddUserProduct(ctx, chatID, productID)
 	if err != nil {
-		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка добавления продукта: %v", err))
+		errorMsg := tgbotapi.NewMessage(chatID, loc.T("product.add_failed", err))
 		bot.Send(errorMsg)
 		return
 	}
 
-	successMsg := tgbotapi.NewMessage(chatID, "✅ Продукт успешно добавлен в вашу коллекцию!")
+	successMsg := tgbotapi.NewMessage(chatID, loc.T("product.added"))
 	bot.Send(successMsg)
+
+	// Проверяем состав по аллергиям и особенностям питания из анкеты
+	warnAboutProduct(ctx, bot, chatID, productID)
 }
 
 // handleRemoveProductFromCollection обрабатывает удаление продукта из коллекции пользователя
-func handleRemoveProductFromCollection(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+func handleRemoveProductFromCollection(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
 	chatID := callback.Message.Chat.ID
-	data := callback.Data
+	productID := params.Int("id")
 
-	// Извлекаем ID продукта
-	productIDStr := strings.TrimPrefix(data, "remove_product_")
-	productID, err := strconv.Atoi(productIDStr)
-	if err != nil {
-		errorMsg := tgbotapi.NewMessage(chatID, "❌ Ошибка: неверный ID продукта")
-		bot.Send(errorMsg)
-		return
-	}
+	loc := i18n.FromContext(ctx)
 
 	// Удаляем продукт из коллекции пользователя через API
-	err = database.RemoveUserProduct(chatID, productID)
+	err := database.RemoveUserProduct(ctx, chatID, productID)
 	if err != nil {
-		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Ошибка удаления продукта: %v", err))
+		errorMsg := tgbotapi.NewMessage(chatID, loc.T("product.remove_failed", err))
 		bot.Send(errorMsg)
 		return
 	}
 
-	successMsg := tgbotapi.NewMessage(chatID, "✅ Продукт успешно удален из вашей коллекции!")
+	successMsg := tgbotapi.NewMessage(chatID, loc.T("product.removed"))
 	bot.Send(successMsg)
 
+	// Продукта больше нет в коллекции — убираем его и из ухода
+	removeFromRoutine(ctx, chatID, productID)
+
 	// Показываем обновленный список продуктов
-	handleMyProducts(bot, callback)
+	handleMyProducts(ctx, bot, callback)
 }
 
 // handleRecommendations обрабатывает запрос рекомендаций
-func handleRecommendations(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+func handleRecommendations(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
 	chatID := callback.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
 
 	// Отправляем фото с подписью
 	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FilePath("images/04.png"))
-	photo.Caption = `🤖 <b>Рекомендации</b>
-
-Мы можем предложить тебе советы по уходу на основе анкеты, твоих текущих продуктов, или подсказать, чего не хватает в твоем уходе.
-
-<i>Данные рекомендации только для ознакомления и не заменяют консультацию дерматолога и не ставят точные диагнозы.</i>`
+	photo.Caption = loc.T("recs.caption")
 	photo.ParseMode = "HTML"
 
 	// Создаем клавиатуру с кнопками рекомендаций
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📊 Рекомендации на основе анкеты", "recommendations_anketa"),
+			tgbotapi.NewInlineKeyboardButtonData(recommendationTitle(loc, services.RecommendationAnketa), routeRecommendationsAnketa.Data()),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(recommendationTitle(loc, services.RecommendationProducts), routeRecommendationsProducts.Data()),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🧴 Рекомендации с учётом моих продуктов", "recommendations_products"),
+			tgbotapi.NewInlineKeyboardButtonData(recommendationTitle(loc, services.RecommendationGeneral), routeRecommendationsGeneral.Data()),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🧩 Общие рекомендации", "recommendations_general"),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("recs.history"), routeHistory.Data(0)),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "back_to_start"),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("button.back"), routeStart.Data()),
 		),
 	)
 	photo.ReplyMarkup = keyboard
@@ -740,144 +660,187 @@ func handleRecommendations(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuer
 }
 
 // handleRecommendationsAnketa обрабатывает рекомендации на основе анкеты
-func handleRecommendationsAnketa(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
-	chatID := callback.Message.Chat.ID
+func handleRecommendationsAnketa(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	generateRecommendation(ctx, bot, callback.Message.Chat.ID, services.RecommendationAnketa,
+		recommendationService.GetAnketaRecommendations, routeRecommendationsAnketa)
+}
 
-	// Отправляем сообщение о загрузке
-	loadingMsg := tgbotapi.NewMessage(chatID, "🤖 Генерирую рекомендации на основе вашей анкеты...\n\n⏳ Это может занять до 2 минут. Пожалуйста, подождите...")
-	bot.Send(loadingMsg)
+// handleRecommendationsProducts обрабатывает рекомендации с учётом продуктов
+func handleRecommendationsProducts(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	generateRecommendation(ctx, bot, callback.Message.Chat.ID, services.RecommendationProducts,
+		recommendationService.GetProductsRecommendations, routeRecommendationsProducts)
+}
 
-	// Получаем рекомендации
-	recommendations, err := recommendationService.GetAnketaRecommendations(chatID)
-	if err != nil {
-		var errorText string
-		if strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "deadline exceeded") {
-			errorText = "⏰ Время ожидания истекло. Нейросеть работает медленно. Попробуйте еще раз через несколько минут."
-		} else if strings.Contains(err.Error(), "401") {
-			errorText = "🔑 Ошибка аутентификации. Проверьте настройки API."
-		} else {
-			errorText = fmt.Sprintf("❌ Ошибка получения рекомендаций: %v", err)
-		}
-		errorMsg := tgbotapi.NewMessage(chatID, errorText)
+// handleRecommendationsGeneral обрабатывает общие рекомендации
+func handleRecommendationsGeneral(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	generateRecommendation(ctx, bot, callback.Message.Chat.ID, services.RecommendationGeneral,
+		recommendationService.GetGeneralRecommendations, routeRecommendationsGeneral)
+}
 
-		// Добавляем кнопку "Попробовать снова" для ошибок таймаута
-		if strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "deadline exceeded") {
-			keyboard := tgbotapi.NewInlineKeyboardMarkup(
-				tgbotapi.NewInlineKeyboardRow(
-					tgbotapi.NewInlineKeyboardButtonData("🔄 Попробовать снова", "recommendations_anketa"),
-				),
+// generateRecommendation запускает генерацию рекомендации вне очереди чата.
+// Если такая генерация у пользователя уже идет, новая не запускается:
+// пользователь получает сообщение «уже генерирую», которое исчезает,
+// когда готов ответ в первом сообщении.
+func generateRecommendation(ctx context.Context, bot *tgbotapi.BotAPI, chatID int64, kind services.RecommendationType,
+	generate func(ctx context.Context, userID int64, onProgress services.ProgressFunc) (string, error), retryRoute Route) {
+	loc := i18n.FromContext(ctx)
+
+	running, started := generations.Start(ctx, generationKey{userID: chatID, kind: kind}, func(ctx context.Context) {
+		// Генерируем рекомендации, показывая текст по мере поступления
+		err := streamRecommendation(ctx, bot, chatID, kind, generate)
+		switch {
+		case err == nil:
+		case cancelledByUser(ctx):
+			log.Printf("Генерация рекомендаций %s пользователя %d отменена", kind, chatID)
+			msg := tgbotapi.NewMessage(chatID, loc.T("recs.cancelled"))
+			msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
 				tgbotapi.NewInlineKeyboardRow(
-					tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад к рекомендациям", "recommendations"),
+					tgbotapi.NewInlineKeyboardButtonData(loc.T("recs.retry"), retryRoute.Data()),
 				),
-			)
-			errorMsg.ReplyMarkup = keyboard
-		} else {
-			keyboard := tgbotapi.NewInlineKeyboardMarkup(
 				tgbotapi.NewInlineKeyboardRow(
-					tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад к рекомендациям", "recommendations"),
+					tgbotapi.NewInlineKeyboardButtonData(loc.T("recs.back"), routeRecommendations.Data()),
 				),
 			)
-			errorMsg.ReplyMarkup = keyboard
+			bot.Send(msg)
+		default:
+			sendRecommendationError(ctx, bot, chatID, err, retryRoute)
 		}
-
-		bot.Send(errorMsg)
+	})
+	if started {
 		return
 	}
 
-	// Отправляем рекомендации с красивым форматированием
-	formattedRecommendations := formatRecommendationForTelegram(recommendations)
-	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("📊 <b>Рекомендации на основе анкеты</b>\n\n%s", formattedRecommendations))
-	msg.ParseMode = "HTML"
+	log.Printf("Рекомендации %s для пользователя %d уже генерируются, ждем их", kind, chatID)
+	msg := tgbotapi.NewMessage(chatID, loc.T("recs.in_progress"))
+	msg.ReplyMarkup = recommendationProgressKeyboard(loc, kind)
+	sent, err := bot.Send(msg)
+	if err != nil {
+		return
+	}
+	generations.Attach(running, func() {
+		deleteMessage(bot, chatID, sent.MessageID)
+	})
+}
 
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+// recommendationProgressKeyboard кнопки под сообщением о генерации
+func recommendationProgressKeyboard(loc *i18n.Localizer, kind services.RecommendationType) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад к рекомендациям", "recommendations"),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("recs.cancel"), routeRecommendationsCancel.Data(string(kind))),
 		),
 	)
-	msg.ReplyMarkup = keyboard
+}
 
-	// Если отправка с HTML не удалась, отправляем без форматирования
-	if _, err := bot.Send(msg); err != nil {
-		log.Printf("Ошибка отправки с HTML форматированием: %v", err)
-		// Отправляем без форматирования
-		plainMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("📊 Рекомендации на основе анкеты\n\n%s", recommendations))
-		plainMsg.ReplyMarkup = keyboard
-		bot.Send(plainMsg)
+// handleRecommendationsCancel отменяет идущую генерацию рекомендаций. Сообщение
+// с кнопкой не удаляется: его обновит сама прерванная генерация.
+func handleRecommendationsCancel(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
+	loc := i18n.FromContext(ctx)
+	key := generationKey{
+		userID: callback.Message.Chat.ID,
+		kind:   services.RecommendationType(params.String("kind")),
 	}
-}
 
-// handleRecommendationsProducts обрабатывает рекомендации с учётом продуктов
-func handleRecommendationsProducts(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
-	chatID := callback.Message.Chat.ID
+	answer := loc.T("recs.cancel.done")
+	if !generations.Cancel(key) {
+		answer = loc.T("recs.cancel.nothing")
+		// Генерация уже закончилась: кнопка отмены больше не нужна
+		edit := tgbotapi.NewEditMessageReplyMarkup(key.userID, callback.Message.MessageID,
+			tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}})
+		bot.Request(edit)
+	}
+	bot.Request(tgbotapi.NewCallback(callback.ID, answer))
+}
 
-	// Отправляем сообщение о загрузке
-	loadingMsg := tgbotapi.NewMessage(chatID, "🤖 Генерирую рекомендации с учётом ваших продуктов...\n\n⏳ Это может занять до 2 минут. Пожалуйста, подождите...")
-	bot.Send(loadingMsg)
+// sendRecommendationError сообщает пользователю о неудачной генерации.
+// Для временных ошибок добавляет кнопку повторной попытки.
+func sendRecommendationError(ctx context.Context, bot *tgbotapi.BotAPI, chatID int64, err error, retryRoute Route) {
+	log.Printf("Ошибка получения рекомендаций для пользователя %d: %v", chatID, err)
+	loc := i18n.FromContext(ctx)
 
-	// Получаем рекомендации
-	recommendations, err := recommendationService.GetProductsRecommendations(chatID)
-	if err != nil {
-		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Ошибка получения рекомендаций: %v", err))
-		bot.Send(errorMsg)
-		return
+	var errorKey string
+	retryable := true
+	switch {
+	case errors.Is(err, api.ErrTimeout) || errors.Is(err, context.DeadlineExceeded):
+		errorKey = "recs.error.timeout"
+	case errors.Is(err, api.ErrRateLimited):
+		errorKey = "recs.error.rate_limited"
+	case errors.Is(err, api.ErrOverloaded):
+		errorKey = "recs.error.overloaded"
+	case errors.Is(err, api.ErrUnauthorized):
+		errorKey = "recs.error.unauthorized"
+		retryable = false
+	case errors.Is(err, routine.ErrInvalid):
+		errorKey = "recs.error.malformed"
+	case errors.Is(err, api.ErrBadRequest):
+		errorKey = "recs.error.bad_request"
+		retryable = false
+	default:
+		errorKey = "recs.error.unknown"
 	}
 
-	// Отправляем рекомендации с красивым форматированием
-	formattedRecommendations := formatRecommendationForTelegram(recommendations)
-	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("🧴 <b>Рекомендации с учётом моих продуктов</b>\n\n%s", formattedRecommendations))
-	msg.ParseMode = "HTML"
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if retryable {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("recs.retry"), retryRoute.Data()),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(loc.T("recs.back"), routeRecommendations.Data()),
+	))
 
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад к рекомендациям", "recommendations"),
-		),
-	)
-	msg.ReplyMarkup = keyboard
-	bot.Send(msg)
+	errorMsg := tgbotapi.NewMessage(chatID, loc.T(errorKey))
+	errorMsg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	bot.Send(errorMsg)
 }
 
-// handleRecommendationsGeneral обрабатывает общие рекомендации
-func handleRecommendationsGeneral(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
-	chatID := callback.Message.Chat.ID
-
-	// Отправляем сообщение о загрузке
-	loadingMsg := tgbotapi.NewMessage(chatID, "🤖 Генерирую общие рекомендации...\n\n⏳ Это может занять до 2 минут. Пожалуйста, подождите...")
-	bot.Send(loadingMsg)
-
-	// Получаем рекомендации
-	recommendations, err := recommendationService.GetGeneralRecommendations(chatID)
+// streamRecommendation генерирует рекомендацию, редактируя одно сообщение по мере
+// поступления текста, и в конце показывает ее с полным форматированием.
+// Заголовок и текст ожидания берутся из каталога по ключам recs.<тип>.title и .progress.
+func streamRecommendation(ctx context.Context, bot *tgbotapi.BotAPI, chatID int64, kind services.RecommendationType,
+	generate func(ctx context.Context, userID int64, onProgress services.ProgressFunc) (string, error)) error {
+	loc := i18n.FromContext(ctx)
+	emoji, title := recommendationEmoji[kind], loc.T("recs."+string(kind)+".title")
+	progressKeyboard := recommendationProgressKeyboard(loc, kind)
+	stream := newStreamMessage(bot, chatID, loc.T("recs."+string(kind)+".progress"), fmt.Sprintf("%s %s", emoji, title), &progressKeyboard)
+
+	recommendations, err := generate(ctx, chatID, stream.Update)
 	if err != nil {
-		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Ошибка получения рекомендаций: %v", err))
-		bot.Send(errorMsg)
-		return
+		stream.Stop()
+		return err
 	}
 
-	// Отправляем рекомендации с красивым форматированием
-	formattedRecommendations := formatRecommendationForTelegram(recommendations)
-	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("🧩 <b>Общие рекомендации</b>\n\n%s", formattedRecommendations))
-	msg.ParseMode = "HTML"
-
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад к рекомендациям", "recommendations"),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("recs.regenerate"), routeRecommendationsFresh.Data(string(kind))),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("recs.back"), routeRecommendations.Data()),
 		),
 	)
-	msg.ReplyMarkup = keyboard
-	bot.Send(msg)
+
+	stream.Finish(fmt.Sprintf("%s <b>%s</b>\n\n%s", emoji, title, markdown.ToTelegramHTML(recommendations)), keyboard)
+	return nil
+}
+
+// recommendationTitle название типа рекомендаций со значком
+func recommendationTitle(loc *i18n.Localizer, kind services.RecommendationType) string {
+	return recommendationEmoji[kind] + " " + loc.T("recs."+string(kind)+".title")
 }
 
 // handleMyProducts обрабатывает запрос на просмотр продуктов пользователя
-func handleMyProducts(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+func handleMyProducts(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
 	chatID := callback.Message.Chat.ID
 
+	loc := i18n.FromContext(ctx)
+
 	// Отправляем сообщение о загрузке
-	loadingMsg := tgbotapi.NewMessage(chatID, "🔄 Загружаю ваши продукты...")
+	loadingMsg := tgbotapi.NewMessage(chatID, loc.T("products.loading"))
 	bot.Send(loadingMsg)
 
 	// Получаем продукты пользователя через API
-	products, err := database.GetUserProducts(chatID)
+	products, err := database.GetUserProducts(ctx, chatID)
 	if err != nil {
-		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Ошибка получения ваших продуктов: %v", err))
+		errorMsg := tgbotapi.NewMessage(chatID, loc.T("products.load_failed", err))
 		bot.Send(errorMsg)
 		return
 	}
@@ -885,21 +848,13 @@ func handleMyProducts(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
 	if len(products) == 0 {
 		// Отправляем фото с сообщением об отсутствии продуктов
 		photo := tgbotapi.NewPhoto(chatID, tgbotapi.FilePath("images/08.png"))
-		photo.Caption = `🧴 <b>Ваша коллекция пуста</b>
-
-У вас пока нет добавленных продуктов в коллекцию.
-
-<b>Для поиска продуктов введите:</b>
-@cosmetics_lab_ai_bot add [продукт который хотите найти]
-
-<b>Пример:</b>
-@cosmetics_lab_ai_bot add Repair Sunscreen SPF 50`
+		photo.Caption = loc.T("products.empty")
 		photo.ParseMode = "HTML"
 
 		// Добавляем только кнопку "Назад"
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "back_to_start"),
+				tgbotapi.NewInlineKeyboardButtonData(loc.T("button.back"), routeStart.Data()),
 			),
 		)
 		photo.ReplyMarkup = keyboard
@@ -909,13 +864,13 @@ func handleMyProducts(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
 
 	// Формируем красивое сообщение со списком продуктов
 	var productsText strings.Builder
-	productsText.WriteString(fmt.Sprintf("🧴 <b>Ваша коллекция (%d продуктов)</b>\n\n", len(products)))
-	productsText.WriteString("💡 <b>Для добавления новых продуктов введите:</b>\n@cosmetics_lab_ai_bot add [название продукта]\n\n")
+	productsText.WriteString(loc.N("products.header", len(products)))
+	productsText.WriteString(loc.T("products.add_hint"))
 
 	// Показываем первые 10 продуктов с подробной информацией
 	for i, product := range products {
 		if i >= 10 {
-			productsText.WriteString(fmt.Sprintf("... и еще %d продуктов\n", len(products)-10))
+			productsText.WriteString(loc.N("products.more", len(products)-10))
 			break
 		}
 
@@ -924,51 +879,52 @@ func handleMyProducts(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
 		// Добавляем описание, если есть
 		if product.Details != "" {
 			// Обрезаем описание если оно слишком длинное
-			details := product.Details
-			if len(details) > 100 {
-				details = details[:97] + "..."
-			}
+			details := truncateTail(product.Details, 100)
 			productsText.WriteString(fmt.Sprintf("   📝 %s\n", details))
 		}
 
 		// Добавляем дату добавления
 		if product.AddedAt != "" {
-			productsText.WriteString(fmt.Sprintf("   📅 Добавлено: %s\n", product.AddedAt))
+			productsText.WriteString(loc.T("products.added_at", product.AddedAt))
 		}
 
 		productsText.WriteString("\n")
 	}
 
-	// Отправляем фото с подписью
-	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FilePath("images/02.png"))
-	photo.Caption = productsText.String()
-	photo.ParseMode = "HTML"
-
 	// Создаем клавиатуру с действиями
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🗑️ Удалить продукты", "delete_products"),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("screening.check"), routeCheckProducts.Data()),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "back_to_start"),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("conflicts.check"), routeProductConflicts.Data()),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("products.delete"), routeDeleteProducts.Data()),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("button.back"), routeStart.Data()),
 		),
 	)
-	photo.ReplyMarkup = keyboard
-	bot.Send(photo)
+
+	// Отправляем фото с подписью; длинный список продолжается следующими сообщениями
+	sendPhotoWithText(bot, chatID, tgbotapi.FilePath("images/02.png"), productsText.String(), &keyboard)
 }
 
 // handleMyProductsCommand обрабатывает команду /myproducts
-func handleMyProductsCommand(bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
+func handleMyProductsCommand(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
 
+	loc := i18n.FromContext(ctx)
+
 	// Отправляем сообщение о загрузке
-	loadingMsg := tgbotapi.NewMessage(chatID, "🔄 Загружаю ваши продукты...")
+	loadingMsg := tgbotapi.NewMessage(chatID, loc.T("products.loading"))
 	bot.Send(loadingMsg)
 
 	// Получаем продукты пользователя через API
-	products, err := database.GetUserProducts(chatID)
+	products, err := database.GetUserProducts(ctx, chatID)
 	if err != nil {
-		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Ошибка получения ваших продуктов: %v", err))
+		errorMsg := tgbotapi.NewMessage(chatID, loc.T("products.load_failed", err))
 		bot.Send(errorMsg)
 		return
 	}
@@ -976,15 +932,7 @@ func handleMyProductsCommand(bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
 	if len(products) == 0 {
 		// Отправляем фото с сообщением об отсутствии продуктов
 		photo := tgbotapi.NewPhoto(chatID, tgbotapi.FilePath("images/08.png"))
-		photo.Caption = `🧴 <b>Ваша коллекция пуста</b>
-
-У вас пока нет добавленных продуктов в коллекцию.
-
-<b>Для поиска продуктов введите:</b>
-@cosmetics_lab_ai_bot add [продукт который хотите найти]
-
-<b>Пример:</b>
-@cosmetics_lab_ai_bot add Repair Sunscreen SPF 50`
+		photo.Caption = loc.T("products.empty")
 		photo.ParseMode = "HTML"
 		bot.Send(photo)
 		return
@@ -992,13 +940,13 @@ func handleMyProductsCommand(bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
 
 	// Формируем красивое сообщение со списком продуктов
 	var productsText strings.Builder
-	productsText.WriteString(fmt.Sprintf("🧴 <b>Ваша коллекция (%d продуктов)</b>\n\n", len(products)))
-	productsText.WriteString("💡 <b>Для добавления новых продуктов введите:</b>\n@cosmetics_lab_ai_bot add [название продукта]\n\n")
+	productsText.WriteString(loc.N("products.header", len(products)))
+	productsText.WriteString(loc.T("products.add_hint"))
 
 	// Показываем первые 10 продуктов с подробной информацией
 	for i, product := range products {
 		if i >= 10 {
-			productsText.WriteString(fmt.Sprintf("... и еще %d продуктов\n", len(products)-10))
+			productsText.WriteString(loc.N("products.more", len(products)-10))
 			break
 		}
 
@@ -1007,62 +955,62 @@ func handleMyProductsCommand(bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
 		// Добавляем описание, если есть
 		if product.Details != "" {
 			// Обрезаем описание если оно слишком длинное
-			details := product.Details
-			if len(details) > 100 {
-				details = details[:97] + "..."
-			}
+			details := truncateTail(product.Details, 100)
 			productsText.WriteString(fmt.Sprintf("   📝 %s\n", details))
 		}
 
 		// Добавляем дату добавления
 		if product.AddedAt != "" {
-			productsText.WriteString(fmt.Sprintf("   📅 Добавлено: %s\n", product.AddedAt))
+			productsText.WriteString(loc.T("products.added_at", product.AddedAt))
 		}
 
 		productsText.WriteString("\n")
 	}
 
-	// Отправляем фото с подписью
-	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FilePath("images/02.png"))
-	photo.Caption = productsText.String()
-	photo.ParseMode = "HTML"
-
 	// Создаем клавиатуру с действиями
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🗑️ Удалить продукты", "delete_products"),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("screening.check"), routeCheckProducts.Data()),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("conflicts.check"), routeProductConflicts.Data()),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("products.delete"), routeDeleteProducts.Data()),
 		),
 	)
-	photo.ReplyMarkup = keyboard
-	bot.Send(photo)
+
+	// Отправляем фото с подписью; длинный список продолжается следующими сообщениями
+	sendPhotoWithText(bot, chatID, tgbotapi.FilePath("images/02.png"), productsText.String(), &keyboard)
 }
 
 // handleDeleteProducts обрабатывает удаление продуктов из коллекции
-func handleDeleteProducts(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+func handleDeleteProducts(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
 	chatID := callback.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
 
 	// Получаем продукты пользователя через API
-	products, err := database.GetUserProducts(chatID)
+	products, err := database.GetUserProducts(ctx, chatID)
 	if err != nil {
-		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Ошибка получения ваших продуктов: %v", err))
+		errorMsg := tgbotapi.NewMessage(chatID, loc.T("products.load_failed", err))
 		bot.Send(errorMsg)
 		return
 	}
 
 	if len(products) == 0 {
-		msg := tgbotapi.NewMessage(chatID, "🧴 У вас нет продуктов для удаления.")
+		msg := tgbotapi.NewMessage(chatID, loc.T("products.delete.none"))
 		bot.Send(msg)
 		return
 	}
 
 	// Формируем сообщение со списком продуктов для удаления
 	var productsText strings.Builder
-	productsText.WriteString(fmt.Sprintf("🗑️ <b>Выберите продукты для удаления (%d продуктов):</b>\n\n", len(products)))
+	productsText.WriteString(loc.N("products.delete.header", len(products)))
 
 	// Показываем первые 10 продуктов
 	for i, product := range products {
 		if i >= 10 {
-			productsText.WriteString(fmt.Sprintf("... и еще %d продуктов\n", len(products)-10))
+			productsText.WriteString(loc.N("products.more", len(products)-10))
 			break
 		}
 
@@ -1080,14 +1028,14 @@ func handleDeleteProducts(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery
 		}
 		button := tgbotapi.NewInlineKeyboardButtonData(
 			fmt.Sprintf("🗑️ %s %s", product.Brand, product.Title),
-			fmt.Sprintf("remove_product_%d", product.ProductID),
+			routeProductRemove.Data(product.ProductID),
 		)
 		keyboard = append(keyboard, tgbotapi.NewInlineKeyboardRow(button))
 	}
 
 	// Добавляем кнопку "Назад"
 	keyboard = append(keyboard, tgbotapi.NewInlineKeyboardRow(
-		tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад к продуктам", "my_products"),
+		tgbotapi.NewInlineKeyboardButtonData(loc.T("products.back"), routeMyProducts.Data()),
 	))
 
 	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(keyboard...)
@@ -1095,28 +1043,21 @@ func handleDeleteProducts(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery
 }
 
 // handleAnketa обрабатывает кнопку "Анкета"
-func handleAnketa(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
-	chatID := callback.Message.Chat.ID
+func handleAnketa(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	showProfile(ctx, bot, callback.Message.Chat.ID)
+}
 
+// showProfile показывает сохраненную анкету с кнопками изменения ответов
+func showProfile(ctx context.Context, bot *tgbotapi.BotAPI, chatID int64) {
 	log.Printf("Проверяем анкету пользователя %d через API", chatID)
+	loc := i18n.FromContext(ctx)
 
 	// Получаем профиль пользователя через API
-	profile, err := database.GetUserProfile(chatID)
+	profile, err := database.GetUserProfile(ctx, chatID)
 	if err != nil {
 		log.Printf("Ошибка получения профиля пользователя %d: %v", chatID, err)
 		// Если профиль не найден, показываем кнопки для прохождения анкеты
-		msg := tgbotapi.NewMessage(chatID, "📋 У вас пока нет заполненной анкеты.\n\nЗаполните анкету, чтобы получить персонализированные рекомендации по уходу за кожей!")
-
-		keyboard := tgbotapi.NewInlineKeyboardMarkup(
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("📝 Пройти анкету", "start_form_new"),
-			),
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "back_to_start"),
-			),
-		)
-		msg.ReplyMarkup = keyboard
-		bot.Send(msg)
+		sendEmptyProfile(ctx, bot, chatID)
 		return
 	}
 
@@ -1124,109 +1065,161 @@ func handleAnketa(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
 	log.Printf("Получен профиль пользователя %d: SkinType='%s', Age='%s', Gender='%s', Pregnancy='%s', Concern='%s', Goal='%s', Climate='%s', Fitzpatrick='%s', Lifestyle='%s', Diet='%s', Allergy='%s'",
 		chatID, profile.SkinType, profile.Age, profile.Gender, profile.Pregnancy, profile.Concern, profile.Goal, profile.Climate, profile.Fitzpatrick, profile.Lifestyle, profile.Diet, profile.Allergy)
 
+	// Ответы показываем по кодам на языке пользователя, а не так, как их хранит API
+	state := database.StateFromProfile(profile)
+
 	// Проверяем, заполнена ли анкета (есть ли хотя бы одно поле)
-	if profile.SkinType == "" && profile.Age == "" && profile.Gender == "" &&
-		profile.Pregnancy == "" && profile.Concern == "" && profile.Goal == "" &&
-		profile.Climate == "" && profile.Fitzpatrick == "" && profile.Lifestyle == "" &&
-		profile.Diet == "" && profile.Allergy == "" {
+	var anketaText strings.Builder
+	for _, question := range skincareForm.Questions() {
+		answer := question.Labels(loc, state)
+		if answer == "" {
+			continue
+		}
+		anketaText.WriteString(loc.T("anketa.line", question.Emoji, question.Title(loc), withOther(answer, question.Other(state))))
+	}
+	if anketaText.Len() == 0 {
 		// Анкета пустая
 		log.Printf("Анкета пользователя %d пустая", chatID)
-		msg := tgbotapi.NewMessage(chatID, "📋 У вас пока нет заполненной анкеты.\n\nЗаполните анкету, чтобы получить персонализированные рекомендации по уходу за кожей!")
-
-		keyboard := tgbotapi.NewInlineKeyboardMarkup(
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("📝 Пройти анкету", "start_form_new"),
-			),
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "back_to_start"),
-			),
-		)
-		msg.ReplyMarkup = keyboard
-		bot.Send(msg)
+		sendEmptyProfile(ctx, bot, chatID)
 		return
 	}
 
 	// Анкета заполнена, показываем её содержимое
 	log.Printf("Анкета пользователя %d заполнена, отображаем содержимое", chatID)
-	var anketaText strings.Builder
-	anketaText.WriteString("📋 <b>Ваша анкета:</b>\n\n")
 
-	if profile.SkinType != "" {
-		anketaText.WriteString(fmt.Sprintf("👤 <b>Тип кожи:</b> %s\n", profile.SkinType))
-	}
-	if profile.Age != "" {
-		anketaText.WriteString(fmt.Sprintf("📅 <b>Возраст:</b> %s\n", profile.Age))
-	}
-	if profile.Gender != "" {
-		anketaText.WriteString(fmt.Sprintf("🚻 <b>Пол:</b> %s\n", profile.Gender))
-	}
-	if profile.Pregnancy != "" {
-		anketaText.WriteString(fmt.Sprintf("🤱 <b>Беременность/лактация:</b> %s\n", profile.Pregnancy))
-	}
-	if profile.Concern != "" {
-		anketaText.WriteString(fmt.Sprintf("💭 <b>Проблемы:</b> %s\n", profile.Concern))
-	}
-	if profile.Goal != "" {
-		anketaText.WriteString(fmt.Sprintf("🎯 <b>Цель:</b> %s\n", profile.Goal))
-	}
-	if profile.Climate != "" {
-		anketaText.WriteString(fmt.Sprintf("🌍 <b>Климат:</b> %s\n", profile.Climate))
-	}
-	if profile.Fitzpatrick != "" {
-		anketaText.WriteString(fmt.Sprintf("☀️ <b>Тип кожи по Фитцпатрику:</b> %s\n", profile.Fitzpatrick))
-	}
-	if profile.Lifestyle != "" {
-		anketaText.WriteString(fmt.Sprintf("🏃 <b>Образ жизни:</b> %s\n", profile.Lifestyle))
-	}
-	if profile.Diet != "" {
-		anketaText.WriteString(fmt.Sprintf("🥗 <b>Питание:</b> %s\n", profile.Diet))
-	}
-	if profile.Allergy != "" {
-		anketaText.WriteString(fmt.Sprintf("⚠️ <b>Аллергии:</b> %s\n", profile.Allergy))
-	}
+	// Создаем клавиатуру с действиями: сначала изменение отдельных ответов
+	rows := editAnswerRows(loc, state)
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("anketa.delete"), routeAnketaDelete.Data()),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("anketa.retake_full"), routeAnketaRetake.Data()),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("button.back"), routeStart.Data()),
+		),
+	)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
 
-	// Отправляем фото с подписью вместо текстового сообщения
-	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FilePath("images/12.png"))
-	photo.Caption = anketaText.String()
-	photo.ParseMode = "HTML"
+	// Отправляем фото с подписью вместо текстового сообщения; длинные ответы
+	// не помещаются в подпись и продолжаются следующим сообщением
+	sendPhotoWithText(bot, chatID, tgbotapi.FilePath("images/12.png"), loc.T("anketa.header")+anketaText.String(), &keyboard)
+}
+
+// sendEmptyProfile предлагает заполнить анкету, если ее еще нет
+func sendEmptyProfile(ctx context.Context, bot *tgbotapi.BotAPI, chatID int64) {
+	loc := i18n.FromContext(ctx)
+	msg := tgbotapi.NewMessage(chatID, loc.T("anketa.empty"))
 
-	// Создаем клавиатуру с действиями
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🗑️ Удалить анкету", "delete_anketa"),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("anketa.start"), routeAnketaNew.Data()),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🔄 Пройти анкету заново", "retake_anketa"),
-		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "back_to_start"),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("button.back"), routeStart.Data()),
 		),
 	)
-	photo.ReplyMarkup = keyboard
-	bot.Send(photo)
+	msg.ReplyMarkup = keyboard
+	bot.Send(msg)
+}
+
+// editAnswerRows создает кнопки изменения ответа для каждого вопроса анкеты.
+// Вопросы, пропускаемые при текущих ответах, не показываются.
+func editAnswerRows(loc *i18n.Localizer, state *models.UserState) [][]tgbotapi.InlineKeyboardButton {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for _, question := range skincareForm.Questions() {
+		if skincareForm.Skipped(&question, state) {
+			continue
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(loc.T("anketa.edit", question.Title(loc)), routeAnketaEdit.Data(question.ID)))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// handleEditAnswer задает один вопрос заполненной анкеты заново
+func handleEditAnswer(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
+	chatID := callback.Message.Chat.ID
+	questionID := params.String("question")
+
+	// Изменяем сохраненную анкету, а не незавершенную сессию. Без анкеты
+	// ответ не меняем: сохранение пустого состояния затерло бы остальные ответы.
+	profile, err := database.GetUserProfile(ctx, chatID)
+	if err != nil {
+		log.Printf("Ошибка получения анкеты пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, i18n.FromContext(ctx).T("anketa.load_failed")))
+		return
+	}
+	state := database.StateFromProfile(profile)
+
+	if err := skincareForm.Edit(state, questionID); err != nil {
+		log.Printf("Нельзя изменить ответ %s пользователя %d: %v", questionID, chatID, err)
+		showProfile(ctx, bot, chatID)
+		return
+	}
+	log.Printf("Пользователь %d меняет ответ на вопрос %s", chatID, questionID)
+
+	saveUserState(ctx, chatID, state)
+	ShowSkincareFormStep(ctx, bot, chatID, state)
+}
+
+// finishForm завершает анкету: после изменения одного ответа сохраняет профиль
+// и возвращает к его просмотру, после полного прохождения показывает итог
+func finishForm(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, state *models.UserState) {
+	if !state.Editing {
+		showFormResults(ctx, bot, message, state)
+		return
+	}
+
+	chatID := message.Chat.ID
+	state.Editing = false
+	if err := saveUserProfile(ctx, chatID, state); err != nil {
+		log.Printf("Ошибка сохранения измененной анкеты: %v", err)
+		bot.Send(tgbotapi.NewMessage(chatID, i18n.FromContext(ctx).T("anketa.save_failed")))
+		return
+	}
+	showProfile(ctx, bot, chatID)
+}
+
+// withOther добавляет к ответу уточнение, написанное пользователем к варианту «Другое».
+// Уточнение экранируется: это пользовательский текст внутри HTML-сообщения.
+func withOther(value, other string) string {
+	if other == "" {
+		return value
+	}
+	return fmt.Sprintf("%s (%s)", value, html.EscapeString(other))
 }
 
 // handleDeleteAnketa обрабатывает удаление анкеты
-func handleDeleteAnketa(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+func handleDeleteAnketa(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
 	chatID := callback.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
 
-	// Очищаем локальное состояние
-	delete(userStates, chatID)
+	// Очищаем незавершенную анкету
+	deleteFormSession(ctx, chatID)
 
 	// Очищаем профиль пользователя через API
-	err := database.EmptyUserProfile(chatID)
+	err := database.EmptyUserProfile(ctx, chatID)
 	if err != nil {
-		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка удаления анкеты: %v", err))
+		errorMsg := tgbotapi.NewMessage(chatID, loc.T("anketa.delete_failed", err))
 		bot.Send(errorMsg)
 		return
 	}
 
 	// Отправляем сообщение об успешном удалении с кнопкой "Назад"
-	msg := tgbotapi.NewMessage(chatID, "✅ Ваша анкета удалена!")
+	msg := tgbotapi.NewMessage(chatID, loc.T("anketa.deleted"))
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "back_to_start"),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("button.back"), routeStart.Data()),
 		),
 	)
 	msg.ReplyMarkup = keyboard
@@ -1234,68 +1227,42 @@ func handleDeleteAnketa(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery)
 }
 
 // handleBackToStart возвращает к главному меню
-func handleBackToStart(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
-	chatID := callback.Message.Chat.ID
+func handleBackToStart(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	sendMainMenu(ctx, bot, callback.Message.Chat.ID)
+}
+
+// sendMainMenu отправляет фото с приветственным сообщением и главным меню
+func sendMainMenu(ctx context.Context, bot *tgbotapi.BotAPI, chatID int64) {
+	loc := i18n.FromContext(ctx)
 
-	// Отправляем фото с приветственным сообщением (как в /start)
 	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FilePath("images/01.png"))
-	photo.Caption = `✨ Я — твой умный бьюти-бот, созданный, чтобы наконец навести порядок в косметичке. Этот бот - часть проекта Cos AI, созданного для того, чтобы помочь тебе собрать персонализированный уход за кожей.
-Хочешь попробовать? Давай начнем с небольшой анкеты 💬👇`
+	photo.Caption = loc.T("start.caption")
 	photo.ParseMode = "HTML"
 
 	// Создаем клавиатуру с кнопками
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📋 Анкета", "start_form"),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("menu.anketa"), routeAnketa.Data()),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("menu.recommendations"), routeRecommendations.Data()),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🤖 Рекомендации", "recommendations"),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("menu.products"), routeMyProducts.Data()),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🧴 Мои продукты", "my_products"),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("menu.routine"), routeRoutine.Data()),
 		),
 	)
 	photo.ReplyMarkup = keyboard
 	bot.Send(photo)
 }
 
-// getUserState получает состояние пользователя с fallback на локальное хранение
-func getUserState(chatID int64) *models.UserState {
-	// Сначала проверяем локальное состояние (для активной анкеты)
-	if localState, exists := userStates[chatID]; exists {
-		log.Printf("Используем локальное состояние для пользователя %d: шаг %d", chatID, localState.Step)
-		return localState
-	}
-
-	// Если локального состояния нет, пытаемся получить из API
-	state, err := database.GetUserState(chatID)
-	if err != nil {
-		log.Printf("Ошибка получения состояния из API, создаем новое: %v", err)
-		// Если API недоступен, создаем новое состояние
-		return &models.UserState{Step: 0}
-	}
-	log.Printf("Получено состояние из API для пользователя %d: шаг %d", chatID, state.Step)
-	return state
-}
-
-// saveUserState сохраняет состояние пользователя с fallback на локальное хранение
-func saveUserState(chatID int64, state *models.UserState) {
-	// Всегда сохраняем локально для активной анкеты
-	userStates[chatID] = state
-	log.Printf("Состояние пользователя %d сохранено локально: шаг %d", chatID, state.Step)
-
-	// Также пытаемся сохранить в API (для персистентности)
-	if err := database.SaveUserState(chatID, state); err != nil {
-		log.Printf("Ошибка сохранения в API: %v", err)
-	} else {
-		log.Printf("Состояние пользователя %d также сохранено в API: шаг %d", chatID, state.Step)
-	}
-}
-
 // handleInlineQuery обрабатывает inline запросы
-func handleInlineQuery(bot *tgbotapi.BotAPI, inlineQuery *tgbotapi.InlineQuery) {
+func handleInlineQuery(ctx context.Context, bot *tgbotapi.BotAPI, inlineQuery *tgbotapi.InlineQuery) {
 	query := inlineQuery.Query
 	userID := inlineQuery.From.ID
+	loc := i18n.FromContext(ctx)
 
 	log.Printf("[INLINE] Получен inline запрос от пользователя %d: '%s'", userID, query)
 	log.Printf("[INLINE] Детали запроса: ID=%s, Offset=%s", inlineQuery.ID, inlineQuery.Offset)
@@ -1309,10 +1276,10 @@ func handleInlineQuery(bot *tgbotapi.BotAPI, inlineQuery *tgbotapi.InlineQuery)
 		// Создаем результат с сообщением о коротком запросе
 		result := tgbotapi.NewInlineQueryResultArticle(
 			"too_short",
-			"⚠️ Запрос слишком короткий",
-			"Введите минимум 3 символа для поиска продуктов.",
+			loc.T("inline.too_short.title"),
+			loc.T("inline.too_short.text"),
 		)
-		result.Description = "Минимум 3 символа для поиска"
+		result.Description = loc.T("inline.too_short.description")
 
 		answerInlineQuery := tgbotapi.InlineConfig{
 			InlineQueryID: inlineQuery.ID,
@@ -1324,16 +1291,16 @@ func handleInlineQuery(bot *tgbotapi.BotAPI, inlineQuery *tgbotapi.InlineQuery)
 
 	// Выполняем поиск продуктов через API
 	log.Printf("[INLINE] Выполняем поиск продуктов для запроса: '%s'", query)
-	products, err := database.SearchProducts(query, 20, 0, nil, nil, nil, nil)
+	products, err := database.SearchProducts(ctx, query, 20, 0, nil, nil, nil, nil)
 	if err != nil {
 		log.Printf("[INLINE] Ошибка поиска продуктов для inline запроса: %v", err)
 		// Создаем результат с сообщением об ошибке
 		result := tgbotapi.NewInlineQueryResultArticle(
 			"error",
-			"❌ Ошибка поиска",
-			"Произошла ошибка при поиске продуктов. Попробуйте позже.",
+			loc.T("inline.error.title"),
+			loc.T("inline.error.text"),
 		)
-		result.Description = "Ошибка соединения с сервером"
+		result.Description = loc.T("inline.error.description")
 
 		answerInlineQuery := tgbotapi.InlineConfig{
 			InlineQueryID: inlineQuery.ID,
@@ -1352,10 +1319,10 @@ func handleInlineQuery(bot *tgbotapi.BotAPI, inlineQuery *tgbotapi.InlineQuery)
 		// Создаем inline результат с сообщением
 		result := tgbotapi.NewInlineQueryResultArticle(
 			"not_found",
-			"❌ Продукт не найден",
-			"По вашему запросу ничего не найдено. Попробуйте другой поисковый запрос.",
+			loc.T("inline.not_found.title"),
+			loc.T("inline.not_found.text"),
 		)
-		result.Description = fmt.Sprintf("По запросу '%s' ничего не найдено", query)
+		result.Description = loc.T("inline.not_found.description", query)
 
 		answerInlineQuery := tgbotapi.InlineConfig{
 			InlineQueryID: inlineQuery.ID,
@@ -1395,7 +1362,7 @@ func handleInlineQuery(bot *tgbotapi.BotAPI, inlineQuery *tgbotapi.InlineQuery)
 		result.ReplyMarkup = &tgbotapi.InlineKeyboardMarkup{
 			InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{
 				{
-					tgbotapi.NewInlineKeyboardButtonData("➕ Добавить в коллекцию", fmt.Sprintf("add_product_%d", product.ID)),
+					tgbotapi.NewInlineKeyboardButtonData(loc.T("product.add"), routeProductAdd.Data(product.ID)),
 				},
 			},
 		}
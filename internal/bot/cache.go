@@ -0,0 +1,66 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"cos-ai-bot/internal/llmcache"
+	"cos-ai-bot/internal/services"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// newLLMCacheStore создает кэш ответов модели: в базе, если она открыта,
+// иначе в памяти. При нулевом ttl кэш выключен и возвращается nil.
+func newLLMCacheStore(db *sql.DB, ttl time.Duration) llmcache.Store {
+	switch {
+	case ttl <= 0:
+		return nil
+	case db == nil:
+		return llmcache.NewMemoryStore(ttl)
+	default:
+		return llmcache.NewPostgresStore(db, ttl)
+	}
+}
+
+// logCacheStats периодически пишет в лог счетчики кэша ответов модели до отмены ctx.
+// Интервалы без запросов к модели не логируются.
+func logCacheStats(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var logged int64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats := llmcache.Snapshot()
+			if stats.Requests() == logged {
+				continue
+			}
+			logged = stats.Requests()
+			log.Printf("Кэш ответов модели: попаданий %d, промахов %d, в обход кэша %d, ошибок хранилища %d, доля попаданий %.0f%%",
+				stats.Hits, stats.Misses, stats.Bypasses, stats.Errors, stats.HitRatio()*100)
+		}
+	}
+}
+
+// handleRecommendationsFresh генерирует рекомендацию заново, не используя
+// ответ из кэша; новый ответ заменяет сохраненный
+func handleRecommendationsFresh(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
+	ctx = llmcache.WithBypass(ctx)
+
+	switch kind := services.RecommendationType(params.String("kind")); kind {
+	case services.RecommendationAnketa:
+		handleRecommendationsAnketa(ctx, bot, callback)
+	case services.RecommendationProducts:
+		handleRecommendationsProducts(ctx, bot, callback)
+	case services.RecommendationGeneral:
+		handleRecommendationsGeneral(ctx, bot, callback)
+	default:
+		log.Printf("Неизвестный тип рекомендаций для повторной генерации: %s", kind)
+	}
+}
@@ -0,0 +1,83 @@
+package bot
+
+import (
+	"context"
+	"html"
+	"log"
+	"strings"
+
+	"cos-ai-bot/internal/i18n"
+	"cos-ai-bot/internal/services"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleProductConflicts ищет в коллекции продукты с активами,
+// которые нельзя наносить в одно время
+func handleProductConflicts(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("products.back"), routeMyProducts.Data()),
+		),
+	)
+
+	bot.Send(tgbotapi.NewMessage(chatID, loc.T("conflicts.loading")))
+
+	analysis, err := services.AnalyzeCollection(ctx, chatID)
+	if err != nil {
+		log.Printf("Ошибка проверки совместимости продуктов пользователя %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, loc.T("conflicts.failed"))
+		msg.ReplyMarkup = keyboard
+		bot.Send(msg)
+		return
+	}
+
+	var text strings.Builder
+	text.WriteString(loc.T("conflicts.header"))
+	if len(analysis.Conflicts) == 0 {
+		text.WriteString(loc.T("conflicts.nothing_found"))
+	} else {
+		text.WriteString(loc.N("conflicts.found", len(analysis.Conflicts)))
+		for _, conflict := range analysis.Conflicts {
+			writeConflict(&text, loc, conflict)
+		}
+	}
+	if len(analysis.Unchecked) > 0 {
+		text.WriteString(loc.N("screening.unknown", len(analysis.Unchecked)))
+		for _, product := range analysis.Unchecked {
+			text.WriteString(loc.T("screening.unknown_product", html.EscapeString(product.Brand), html.EscapeString(product.Title)))
+		}
+	}
+	if analysis.Partial {
+		text.WriteString(loc.T("conflicts.partial"))
+	}
+	text.WriteString(loc.T("conflicts.disclaimer"))
+
+	sendLongMessage(bot, chatID, text.String(), &keyboard)
+}
+
+// writeConflict описывает пару конфликтующих продуктов, причину и как их развести
+func writeConflict(text *strings.Builder, loc *i18n.Localizer, conflict services.Conflict) {
+	product := func(active services.ProductActive) string {
+		return loc.T("conflicts.product",
+			html.EscapeString(active.Brand), html.EscapeString(active.Title),
+			loc.T("conflicts.class."+string(active.Class)),
+			html.EscapeString(strings.Join(active.Ingredients, ", ")))
+	}
+
+	text.WriteString(product(conflict.First))
+	text.WriteString(product(conflict.Second))
+	text.WriteString(loc.T("conflicts.reason", loc.T("conflicts.rule."+conflict.Rule)))
+	switch conflict.Advice {
+	case services.AdviceSplit:
+		text.WriteString(loc.T("conflicts.advice.split",
+			html.EscapeString(conflict.First.Title), loc.T("conflicts.slot."+string(conflict.FirstSlot)),
+			html.EscapeString(conflict.Second.Title), loc.T("conflicts.slot."+string(conflict.SecondSlot))))
+	case services.AdviceAlternate:
+		text.WriteString(loc.T("conflicts.advice.alternate"))
+	}
+	text.WriteString("\n")
+}
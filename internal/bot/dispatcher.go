@@ -0,0 +1,144 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// UpdateHandler обрабатывает одно обновление Telegram
+type UpdateHandler func(ctx context.Context, update tgbotapi.Update)
+
+// Dispatcher распределяет обновления между воркерами.
+// Обновления одного чата обрабатываются строго по очереди,
+// обновления разных чатов — параллельно, но не более workers одновременно.
+type Dispatcher struct {
+	ctx     context.Context // контекст, передаваемый обработчикам
+	handle  UpdateHandler
+	workers chan struct{} // семафор одновременно работающих обработчиков
+	slots   chan struct{} // места в очереди; при переполнении Dispatch блокируется
+
+	mu    sync.Mutex
+	chats map[int64][]tgbotapi.Update // очереди обновлений по чатам
+	wg    sync.WaitGroup
+}
+
+// NewDispatcher создает диспетчер с ограничением параллелизма и размера очереди.
+// ctx передается всем обработчикам; его отмена прерывает незавершенную работу.
+func NewDispatcher(ctx context.Context, handle UpdateHandler, workers, queueSize int) *Dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < workers {
+		queueSize = workers
+	}
+
+	return &Dispatcher{
+		ctx:     ctx,
+		handle:  handle,
+		workers: make(chan struct{}, workers),
+		slots:   make(chan struct{}, queueSize),
+		chats:   make(map[int64][]tgbotapi.Update),
+	}
+}
+
+// Dispatch ставит обновление в очередь его чата.
+// Если очередь заполнена, вызов блокируется до освобождения места или отмены ctx.
+func (d *Dispatcher) Dispatch(ctx context.Context, update tgbotapi.Update) error {
+	select {
+	case d.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	chatID := updateChatID(update)
+
+	d.mu.Lock()
+	queue, active := d.chats[chatID]
+	d.chats[chatID] = append(queue, update)
+	if !active {
+		d.wg.Add(1)
+	}
+	d.mu.Unlock()
+
+	if !active {
+		go d.drain(chatID)
+	}
+	return nil
+}
+
+// Wait ожидает обработки всех поставленных в очередь обновлений
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// WaitTimeout ожидает обработки очереди не дольше timeout.
+// Возвращает false, если время вышло раньше.
+func (d *Dispatcher) WaitTimeout(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// drain последовательно обрабатывает очередь одного чата
+func (d *Dispatcher) drain(chatID int64) {
+	defer d.wg.Done()
+
+	for {
+		d.mu.Lock()
+		queue := d.chats[chatID]
+		if len(queue) == 0 {
+			delete(d.chats, chatID)
+			d.mu.Unlock()
+			return
+		}
+		update := queue[0]
+		d.chats[chatID] = queue[1:]
+		d.mu.Unlock()
+
+		d.workers <- struct{}{}
+		d.process(update)
+		<-d.workers
+		<-d.slots
+	}
+}
+
+// process вызывает обработчик, не давая панике остановить воркер
+func (d *Dispatcher) process(update tgbotapi.Update) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Паника при обработке обновления %d: %v\n%s", update.UpdateID, r, debug.Stack())
+		}
+	}()
+
+	d.handle(d.ctx, update)
+}
+
+// updateChatID возвращает ключ, по которому упорядочиваются обновления
+func updateChatID(update tgbotapi.Update) int64 {
+	switch {
+	case update.Message != nil:
+		return update.Message.Chat.ID
+	case update.CallbackQuery != nil:
+		if update.CallbackQuery.Message != nil {
+			return update.CallbackQuery.Message.Chat.ID
+		}
+		return update.CallbackQuery.From.ID
+	case update.InlineQuery != nil:
+		return update.InlineQuery.From.ID
+	}
+	return 0
+}
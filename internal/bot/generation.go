@@ -0,0 +1,137 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"cos-ai-bot/internal/services"
+)
+
+// errGenerationCancelled причина отмены генерации по кнопке пользователя
+var errGenerationCancelled = errors.New("генерация отменена пользователем")
+
+// generationKey генерация одного типа рекомендаций для одного пользователя
+type generationKey struct {
+	userID int64
+	kind   services.RecommendationType
+}
+
+// generation выполняющаяся генерация
+type generation struct {
+	cancel context.CancelCauseFunc
+	done   chan struct{} // закрывается по завершении
+}
+
+// generationRegistry запускает генерации рекомендаций вне очереди чата, чтобы
+// пока модель отвечает, бот мог принять повторное нажатие или отмену. На каждую
+// пару пользователь и тип одновременно выполняется не больше одной генерации.
+type generationRegistry struct {
+	mu      sync.Mutex
+	running map[generationKey]*generation
+	wg      sync.WaitGroup
+}
+
+// generations выполняющиеся генерации рекомендаций
+var generations = newGenerationRegistry()
+
+// newGenerationRegistry создает пустой реестр генераций
+func newGenerationRegistry() *generationRegistry {
+	return &generationRegistry{running: make(map[generationKey]*generation)}
+}
+
+// Start запускает run в отдельной горутине, если генерация с таким ключом
+// еще не выполняется. Иначе возвращает уже выполняющуюся генерацию и false.
+// ctx, переданный в run, отменяется кнопкой отмены (см. Cancel).
+func (r *generationRegistry) Start(ctx context.Context, key generationKey, run func(ctx context.Context)) (*generation, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if g, ok := r.running[key]; ok {
+		return g, false
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	g := &generation{cancel: cancel, done: make(chan struct{})}
+	r.running[key] = g
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer r.finish(key, g)
+		defer recoverGeneration(key)
+		run(ctx)
+	}()
+	return g, true
+}
+
+// Attach вызывает then, когда генерация g завершится
+func (r *generationRegistry) Attach(g *generation, then func()) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		<-g.done
+		then()
+	}()
+}
+
+// Cancel отменяет генерацию; false, если такой генерации нет
+func (r *generationRegistry) Cancel(key generationKey) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	g, ok := r.running[key]
+	if !ok {
+		return false
+	}
+	g.cancel(errGenerationCancelled)
+	return true
+}
+
+// Wait ожидает завершения всех генераций
+func (r *generationRegistry) Wait() {
+	r.wg.Wait()
+}
+
+// WaitTimeout ожидает завершения генераций не дольше timeout.
+// Возвращает false, если время вышло раньше.
+func (r *generationRegistry) WaitTimeout(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// finish убирает завершенную генерацию из реестра
+func (r *generationRegistry) finish(key generationKey, g *generation) {
+	r.mu.Lock()
+	delete(r.running, key)
+	r.mu.Unlock()
+
+	g.cancel(nil)
+	close(g.done)
+}
+
+// recoverGeneration не дает панике в генерации остановить бота
+func recoverGeneration(key generationKey) {
+	if r := recover(); r != nil {
+		log.Printf("Паника при генерации рекомендаций %s пользователя %d: %v\n%s", key.kind, key.userID, r, debug.Stack())
+	}
+}
+
+// cancelledByUser проверяет, что ctx генерации отменен кнопкой пользователя,
+// а не остановкой бота
+func cancelledByUser(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errGenerationCancelled)
+}
@@ -0,0 +1,269 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"html"
+	"log"
+	"strings"
+
+	"cos-ai-bot/internal/history"
+	"cos-ai-bot/internal/i18n"
+	"cos-ai-bot/internal/markdown"
+	"cos-ai-bot/internal/models"
+	"cos-ai-bot/internal/services"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// historyPageSize количество рекомендаций на одной странице истории
+	historyPageSize = 5
+	// historyDiffLines сколько измененных строк текста показывать при сравнении
+	historyDiffLines = 30
+	// historyDateFormat формат даты рекомендации в истории
+	historyDateFormat = "02.01.2006 15:04"
+)
+
+var recommendationHistory history.Store // выданные рекомендации пользователей
+
+// newHistoryStore создает хранилище истории рекомендаций: в базе, если она
+// открыта, иначе в памяти
+func newHistoryStore(db *sql.DB) history.Store {
+	if db == nil {
+		return history.NewMemoryStore()
+	}
+	return history.NewPostgresStore(db)
+}
+
+// handleHistory показывает страницу истории рекомендаций, от новых к старым
+func handleHistory(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
+	chatID := callback.Message.Chat.ID
+	page := params.Int("page")
+	loc := i18n.FromContext(ctx)
+
+	records, total, err := recommendationHistory.List(ctx, chatID, page*historyPageSize, historyPageSize)
+	if err != nil {
+		log.Printf("Ошибка получения истории рекомендаций пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, loc.T("history.load_failed")))
+		return
+	}
+
+	if total == 0 {
+		msg := tgbotapi.NewMessage(chatID, loc.T("history.empty"))
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(loc.T("recs.back"), routeRecommendations.Data()),
+			),
+		)
+		bot.Send(msg)
+		return
+	}
+
+	// Каждая рекомендация — отдельная кнопка
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, record := range records {
+		kind := services.RecommendationType(record.Type)
+		label := loc.T("history.item", recommendationEmoji[kind], record.CreatedAt.Format(historyDateFormat), loc.T("recs."+record.Type+".title"))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, routeHistoryView.Data(record.ID)),
+		))
+	}
+
+	// Листание страниц: слева более новые рекомендации, справа более старые
+	pages := (total + historyPageSize - 1) / historyPageSize
+	var paging []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		paging = append(paging, tgbotapi.NewInlineKeyboardButtonData(loc.T("history.newer"), routeHistory.Data(page-1)))
+	}
+	if page+1 < pages {
+		paging = append(paging, tgbotapi.NewInlineKeyboardButtonData(loc.T("history.older"), routeHistory.Data(page+1)))
+	}
+	if len(paging) > 0 {
+		rows = append(rows, paging)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(loc.T("recs.back"), routeRecommendations.Data()),
+	))
+
+	msg := tgbotapi.NewMessage(chatID, loc.T("history.header", min(page+1, pages), pages))
+	msg.ParseMode = "HTML"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	bot.Send(msg)
+}
+
+// handleHistoryView показывает сохраненную рекомендацию целиком
+func handleHistoryView(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
+	chatID := callback.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	record, ok := loadHistoryRecord(ctx, bot, chatID, params.Int("id"))
+	if !ok {
+		return
+	}
+
+	var text strings.Builder
+	text.WriteString(loc.T("history.view.header",
+		recommendationEmoji[services.RecommendationType(record.Type)],
+		loc.T("recs."+record.Type+".title"),
+		record.CreatedAt.Format(historyDateFormat),
+		html.EscapeString(record.Model)))
+	text.WriteString(markdown.ToTelegramHTML(record.Content))
+
+	// Сравнение доступно, если до этой рекомендации была другая того же типа
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if _, err := recommendationHistory.Previous(ctx, record); err == nil {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("history.diff"), routeHistoryDiff.Data(record.ID)),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(loc.T("history.back"), routeHistory.Data(0)),
+	))
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	sendLongMessage(bot, chatID, text.String(), &keyboard)
+}
+
+// handleHistoryDiff показывает, что изменилось между рекомендацией и предыдущей
+// рекомендацией того же типа: ответы анкеты, коллекция, модель и текст
+func handleHistoryDiff(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
+	chatID := callback.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	record, ok := loadHistoryRecord(ctx, bot, chatID, params.Int("id"))
+	if !ok {
+		return
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("history.open"), routeHistoryView.Data(record.ID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("history.back"), routeHistory.Data(0)),
+		),
+	)
+
+	previous, err := recommendationHistory.Previous(ctx, record)
+	if err != nil {
+		if !errors.Is(err, history.ErrNotFound) {
+			log.Printf("Ошибка получения предыдущей рекомендации пользователя %d: %v", chatID, err)
+		}
+		msg := tgbotapi.NewMessage(chatID, loc.T("history.diff.none"))
+		msg.ReplyMarkup = keyboard
+		bot.Send(msg)
+		return
+	}
+
+	var text strings.Builder
+	text.WriteString(loc.T("history.diff.header",
+		loc.T("recs."+record.Type+".title"),
+		previous.CreatedAt.Format(historyDateFormat),
+		record.CreatedAt.Format(historyDateFormat)))
+	writeProfileDiff(&text, loc, previous.Profile, record.Profile)
+	if previous.Products != nil || record.Products != nil {
+		writeProductsDiff(&text, loc, previous.Products, record.Products)
+	}
+	if previous.Model != record.Model {
+		text.WriteString(loc.T("history.diff.model", html.EscapeString(previous.Model), html.EscapeString(record.Model)))
+	}
+	writeContentDiff(&text, loc, previous.Content, record.Content)
+
+	sendLongMessage(bot, chatID, text.String(), &keyboard)
+}
+
+// loadHistoryRecord загружает рекомендацию пользователя из истории;
+// если ее нет, сообщает об этом пользователю
+func loadHistoryRecord(ctx context.Context, bot *tgbotapi.BotAPI, chatID int64, id int) (*history.Record, bool) {
+	loc := i18n.FromContext(ctx)
+
+	record, err := recommendationHistory.Get(ctx, chatID, int64(id))
+	if err != nil {
+		if !errors.Is(err, history.ErrNotFound) {
+			log.Printf("Ошибка получения рекомендации %d пользователя %d: %v", id, chatID, err)
+		}
+		msg := tgbotapi.NewMessage(chatID, loc.T("history.not_found"))
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(loc.T("history.back"), routeHistory.Data(0)),
+			),
+		)
+		bot.Send(msg)
+		return nil, false
+	}
+	return record, true
+}
+
+// writeProfileDiff перечисляет ответы анкеты, изменившиеся между запусками
+func writeProfileDiff(text *strings.Builder, loc *i18n.Localizer, before, after *models.UserState) {
+	if before == nil {
+		before = &models.UserState{}
+	}
+	if after == nil {
+		after = &models.UserState{}
+	}
+
+	var changes strings.Builder
+	for _, question := range skincareForm.Questions() {
+		was, now := question.Display(loc, before), question.Display(loc, after)
+		if was == now {
+			continue
+		}
+		if was == "" {
+			was = loc.T("form.results.empty")
+		}
+		if now == "" {
+			now = loc.T("form.results.empty")
+		}
+		changes.WriteString(loc.T("history.diff.answer", question.Emoji, question.Title(loc), html.EscapeString(was), html.EscapeString(now)))
+	}
+
+	if changes.Len() == 0 {
+		text.WriteString(loc.T("history.diff.profile_same"))
+		return
+	}
+	text.WriteString(loc.T("history.diff.profile"))
+	text.WriteString(changes.String())
+}
+
+// writeProductsDiff перечисляет продукты, добавленные в коллекцию и удаленные из нее
+func writeProductsDiff(text *strings.Builder, loc *i18n.Localizer, before, after []history.Product) {
+	added, removed := history.DiffProducts(before, after)
+	if len(added) == 0 && len(removed) == 0 {
+		text.WriteString(loc.T("history.diff.products_same"))
+		return
+	}
+
+	text.WriteString(loc.T("history.diff.products"))
+	for _, product := range added {
+		text.WriteString(loc.T("history.diff.product_added", html.EscapeString(product.Brand), html.EscapeString(product.Title)))
+	}
+	for _, product := range removed {
+		text.WriteString(loc.T("history.diff.product_removed", html.EscapeString(product.Brand), html.EscapeString(product.Title)))
+	}
+}
+
+// writeContentDiff показывает строки текста рекомендации, которые появились
+// или пропали; длинный список изменений обрезается до historyDiffLines строк
+func writeContentDiff(text *strings.Builder, loc *i18n.Localizer, before, after string) {
+	changes := history.DiffLines(before, after)
+	if len(changes) == 0 {
+		text.WriteString(loc.T("history.diff.text_same"))
+		return
+	}
+
+	text.WriteString(loc.T("history.diff.text"))
+	for i, change := range changes {
+		if i == historyDiffLines {
+			text.WriteString(loc.N("history.diff.more_lines", len(changes)-historyDiffLines))
+			break
+		}
+		key := "history.diff.line_added"
+		if change.Op == history.LineRemoved {
+			key = "history.diff.line_removed"
+		}
+		text.WriteString(loc.T(key, html.EscapeString(change.Text)))
+	}
+}
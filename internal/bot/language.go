@@ -0,0 +1,81 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"cos-ai-bot/internal/i18n"
+	"cos-ai-bot/internal/preferences"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+var userPreferences preferences.Store // настройки пользователей: язык, выбранный через /language
+
+// newPreferenceStore создает хранилище настроек: в базе, если она открыта, иначе в памяти
+func newPreferenceStore(db *sql.DB) preferences.Store {
+	if db == nil {
+		return preferences.NewMemoryStore()
+	}
+	return preferences.NewPostgresStore(db)
+}
+
+// withUserLanguage добавляет в контекст переводчик на язык пользователя:
+// выбранный через /language, а если его нет — язык клиента Telegram
+func withUserLanguage(ctx context.Context, user *tgbotapi.User) context.Context {
+	if user == nil {
+		return i18n.WithLocalizer(ctx, i18n.For(i18n.Default))
+	}
+	return i18n.WithLocalizer(ctx, i18n.For(userLanguage(ctx, user)))
+}
+
+// userLanguage определяет язык интерфейса пользователя
+func userLanguage(ctx context.Context, user *tgbotapi.User) i18n.Lang {
+	code, err := userPreferences.Language(ctx, user.ID)
+	if err == nil {
+		if lang, ok := i18n.Parse(code); ok {
+			return lang
+		}
+	} else if !errors.Is(err, preferences.ErrNotFound) {
+		log.Printf("Ошибка получения языка пользователя %d: %v", user.ID, err)
+	}
+	return i18n.Match(user.LanguageCode)
+}
+
+// handleLanguageCommand показывает выбор языка интерфейса
+func handleLanguageCommand(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
+	loc := i18n.FromContext(ctx)
+
+	// Название каждого языка показываем на нем самом
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, lang := range i18n.Languages {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(i18n.For(lang).T("language.name"), routeLanguage.Data(string(lang))),
+		))
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, loc.T("language.choose"))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	bot.Send(msg)
+}
+
+// handleLanguageSelect сохраняет выбранный язык и показывает главное меню на нем
+func handleLanguageSelect(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
+	chatID := callback.Message.Chat.ID
+
+	lang, ok := i18n.Parse(params.String("lang"))
+	if !ok {
+		log.Printf("Неизвестный язык %q от пользователя %d", params.String("lang"), callback.From.ID)
+		return
+	}
+	if err := userPreferences.SetLanguage(ctx, callback.From.ID, string(lang)); err != nil {
+		log.Printf("Ошибка сохранения языка пользователя %d: %v", callback.From.ID, err)
+	}
+	log.Printf("Пользователь %d выбрал язык %s", callback.From.ID, lang)
+
+	ctx = i18n.WithLocalizer(ctx, i18n.For(lang))
+	bot.Send(tgbotapi.NewMessage(chatID, i18n.FromContext(ctx).T("language.changed")))
+	sendMainMenu(ctx, bot, chatID)
+}
@@ -0,0 +1,287 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// maxCallbackDataLen ограничение Telegram на размер callback_data в байтах
+const maxCallbackDataLen = 64
+
+// Ошибки маршрутизации callback-запросов
+var (
+	ErrUnknownRoute         = errors.New("неизвестный маршрут")
+	ErrCallbackDataTooLong  = fmt.Errorf("callback_data длиннее %d байт", maxCallbackDataLen)
+	ErrInvalidRouteParam    = errors.New("неверный параметр маршрута")
+	ErrRouteParamsMismatch  = errors.New("число аргументов не совпадает с шаблоном маршрута")
+	errInvalidRoutePattern  = errors.New("неверный шаблон маршрута")
+	errDuplicateRouteHandle = errors.New("маршрут уже зарегистрирован")
+)
+
+// RouteError описывает ошибку разбора или сборки callback_data
+type RouteError struct {
+	Data  string // callback_data или шаблон маршрута
+	Param string // имя параметра, если ошибка связана с ним
+	Err   error
+}
+
+func (e *RouteError) Error() string {
+	if e.Param != "" {
+		return fmt.Sprintf("%v: %q (параметр %s)", e.Err, e.Data, e.Param)
+	}
+	return fmt.Sprintf("%v: %q", e.Err, e.Data)
+}
+
+func (e *RouteError) Unwrap() error {
+	return e.Err
+}
+
+// Route шаблон callback-маршрута из сегментов, разделенных "/".
+// Сегмент {name} принимает любое непустое значение, {name:int} — только целое число.
+// Например: "product/{id:int}".
+type Route string
+
+// Build собирает callback_data, подставляя аргументы в параметры по порядку
+func (r Route) Build(args ...interface{}) (string, error) {
+	segments := strings.Split(string(r), "/")
+	parts := make([]string, 0, len(segments))
+	argIndex := 0
+
+	for _, segment := range segments {
+		name, kind, isParam := parseRouteParam(segment)
+		if !isParam {
+			parts = append(parts, segment)
+			continue
+		}
+		if argIndex >= len(args) {
+			return "", &RouteError{Data: string(r), Err: ErrRouteParamsMismatch}
+		}
+
+		value := fmt.Sprint(args[argIndex])
+		argIndex++
+		if value == "" || strings.Contains(value, "/") {
+			return "", &RouteError{Data: string(r), Param: name, Err: ErrInvalidRouteParam}
+		}
+		if kind == "int" {
+			if _, err := strconv.Atoi(value); err != nil {
+				return "", &RouteError{Data: string(r), Param: name, Err: ErrInvalidRouteParam}
+			}
+		}
+		parts = append(parts, value)
+	}
+
+	if argIndex != len(args) {
+		return "", &RouteError{Data: string(r), Err: ErrRouteParamsMismatch}
+	}
+
+	data := strings.Join(parts, "/")
+	if len(data) > maxCallbackDataLen {
+		return "", &RouteError{Data: data, Err: ErrCallbackDataTooLong}
+	}
+	return data, nil
+}
+
+// Data собирает callback_data и паникует при ошибке.
+// Используется при построении клавиатур, где аргументы заведомо корректны.
+func (r Route) Data(args ...interface{}) string {
+	data, err := r.Build(args...)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// RouteParams значения параметров, извлеченные из callback_data
+type RouteParams map[string]string
+
+// String возвращает значение параметра
+func (p RouteParams) String(name string) string {
+	return p[name]
+}
+
+// Int возвращает значение целочисленного параметра.
+// Для параметров вида {name:int} значение уже проверено маршрутизатором.
+func (p RouteParams) Int(name string) int {
+	value, _ := strconv.Atoi(p[name])
+	return value
+}
+
+// CallbackHandler обрабатывает callback-запрос, совпавший с маршрутом
+type CallbackHandler func(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams)
+
+// routeSegment разобранный сегмент шаблона
+type routeSegment struct {
+	literal string
+	param   string
+	kind    string
+}
+
+// compiledRoute маршрут, готовый к сопоставлению
+type compiledRoute struct {
+	route    Route
+	segments []routeSegment
+	literals int
+	handler  CallbackHandler
+}
+
+// CallbackRouter сопоставляет callback_data с зарегистрированными маршрутами.
+// Порядок регистрации не важен: при нескольких совпадениях выигрывает
+// маршрут с наибольшим числом фиксированных сегментов.
+type CallbackRouter struct {
+	routes []compiledRoute
+}
+
+// NewCallbackRouter создает пустой маршрутизатор
+func NewCallbackRouter() *CallbackRouter {
+	return &CallbackRouter{}
+}
+
+// Handle регистрирует обработчик маршрута.
+// Паникует при неверном или повторном шаблоне — это ошибка программы.
+func (r *CallbackRouter) Handle(route Route, handler CallbackHandler) {
+	compiled := compiledRoute{route: route, handler: handler}
+
+	for _, segment := range strings.Split(string(route), "/") {
+		if segment == "" {
+			panic(&RouteError{Data: string(route), Err: errInvalidRoutePattern})
+		}
+		name, kind, isParam := parseRouteParam(segment)
+		if !isParam {
+			compiled.segments = append(compiled.segments, routeSegment{literal: segment})
+			compiled.literals++
+			continue
+		}
+		if name == "" || (kind != "" && kind != "int") {
+			panic(&RouteError{Data: string(route), Param: name, Err: errInvalidRoutePattern})
+		}
+		compiled.segments = append(compiled.segments, routeSegment{param: name, kind: kind})
+	}
+
+	for _, existing := range r.routes {
+		if sameShape(existing.segments, compiled.segments) {
+			panic(&RouteError{Data: string(route), Err: errDuplicateRouteHandle})
+		}
+	}
+
+	r.routes = append(r.routes, compiled)
+}
+
+// Dispatch находит маршрут для callback.Data и вызывает его обработчик
+func (r *CallbackRouter) Dispatch(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) error {
+	route, params, err := r.lookup(callback.Data)
+	if err != nil {
+		return err
+	}
+
+	route.handler(ctx, bot, callback, params)
+	return nil
+}
+
+// Match возвращает шаблон маршрута, которому соответствует callback_data
+func (r *CallbackRouter) Match(data string) (Route, error) {
+	route, _, err := r.lookup(data)
+	if err != nil {
+		return "", err
+	}
+	return route.route, nil
+}
+
+// lookup разбирает callback_data и возвращает подходящий маршрут с параметрами
+func (r *CallbackRouter) lookup(data string) (*compiledRoute, RouteParams, error) {
+	if len(data) > maxCallbackDataLen {
+		return nil, nil, &RouteError{Data: data, Err: ErrCallbackDataTooLong}
+	}
+
+	parts := strings.Split(data, "/")
+
+	var (
+		best       *compiledRoute
+		bestParams RouteParams
+		paramErr   error
+	)
+	for i := range r.routes {
+		route := &r.routes[i]
+		params, badParam, ok := route.match(parts)
+		if !ok {
+			if badParam != "" && paramErr == nil {
+				paramErr = &RouteError{Data: data, Param: badParam, Err: ErrInvalidRouteParam}
+			}
+			continue
+		}
+		if best == nil || route.literals > best.literals {
+			best, bestParams = route, params
+		}
+	}
+
+	if best != nil {
+		return best, bestParams, nil
+	}
+	if paramErr != nil {
+		return nil, nil, paramErr
+	}
+	return nil, nil, &RouteError{Data: data, Err: ErrUnknownRoute}
+}
+
+// match сопоставляет сегменты данных с маршрутом.
+// Если форма совпала, но значение параметра не прошло проверку типа,
+// возвращает имя этого параметра.
+func (cr *compiledRoute) match(parts []string) (params RouteParams, badParam string, ok bool) {
+	if len(parts) != len(cr.segments) {
+		return nil, "", false
+	}
+
+	params = RouteParams{}
+	for i, segment := range cr.segments {
+		part := parts[i]
+		if segment.param == "" {
+			if part != segment.literal {
+				return nil, "", false
+			}
+			continue
+		}
+		if part == "" {
+			return nil, "", false
+		}
+		if segment.kind == "int" && badParam == "" {
+			if _, err := strconv.Atoi(part); err != nil {
+				badParam = segment.param
+			}
+		}
+		params[segment.param] = part
+	}
+
+	if badParam != "" {
+		return nil, badParam, false
+	}
+	return params, "", true
+}
+
+// parseRouteParam разбирает сегмент вида {name} или {name:kind}
+func parseRouteParam(segment string) (name, kind string, ok bool) {
+	if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+		return "", "", false
+	}
+	name = strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}")
+	if i := strings.IndexByte(name, ':'); i >= 0 {
+		name, kind = name[:i], name[i+1:]
+	}
+	return name, kind, true
+}
+
+// sameShape проверяет, что два шаблона совпадают с точностью до имен параметров
+func sameShape(a, b []routeSegment) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].literal != b[i].literal || (a[i].param == "") != (b[i].param == "") {
+			return false
+		}
+	}
+	return true
+}
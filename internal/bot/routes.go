@@ -0,0 +1,121 @@
+package bot
+
+import (
+	"context"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Маршруты callback-кнопок
+const (
+	routeStart                   Route = "start"
+	routeAnketa                  Route = "anketa"
+	routeAnketaNew               Route = "anketa/new"
+	routeAnketaResume            Route = "anketa/resume"
+	routeAnketaRetake            Route = "anketa/retake"
+	routeAnketaDelete            Route = "anketa/delete"
+	routeAnketaEdit              Route = "anketa/edit/{question}"
+	routeFormAnswer              Route = "form/{code}"
+	routeFormToggle              Route = "form/toggle/{code}"
+	routeFormDone                Route = "form/done"
+	routeFormBack                Route = "form/back"
+	routeFormSkip                Route = "form/skip"
+	routeProduct                 Route = "product/{id:int}"
+	routeProductAdd              Route = "add/{id:int}"
+	routeProductRemove           Route = "remove/{id:int}"
+	routeMyProducts              Route = "products"
+	routeDeleteProducts          Route = "products/delete"
+	routeCheckProducts           Route = "products/check"
+	routeProductConflicts        Route = "products/conflicts"
+	routeRecommendations         Route = "recs"
+	routeRecommendationsAnketa   Route = "recs/anketa"
+	routeRecommendationsProducts Route = "recs/products"
+	routeRecommendationsGeneral  Route = "recs/general"
+	routeRecommendationsFresh    Route = "recs/fresh/{kind}"
+	routeRecommendationsCancel   Route = "recs/cancel/{kind}"
+	routeHistory                 Route = "recs/history/{page:int}"
+	routeHistoryView             Route = "recs/history/view/{id:int}"
+	routeHistoryDiff             Route = "recs/history/diff/{id:int}"
+	routeLanguage                Route = "language/{lang}"
+	routeRoutine                 Route = "routine"
+	routeRoutineAdd              Route = "routine/add/{slot}"
+	routeRoutineAddProduct       Route = "routine/add/{slot}/{id:int}"
+	routeRoutineAddCategory      Route = "routine/add/{slot}/{id:int}/{category}"
+	routeRoutineAddFrequency     Route = "routine/add/{slot}/{id:int}/{category}/{frequency}"
+	routeRoutineEdit             Route = "routine/edit/{slot}"
+	routeRoutineMove             Route = "routine/move/{slot}/{index:int}/{dir}"
+	routeRoutineRemove           Route = "routine/remove/{slot}/{index:int}"
+	routeRoutineSort             Route = "routine/sort"
+	routeRoutineName             Route = "routine/name"
+	routeRoutineClear            Route = "routine/clear"
+)
+
+// inPlaceRoutes маршруты, обработчики которых обновляют сообщение с кнопками
+// вместо его удаления и сами отвечают на callback
+var inPlaceRoutes = map[Route]bool{
+	routeFormToggle: true,
+	routeFormDone:   true,
+
+	routeRecommendationsCancel: true,
+
+	routeRoutineMove:   true,
+	routeRoutineRemove: true,
+}
+
+// callbackRouter маршрутизатор нажатий на inline кнопки
+var callbackRouter = newCallbackRoutes()
+
+// newCallbackRoutes регистрирует обработчики всех callback-маршрутов
+func newCallbackRoutes() *CallbackRouter {
+	router := NewCallbackRouter()
+
+	router.Handle(routeStart, withoutParams(handleBackToStart))
+	router.Handle(routeAnketa, withoutParams(handleAnketa))
+	router.Handle(routeAnketaNew, withoutParams(handleStartFormNew))
+	router.Handle(routeAnketaResume, withoutParams(handleResumeForm))
+	router.Handle(routeAnketaRetake, withoutParams(handleRetakeAnketa))
+	router.Handle(routeAnketaDelete, withoutParams(handleDeleteAnketa))
+	router.Handle(routeAnketaEdit, handleEditAnswer)
+	router.Handle(routeFormAnswer, handleFormCallback)
+	router.Handle(routeFormToggle, handleFormToggle)
+	router.Handle(routeFormDone, withoutParams(handleFormDone))
+	router.Handle(routeFormBack, withoutParams(handleFormBack))
+	router.Handle(routeFormSkip, withoutParams(handleFormSkip))
+	router.Handle(routeProduct, handleProductSelection)
+	router.Handle(routeProductAdd, handleAddProductToCollection)
+	router.Handle(routeProductRemove, handleRemoveProductFromCollection)
+	router.Handle(routeMyProducts, withoutParams(handleMyProducts))
+	router.Handle(routeDeleteProducts, withoutParams(handleDeleteProducts))
+	router.Handle(routeCheckProducts, withoutParams(handleCheckProducts))
+	router.Handle(routeProductConflicts, withoutParams(handleProductConflicts))
+	router.Handle(routeRecommendations, withoutParams(handleRecommendations))
+	router.Handle(routeRecommendationsAnketa, withoutParams(handleRecommendationsAnketa))
+	router.Handle(routeRecommendationsProducts, withoutParams(handleRecommendationsProducts))
+	router.Handle(routeRecommendationsGeneral, withoutParams(handleRecommendationsGeneral))
+	router.Handle(routeRecommendationsFresh, handleRecommendationsFresh)
+	router.Handle(routeRecommendationsCancel, handleRecommendationsCancel)
+	router.Handle(routeHistory, handleHistory)
+	router.Handle(routeHistoryView, handleHistoryView)
+	router.Handle(routeHistoryDiff, handleHistoryDiff)
+	router.Handle(routeLanguage, handleLanguageSelect)
+	router.Handle(routeRoutine, withoutParams(handleRoutine))
+	router.Handle(routeRoutineAdd, handleRoutineAdd)
+	router.Handle(routeRoutineAddProduct, handleRoutineAddProduct)
+	router.Handle(routeRoutineAddCategory, handleRoutineAddCategory)
+	router.Handle(routeRoutineAddFrequency, handleRoutineAddFrequency)
+	router.Handle(routeRoutineEdit, handleRoutineEdit)
+	router.Handle(routeRoutineMove, handleRoutineMove)
+	router.Handle(routeRoutineRemove, handleRoutineRemove)
+	router.Handle(routeRoutineSort, withoutParams(handleRoutineSort))
+	router.Handle(routeRoutineName, withoutParams(handleRoutineName))
+	router.Handle(routeRoutineClear, withoutParams(handleRoutineClear))
+
+	return router
+}
+
+// withoutParams адаптирует обработчик, которому не нужны параметры маршрута
+func withoutParams(handler func(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery)) CallbackHandler {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, _ RouteParams) {
+		handler(ctx, bot, callback)
+	}
+}
@@ -0,0 +1,620 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"cos-ai-bot/internal/database"
+	"cos-ai-bot/internal/i18n"
+	"cos-ai-bot/internal/models"
+	"cos-ai-bot/internal/routine"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// routineNameMaxLen наибольшая длина названия ухода в символах
+// (см. migrations/007_user_routines.sql)
+const routineNameMaxLen = 64
+
+var userRoutines routine.Store // уход, составленный пользователями
+
+// newRoutineStore создает хранилище ухода пользователей: в базе, если она
+// открыта, иначе в памяти
+func newRoutineStore(db *sql.DB) routine.Store {
+	if db == nil {
+		return routine.NewMemoryStore()
+	}
+	return routine.NewPostgresStore(db)
+}
+
+// pendingRoutineNames чаты, от которых ждем название ухода следующим сообщением.
+// Хранится в памяти: после перезапуска достаточно снова нажать кнопку.
+var pendingRoutineNames = struct {
+	sync.Mutex
+	chats map[int64]bool
+}{chats: make(map[int64]bool)}
+
+// setRoutineNamePending включает или выключает ожидание названия ухода
+func setRoutineNamePending(chatID int64, pending bool) {
+	pendingRoutineNames.Lock()
+	defer pendingRoutineNames.Unlock()
+
+	if pending {
+		pendingRoutineNames.chats[chatID] = true
+	} else {
+		delete(pendingRoutineNames.chats, chatID)
+	}
+}
+
+// takeRoutineNamePending проверяет, ждем ли название ухода, и снимает ожидание
+func takeRoutineNamePending(chatID int64) bool {
+	pendingRoutineNames.Lock()
+	defer pendingRoutineNames.Unlock()
+
+	pending := pendingRoutineNames.chats[chatID]
+	delete(pendingRoutineNames.chats, chatID)
+	return pending
+}
+
+// loadRoutine возвращает уход пользователя; если он еще не составлен — пустой
+func loadRoutine(ctx context.Context, chatID int64) (*routine.UserRoutine, error) {
+	own, err := userRoutines.Get(ctx, chatID)
+	if errors.Is(err, routine.ErrNotFound) {
+		return &routine.UserRoutine{UserID: chatID}, nil
+	}
+	return own, err
+}
+
+// slotTitle название утреннего или вечернего ухода
+func slotTitle(loc *i18n.Localizer, slot routine.Slot) string {
+	if slot == routine.SlotPM {
+		return loc.T("routine.evening")
+	}
+	return loc.T("routine.morning")
+}
+
+// handleRoutine показывает уход пользователя
+func handleRoutine(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	setRoutineNamePending(chatID, false)
+	showRoutine(ctx, bot, chatID)
+}
+
+// showRoutine отправляет уход по шагам, нарушения порядка нанесения и кнопки для изменения
+func showRoutine(ctx context.Context, bot *tgbotapi.BotAPI, chatID int64) {
+	loc := i18n.FromContext(ctx)
+
+	own, err := loadRoutine(ctx, chatID)
+	if err != nil {
+		log.Printf("Ошибка получения ухода пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, loc.T("routine.builder.load_failed")))
+		return
+	}
+
+	var text strings.Builder
+	if own.Name != "" {
+		text.WriteString(loc.T("routine.builder.named", html.EscapeString(own.Name)))
+	} else {
+		text.WriteString(loc.T("routine.builder.header"))
+	}
+	for _, slot := range routine.Slots {
+		text.WriteString(loc.T("routine.builder.slot", slotTitle(loc, slot)))
+		writeRoutineSteps(&text, loc, own.Steps(slot))
+		text.WriteString("\n")
+	}
+	if own.Empty() {
+		text.WriteString(loc.T("routine.builder.empty_hint"))
+	}
+
+	issues := own.Check()
+	sortable := false
+	if len(issues) > 0 {
+		text.WriteString(loc.T("routine.builder.issues"))
+		for _, issue := range issues {
+			entry := issue.Entry
+			switch issue.Kind {
+			case routine.IssueOrder:
+				sortable = true
+				text.WriteString(loc.T("routine.builder.issue.order",
+					slotTitle(loc, issue.Slot), issue.Step+1,
+					html.EscapeString(entry.Title), loc.T("routine.category."+string(entry.Category)),
+					html.EscapeString(issue.After.Title), loc.T("routine.category."+string(issue.After.Category))))
+			case routine.IssueEveningSPF:
+				text.WriteString(loc.T("routine.builder.issue.evening_spf",
+					slotTitle(loc, issue.Slot), issue.Step+1, html.EscapeString(entry.Title)))
+			}
+		}
+	}
+
+	var keyboard [][]tgbotapi.InlineKeyboardButton
+	var addRow, editRow []tgbotapi.InlineKeyboardButton
+	for _, slot := range routine.Slots {
+		addRow = append(addRow, tgbotapi.NewInlineKeyboardButtonData(
+			loc.T("routine.builder.add", slotTitle(loc, slot)), routeRoutineAdd.Data(slot)))
+		if len(own.Steps(slot)) > 0 {
+			editRow = append(editRow, tgbotapi.NewInlineKeyboardButtonData(
+				loc.T("routine.builder.edit", slotTitle(loc, slot)), routeRoutineEdit.Data(slot)))
+		}
+	}
+	keyboard = append(keyboard, addRow)
+	if len(editRow) > 0 {
+		keyboard = append(keyboard, editRow)
+	}
+	if sortable {
+		keyboard = append(keyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("routine.builder.sort"), routeRoutineSort.Data())))
+	}
+	keyboard = append(keyboard, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(loc.T("routine.builder.rename"), routeRoutineName.Data())))
+	if !own.Empty() {
+		keyboard = append(keyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("routine.builder.clear"), routeRoutineClear.Data())))
+	}
+	keyboard = append(keyboard, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(loc.T("button.back"), routeStart.Data())))
+
+	markup := tgbotapi.NewInlineKeyboardMarkup(keyboard...)
+	sendLongMessage(bot, chatID, text.String(), &markup)
+}
+
+// writeRoutineSteps перечисляет шаги с этапом и частотой
+func writeRoutineSteps(text *strings.Builder, loc *i18n.Localizer, steps []routine.Entry) {
+	if len(steps) == 0 {
+		text.WriteString(loc.T("routine.builder.slot_empty"))
+		return
+	}
+	for i, entry := range steps {
+		text.WriteString(loc.T("routine.builder.step", i+1,
+			html.EscapeString(entry.Brand), html.EscapeString(entry.Title),
+			loc.T("routine.category."+string(entry.Category)),
+			loc.T("routine.frequency."+string(entry.Frequency))))
+	}
+}
+
+// routineBackKeyboard клавиатура с возвратом к уходу
+func routineBackKeyboard(loc *i18n.Localizer) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("routine.builder.back"), routeRoutine.Data()),
+		),
+	)
+}
+
+// routineSlot проверяет время нанесения из маршрута
+func routineSlot(params RouteParams) (routine.Slot, bool) {
+	slot := routine.Slot(params.String("slot"))
+	return slot, slot.Known()
+}
+
+// findCollectionProduct загружает коллекцию и ищет в ней продукт
+func findCollectionProduct(ctx context.Context, chatID int64, productID int) (*models.APIUserProduct, error) {
+	products, err := database.GetUserProducts(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	for _, product := range products {
+		if product.ProductID == productID {
+			return &product, nil
+		}
+	}
+	return nil, nil
+}
+
+// handleRoutineAdd предлагает выбрать продукт коллекции для нового шага
+func handleRoutineAdd(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
+	chatID := callback.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	slot, ok := routineSlot(params)
+	if !ok {
+		log.Printf("Неизвестное время нанесения в маршруте ухода: %s", params.String("slot"))
+		return
+	}
+
+	own, err := loadRoutine(ctx, chatID)
+	if err != nil {
+		log.Printf("Ошибка получения ухода пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, loc.T("routine.builder.load_failed")))
+		return
+	}
+	products, err := database.GetUserProducts(ctx, chatID)
+	if err != nil {
+		errorMsg := tgbotapi.NewMessage(chatID, loc.T("products.load_failed", err))
+		errorMsg.ReplyMarkup = routineBackKeyboard(loc)
+		bot.Send(errorMsg)
+		return
+	}
+
+	inSlot := make(map[int]bool)
+	for _, entry := range own.Steps(slot) {
+		inSlot[entry.ProductID] = true
+	}
+
+	var keyboard [][]tgbotapi.InlineKeyboardButton
+	for _, product := range products {
+		if inSlot[product.ProductID] {
+			continue
+		}
+		keyboard = append(keyboard, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+			fmt.Sprintf("%s %s", product.Brand, product.Title),
+			routeRoutineAddProduct.Data(slot, product.ProductID),
+		)))
+	}
+	keyboard = append(keyboard, routineBackKeyboard(loc).InlineKeyboard...)
+
+	var text string
+	switch {
+	case len(products) == 0:
+		text = loc.T("routine.builder.collection_empty")
+	case len(keyboard) == 1:
+		text = loc.T("routine.builder.all_added", slotTitle(loc, slot))
+	default:
+		text = loc.T("routine.builder.choose_product", slotTitle(loc, slot))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "HTML"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(keyboard...)
+	bot.Send(msg)
+}
+
+// handleRoutineAddProduct предлагает выбрать этап ухода для продукта
+func handleRoutineAddProduct(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
+	chatID := callback.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	slot, ok := routineSlot(params)
+	if !ok {
+		log.Printf("Неизвестное время нанесения в маршруте ухода: %s", params.String("slot"))
+		return
+	}
+	product := routineProduct(ctx, bot, chatID, params.Int("id"))
+	if product == nil {
+		return
+	}
+
+	var keyboard [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for _, category := range routine.Categories {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+			loc.T("routine.category."+string(category)),
+			routeRoutineAddCategory.Data(slot, product.ProductID, category),
+		))
+		if len(row) == 2 {
+			keyboard = append(keyboard, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		keyboard = append(keyboard, row)
+	}
+	keyboard = append(keyboard, routineBackKeyboard(loc).InlineKeyboard...)
+
+	msg := tgbotapi.NewMessage(chatID, loc.T("routine.builder.choose_category",
+		slotTitle(loc, slot), html.EscapeString(product.Brand), html.EscapeString(product.Title)))
+	msg.ParseMode = "HTML"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(keyboard...)
+	bot.Send(msg)
+}
+
+// handleRoutineAddCategory предлагает выбрать, как часто выполнять шаг
+func handleRoutineAddCategory(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
+	chatID := callback.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	slot, ok := routineSlot(params)
+	category := routine.Category(params.String("category"))
+	if !ok || !category.Known() {
+		log.Printf("Неверный шаг ухода в маршруте: %s, %s", params.String("slot"), category)
+		return
+	}
+	product := routineProduct(ctx, bot, chatID, params.Int("id"))
+	if product == nil {
+		return
+	}
+
+	var keyboard [][]tgbotapi.InlineKeyboardButton
+	for _, frequency := range routine.UserFrequencies {
+		keyboard = append(keyboard, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+			loc.T("routine.frequency."+string(frequency)),
+			routeRoutineAddFrequency.Data(slot, product.ProductID, category, frequency),
+		)))
+	}
+	keyboard = append(keyboard, routineBackKeyboard(loc).InlineKeyboard...)
+
+	msg := tgbotapi.NewMessage(chatID, loc.T("routine.builder.choose_frequency",
+		slotTitle(loc, slot), html.EscapeString(product.Brand), html.EscapeString(product.Title),
+		loc.T("routine.category."+string(category))))
+	msg.ParseMode = "HTML"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(keyboard...)
+	bot.Send(msg)
+}
+
+// handleRoutineAddFrequency добавляет шаг в конец утреннего или вечернего ухода.
+// Если продукт там уже есть, у шага меняются этап и частота.
+func handleRoutineAddFrequency(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
+	chatID := callback.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	slot, ok := routineSlot(params)
+	category := routine.Category(params.String("category"))
+	frequency := routine.Frequency(params.String("frequency"))
+	if !ok || !category.Known() || !frequency.Known() {
+		log.Printf("Неверный шаг ухода в маршруте: %s, %s, %s", params.String("slot"), category, frequency)
+		return
+	}
+	product := routineProduct(ctx, bot, chatID, params.Int("id"))
+	if product == nil {
+		return
+	}
+
+	own, err := loadRoutine(ctx, chatID)
+	if err != nil {
+		log.Printf("Ошибка получения ухода пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, loc.T("routine.builder.load_failed")))
+		return
+	}
+
+	entry := routine.Entry{
+		ProductID: product.ProductID,
+		Brand:     product.Brand,
+		Title:     product.Title,
+		Category:  category,
+		Frequency: frequency,
+	}
+	steps := own.Steps(slot)
+	replaced := false
+	for i := range steps {
+		if steps[i].ProductID == entry.ProductID {
+			steps[i] = entry
+			replaced = true
+		}
+	}
+	if !replaced {
+		own.SetSteps(slot, append(steps, entry))
+	}
+
+	saveRoutine(ctx, bot, own)
+}
+
+// routineProduct ищет продукт в коллекции; если его нет или коллекция
+// не загрузилась, сообщает об этом пользователю и возвращает nil
+func routineProduct(ctx context.Context, bot *tgbotapi.BotAPI, chatID int64, productID int) *models.APIUserProduct {
+	loc := i18n.FromContext(ctx)
+
+	product, err := findCollectionProduct(ctx, chatID, productID)
+	if err != nil {
+		errorMsg := tgbotapi.NewMessage(chatID, loc.T("products.load_failed", err))
+		errorMsg.ReplyMarkup = routineBackKeyboard(loc)
+		bot.Send(errorMsg)
+		return nil
+	}
+	if product == nil {
+		msg := tgbotapi.NewMessage(chatID, loc.T("routine.builder.not_in_collection"))
+		msg.ReplyMarkup = routineBackKeyboard(loc)
+		bot.Send(msg)
+	}
+	return product
+}
+
+// saveRoutine сохраняет уход и показывает его
+func saveRoutine(ctx context.Context, bot *tgbotapi.BotAPI, own *routine.UserRoutine) {
+	if err := userRoutines.Save(ctx, own); err != nil {
+		log.Printf("Ошибка сохранения ухода пользователя %d: %v", own.UserID, err)
+		loc := i18n.FromContext(ctx)
+		msg := tgbotapi.NewMessage(own.UserID, loc.T("routine.builder.save_failed"))
+		msg.ReplyMarkup = routineBackKeyboard(loc)
+		bot.Send(msg)
+		return
+	}
+	showRoutine(ctx, bot, own.UserID)
+}
+
+// handleRoutineEdit показывает шаги утреннего или вечернего ухода
+// с кнопками, чтобы переставить или убрать их
+func handleRoutineEdit(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
+	chatID := callback.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	slot, ok := routineSlot(params)
+	if !ok {
+		log.Printf("Неизвестное время нанесения в маршруте ухода: %s", params.String("slot"))
+		return
+	}
+	own, err := loadRoutine(ctx, chatID)
+	if err != nil {
+		log.Printf("Ошибка получения ухода пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, loc.T("routine.builder.load_failed")))
+		return
+	}
+
+	text, keyboard := routineEditView(loc, own, slot)
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "HTML"
+	msg.ReplyMarkup = keyboard
+	bot.Send(msg)
+}
+
+// routineEditView текст и клавиатура для изменения шагов
+func routineEditView(loc *i18n.Localizer, own *routine.UserRoutine, slot routine.Slot) (string, tgbotapi.InlineKeyboardMarkup) {
+	steps := own.Steps(slot)
+
+	var text strings.Builder
+	text.WriteString(loc.T("routine.builder.edit_header", slotTitle(loc, slot)))
+	writeRoutineSteps(&text, loc, steps)
+
+	var keyboard [][]tgbotapi.InlineKeyboardButton
+	for i := range steps {
+		var row []tgbotapi.InlineKeyboardButton
+		if i > 0 {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+				loc.T("routine.builder.up", i+1), routeRoutineMove.Data(slot, i, "up")))
+		}
+		if i < len(steps)-1 {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+				loc.T("routine.builder.down", i+1), routeRoutineMove.Data(slot, i, "down")))
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+			loc.T("routine.builder.remove", i+1), routeRoutineRemove.Data(slot, i)))
+		keyboard = append(keyboard, row)
+	}
+	keyboard = append(keyboard, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(loc.T("routine.builder.done"), routeRoutine.Data())))
+
+	return text.String(), tgbotapi.NewInlineKeyboardMarkup(keyboard...)
+}
+
+// handleRoutineMove переставляет шаг на одну позицию вверх или вниз,
+// обновляя сообщение со списком шагов
+func handleRoutineMove(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
+	editRoutineSteps(ctx, bot, callback, params, func(steps []routine.Entry, index int) []routine.Entry {
+		target := index - 1
+		if params.String("dir") == "down" {
+			target = index + 1
+		}
+		if target >= 0 && target < len(steps) {
+			steps[index], steps[target] = steps[target], steps[index]
+		}
+		return steps
+	})
+}
+
+// handleRoutineRemove убирает шаг, обновляя сообщение со списком шагов
+func handleRoutineRemove(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams) {
+	editRoutineSteps(ctx, bot, callback, params, func(steps []routine.Entry, index int) []routine.Entry {
+		return append(steps[:index], steps[index+1:]...)
+	})
+}
+
+// editRoutineSteps применяет change к шагу index из маршрута, сохраняет уход
+// и обновляет сообщение со списком шагов. Если шаги изменились в другом
+// сообщении и номера уже не совпадают, сообщение просто обновляется.
+func editRoutineSteps(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, params RouteParams,
+	change func(steps []routine.Entry, index int) []routine.Entry) {
+	chatID := callback.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	slot, ok := routineSlot(params)
+	if !ok {
+		log.Printf("Неизвестное время нанесения в маршруте ухода: %s", params.String("slot"))
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		return
+	}
+	own, err := loadRoutine(ctx, chatID)
+	if err != nil {
+		log.Printf("Ошибка получения ухода пользователя %d: %v", chatID, err)
+		bot.Request(tgbotapi.NewCallback(callback.ID, loc.T("routine.builder.load_failed")))
+		return
+	}
+
+	if index := params.Int("index"); index >= 0 && index < len(own.Steps(slot)) {
+		own.SetSteps(slot, change(own.Steps(slot), index))
+		if err := userRoutines.Save(ctx, own); err != nil {
+			log.Printf("Ошибка сохранения ухода пользователя %d: %v", chatID, err)
+			bot.Request(tgbotapi.NewCallback(callback.ID, loc.T("routine.builder.save_failed")))
+			return
+		}
+	}
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+	text, keyboard := routineEditView(loc, own, slot)
+	edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, callback.Message.MessageID, text, keyboard)
+	edit.ParseMode = "HTML"
+	if _, err := bot.Send(edit); err != nil {
+		log.Printf("Ошибка обновления шагов ухода: %v", err)
+	}
+}
+
+// handleRoutineSort расставляет шаги в порядке нанесения
+func handleRoutineSort(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+
+	own, err := loadRoutine(ctx, chatID)
+	if err != nil {
+		log.Printf("Ошибка получения ухода пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, i18n.FromContext(ctx).T("routine.builder.load_failed")))
+		return
+	}
+	own.Sort()
+	saveRoutine(ctx, bot, own)
+}
+
+// handleRoutineClear удаляет уход пользователя вместе с названием
+func handleRoutineClear(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+
+	if err := userRoutines.Delete(ctx, chatID); err != nil {
+		log.Printf("Ошибка удаления ухода пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, i18n.FromContext(ctx).T("routine.builder.save_failed")))
+		return
+	}
+	showRoutine(ctx, bot, chatID)
+}
+
+// handleRoutineName просит прислать название ухода следующим сообщением
+func handleRoutineName(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	setRoutineNamePending(chatID, true)
+
+	msg := tgbotapi.NewMessage(chatID, loc.T("routine.builder.name_prompt", routineNameMaxLen))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("routine.builder.cancel"), routeRoutine.Data()),
+		),
+	)
+	bot.Send(msg)
+}
+
+// handleRoutineNameInput сохраняет название ухода, если бот его ждет.
+// Возвращает false, если сообщение не относится к уходу.
+func handleRoutineNameInput(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message) bool {
+	chatID := message.Chat.ID
+	if !takeRoutineNamePending(chatID) {
+		return false
+	}
+	loc := i18n.FromContext(ctx)
+
+	name := strings.TrimSpace(message.Text)
+	if name == "" || utf8.RuneCountInString(name) > routineNameMaxLen {
+		setRoutineNamePending(chatID, true)
+		bot.Send(tgbotapi.NewMessage(chatID, loc.T("routine.builder.name_invalid", routineNameMaxLen)))
+		return true
+	}
+
+	own, err := loadRoutine(ctx, chatID)
+	if err != nil {
+		log.Printf("Ошибка получения ухода пользователя %d: %v", chatID, err)
+		bot.Send(tgbotapi.NewMessage(chatID, loc.T("routine.builder.load_failed")))
+		return true
+	}
+	own.Name = name
+	saveRoutine(ctx, bot, own)
+	return true
+}
+
+// removeFromRoutine убирает удаленный из коллекции продукт из ухода пользователя
+func removeFromRoutine(ctx context.Context, chatID int64, productID int) {
+	own, err := userRoutines.Get(ctx, chatID)
+	if errors.Is(err, routine.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		log.Printf("Ошибка получения ухода пользователя %d: %v", chatID, err)
+		return
+	}
+	if !own.RemoveProduct(productID) {
+		return
+	}
+	if err := userRoutines.Save(ctx, own); err != nil {
+		log.Printf("Ошибка сохранения ухода пользователя %d: %v", chatID, err)
+	}
+}
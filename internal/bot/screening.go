@@ -0,0 +1,176 @@
+package bot
+
+import (
+	"context"
+	"html"
+	"log"
+	"strings"
+
+	"cos-ai-bot/internal/database"
+	"cos-ai-bot/internal/i18n"
+	"cos-ai-bot/internal/services"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleCheckProducts проверяет составы всех продуктов коллекции
+// по аллергиям и особенностям питания из анкеты
+func handleCheckProducts(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("products.back"), routeMyProducts.Data()),
+		),
+	)
+
+	screener := services.NewIngredientScreener(getUserState(ctx, chatID))
+	if screener.Empty() {
+		msg := tgbotapi.NewMessage(chatID, loc.T("screening.no_restrictions"))
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(loc.T("menu.anketa"), routeAnketa.Data()),
+			),
+			keyboard.InlineKeyboard[0],
+		)
+		bot.Send(msg)
+		return
+	}
+
+	bot.Send(tgbotapi.NewMessage(chatID, loc.T("screening.loading")))
+
+	results, err := screener.ScreenCollection(ctx, chatID)
+	if err != nil {
+		log.Printf("Ошибка проверки коллекции пользователя %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, loc.T("screening.failed"))
+		msg.ReplyMarkup = keyboard
+		bot.Send(msg)
+		return
+	}
+
+	var flagged, unknown []services.ProductScreening
+	clean := 0
+	for _, result := range results {
+		switch {
+		case len(result.Flags) > 0:
+			flagged = append(flagged, result)
+		case result.NoIngredients:
+			unknown = append(unknown, result)
+		default:
+			clean++
+		}
+	}
+
+	var text strings.Builder
+	text.WriteString(loc.T("screening.header"))
+	if len(flagged) == 0 {
+		text.WriteString(loc.T("screening.nothing_found"))
+	} else {
+		text.WriteString(loc.N("screening.flagged", len(flagged)))
+		for _, result := range flagged {
+			text.WriteString(loc.T("screening.product", html.EscapeString(result.Brand), html.EscapeString(result.Title)))
+			writeIngredientFlags(&text, loc, result.Flags)
+			text.WriteString("\n")
+		}
+	}
+	if clean > 0 {
+		text.WriteString(loc.N("screening.clean", clean))
+	}
+	if len(unknown) > 0 {
+		text.WriteString(loc.N("screening.unknown", len(unknown)))
+		for _, result := range unknown {
+			text.WriteString(loc.T("screening.unknown_product", html.EscapeString(result.Brand), html.EscapeString(result.Title)))
+		}
+	}
+	text.WriteString(loc.T("screening.disclaimer"))
+
+	sendLongMessage(bot, chatID, text.String(), &keyboard)
+}
+
+// warnAboutProduct после добавления продукта в коллекцию предупреждает,
+// если в его составе есть ингредиенты, которых пользователь избегает
+func warnAboutProduct(ctx context.Context, bot *tgbotapi.BotAPI, chatID int64, productID int) {
+	screener := services.NewIngredientScreener(getUserState(ctx, chatID))
+	if screener.Empty() {
+		return
+	}
+
+	product, err := database.GetProduct(ctx, productID)
+	if err != nil {
+		log.Printf("Ошибка получения состава продукта %d для проверки: %v", productID, err)
+		return
+	}
+	result := screener.ScreenProduct(product)
+	if len(result.Flags) == 0 {
+		return
+	}
+
+	loc := i18n.FromContext(ctx)
+	var text strings.Builder
+	text.WriteString(loc.T("screening.added_warning", html.EscapeString(result.Brand), html.EscapeString(result.Title)))
+	writeIngredientFlags(&text, loc, result.Flags)
+	text.WriteString(loc.T("screening.disclaimer"))
+
+	msg := tgbotapi.NewMessage(chatID, text.String())
+	msg.ParseMode = "HTML"
+	bot.Send(msg)
+}
+
+// writeIngredientFlags перечисляет найденные группы ингредиентов
+// с причиной из анкеты и названиями из состава
+func writeIngredientFlags(text *strings.Builder, loc *i18n.Localizer, flags []services.IngredientFlag) {
+	for _, flag := range flags {
+		reasons := make([]string, len(flag.Reasons))
+		for i, reason := range flag.Reasons {
+			reasons[i] = screeningReasonLabel(loc, flag.Concern, reason)
+		}
+		text.WriteString(loc.T("screening.flag",
+			loc.T("screening.concern."+string(flag.Concern)),
+			html.EscapeString(strings.Join(reasons, ", ")),
+			html.EscapeString(strings.Join(flag.Ingredients, ", "))))
+	}
+}
+
+// safetySeverityIcons значки уровней строгости ограничений при беременности и лактации
+var safetySeverityIcons = map[services.SafetySeverity]string{
+	services.SeverityAvoid:   "🔴",
+	services.SeverityLimit:   "🟠",
+	services.SeverityCaution: "🟡",
+}
+
+// writeSafetyFlags перечисляет активы продукта, ограниченные при беременности
+// и лактации, со строгостью и пояснением
+func writeSafetyFlags(text *strings.Builder, loc *i18n.Localizer, flags []services.SafetyFlag) {
+	if len(flags) == 0 {
+		return
+	}
+
+	text.WriteString(loc.T("safety.header"))
+	for _, flag := range flags {
+		text.WriteString(loc.T("safety.flag",
+			safetySeverityIcons[flag.Severity],
+			loc.T("safety.rule."+flag.Rule+".name"),
+			loc.T("safety.severity."+string(flag.Severity)),
+			html.EscapeString(strings.Join(flag.Ingredients, ", ")),
+			loc.T("safety.rule."+flag.Rule+".text")))
+		if flag.HighDose {
+			text.WriteString(loc.T("safety.high_dose"))
+		}
+	}
+	text.WriteString(loc.T("safety.disclaimer"))
+}
+
+// screeningReasonLabel название ответа анкеты, из-за которого проверялась группа;
+// для названий, указанных пользователем, — само название
+func screeningReasonLabel(loc *i18n.Localizer, concern services.IngredientConcern, reason string) string {
+	if concern == services.ConcernCustom {
+		return reason
+	}
+	for _, id := range []string{"allergies", "diet"} {
+		if question, ok := skincareForm.Question(id); ok && question.Catalog != nil && question.Catalog.Known(reason) {
+			return question.AnswerLabel(loc, reason)
+		}
+	}
+	return reason
+}
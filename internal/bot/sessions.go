@@ -0,0 +1,126 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"cos-ai-bot/internal/config"
+	"cos-ai-bot/internal/database"
+	"cos-ai-bot/internal/models"
+	"cos-ai-bot/internal/session"
+	"cos-ai-bot/migrations"
+)
+
+var sessions session.Store // незавершенные анкеты: userID -> шаг и частичные ответы
+
+// openDatabase открывает подключение к PostgreSQL для хранилищ бота
+// и применяет их миграции. При хранилище memory база не нужна: возвращается nil.
+func openDatabase(ctx context.Context, cfg *config.Config) (*sql.DB, error) {
+	if cfg.SessionStore != config.SessionStorePostgres {
+		return nil, nil
+	}
+	db, err := session.OpenPostgres(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrations.Apply(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// newSessionStore создает хранилище незавершенных анкет: в базе, если она
+// открыта, иначе в памяти
+func newSessionStore(db *sql.DB, ttl time.Duration) session.Store {
+	if db == nil {
+		return session.NewMemoryStore(ttl)
+	}
+	return session.NewPostgresStore(db, ttl)
+}
+
+// expiringStore хранилище, записи которого устаревают через TTL
+type expiringStore interface {
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// cleanupExpired периодически удаляет устаревшие записи хранилища до отмены ctx;
+// what описывает записи в логе, например «брошенных анкет»
+func cleanupExpired(ctx context.Context, store expiringStore, interval time.Duration, what string) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := store.DeleteExpired(ctx)
+			if err != nil {
+				log.Printf("Ошибка очистки %s: %v", what, err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("Удалено %s: %d", what, deleted)
+			}
+		}
+	}
+}
+
+// getFormSession возвращает незавершенную анкету пользователя, если она есть
+func getFormSession(ctx context.Context, chatID int64) (*models.UserState, bool) {
+	state, err := sessions.Get(ctx, chatID)
+	if err != nil {
+		if !errors.Is(err, session.ErrNotFound) {
+			log.Printf("Ошибка получения сессии анкеты пользователя %d: %v", chatID, err)
+		}
+		return nil, false
+	}
+	return state, true
+}
+
+// getUserState получает состояние анкеты: незавершенную сессию,
+// а если ее нет — сохраненный профиль из API
+func getUserState(ctx context.Context, chatID int64) *models.UserState {
+	if state, ok := getFormSession(ctx, chatID); ok {
+		log.Printf("Используем сессию анкеты пользователя %d: шаг %d", chatID, state.Step)
+		return state
+	}
+
+	state, err := database.GetUserState(ctx, chatID)
+	if err != nil {
+		log.Printf("Ошибка получения состояния из API, создаем новое: %v", err)
+		return &models.UserState{Step: 0}
+	}
+	log.Printf("Получено состояние из API для пользователя %d: шаг %d", chatID, state.Step)
+	return state
+}
+
+// saveUserState сохраняет прогресс незавершенной анкеты
+func saveUserState(ctx context.Context, chatID int64, state *models.UserState) {
+	if err := sessions.Save(ctx, chatID, state); err != nil {
+		log.Printf("Ошибка сохранения сессии анкеты пользователя %d: %v", chatID, err)
+		return
+	}
+	log.Printf("Сессия анкеты пользователя %d сохранена: шаг %d", chatID, state.Step)
+}
+
+// deleteFormSession удаляет незавершенную анкету пользователя
+func deleteFormSession(ctx context.Context, chatID int64) {
+	if err := sessions.Delete(ctx, chatID); err != nil {
+		log.Printf("Ошибка удаления сессии анкеты пользователя %d: %v", chatID, err)
+	}
+}
+
+// saveUserProfile сохраняет заполненную анкету в API и закрывает сессию
+func saveUserProfile(ctx context.Context, chatID int64, state *models.UserState) error {
+	if err := database.SaveUserState(ctx, chatID, state); err != nil {
+		return err
+	}
+	deleteFormSession(ctx, chatID)
+	log.Printf("Анкета пользователя %d сохранена в API", chatID)
+	return nil
+}
@@ -0,0 +1,235 @@
+package bot
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// maxCaptionLength ограничение Telegram на длину подписи к фото
+	maxCaptionLength = 1024
+	// partNumberReserve место, оставляемое в каждой части под номер вида «(2/3)»
+	partNumberReserve = 16
+)
+
+// Уровни мест разреза сообщения, от лучшего к худшему
+const (
+	cutParagraph = iota // пустая строка или начало заголовка
+	cutLine             // перевод строки
+	cutWord             // пробел
+	cutAnywhere         // между любыми символами
+)
+
+var (
+	htmlTagPattern    = regexp.MustCompile(`^<(/?)([a-zA-Z-]+)[^>]*>`)
+	htmlEntityPattern = regexp.MustCompile(`^&(?:#[0-9]+|#x[0-9a-fA-F]+|[a-zA-Z]+);`)
+)
+
+// headingMarkers начала строк, которые считаются заголовками: перед ними
+// сообщение режется так же охотно, как по пустой строке
+var headingMarkers = []string{"🔹", "🔸", "<b>"}
+
+// splitHTML делит HTML-текст на части, которые Telegram примет в одном сообщении:
+// первая часть не длиннее first, остальные не длиннее rest символов. Режет по
+// абзацам и заголовкам, а если абзац не помещается — по строкам, словам или
+// символам. Теги, открытые на месте разреза, закрываются в конце части
+// и открываются заново в начале следующей. Если частей несколько, в конце
+// каждой добавляется номер «(n/всего)».
+func splitHTML(text string, first, rest int) []string {
+	if visibleLength(text) <= first {
+		return []string{text}
+	}
+
+	var parts []string
+	limit := first
+	for visibleLength(text) > limit-partNumberReserve {
+		head, tail := cutHTML(text, limit-partNumberReserve)
+		parts = append(parts, head)
+		text, limit = tail, rest
+	}
+	parts = append(parts, text)
+
+	for i := range parts {
+		parts[i] += fmt.Sprintf("\n\n<i>(%d/%d)</i>", i+1, len(parts))
+	}
+	return parts
+}
+
+// cutHTML отрезает от text начало не длиннее limit видимых символов
+func cutHTML(text string, limit int) (head, tail string) {
+	var (
+		stack    []string // открытые теги в порядке открытия
+		length   int      // видимая длина text[:i]
+		best     = [cutAnywhere + 1]int{}
+		bestTags = [cutAnywhere + 1][]string{}
+	)
+
+	i := 0
+	for i < len(text) {
+		// Хорошие места разреза запоминаем, только если часть не выйдет слишком короткой
+		if i > 0 {
+			if level := cutLevel(text, i); length >= limit/2 || level == cutAnywhere {
+				best[level] = i
+				bestTags[level] = append([]string(nil), stack...)
+			}
+		}
+
+		if m := htmlTagPattern.FindStringSubmatch(text[i:]); m != nil {
+			if m[1] == "" {
+				stack = append(stack, m[0])
+			} else if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			i += len(m[0])
+			continue
+		}
+
+		width, size := 1, 0
+		if m := htmlEntityPattern.FindString(text[i:]); m != "" {
+			size = len(m)
+		} else {
+			r, n := utf8.DecodeRuneInString(text[i:])
+			width, size = utf16.RuneLen(r), n
+			if width < 0 {
+				width = 1
+			}
+		}
+		if length+width > limit {
+			break
+		}
+		length += width
+		i += size
+	}
+
+	cut, tags := i, stack
+	for level := cutParagraph; level <= cutAnywhere; level++ {
+		if best[level] > 0 {
+			cut, tags = best[level], bestTags[level]
+			break
+		}
+	}
+
+	head = strings.TrimRight(text[:cut], " \n")
+	for j := len(tags) - 1; j >= 0; j-- {
+		head += "</" + tagName(tags[j]) + ">"
+	}
+	tail = strings.Join(tags, "") + strings.TrimLeft(text[cut:], " \n")
+	return head, tail
+}
+
+// cutLevel оценивает, насколько хорошо резать text перед позицией i.
+// Место сразу после заголовка считается плохим: заголовок не должен
+// отрываться от своего текста.
+func cutLevel(text string, i int) int {
+	switch {
+	case strings.HasSuffix(text[:i], "\n\n"):
+		before := strings.TrimRight(text[:i], "\n")
+		if isHeading(before[strings.LastIndexByte(before, '\n')+1:]) {
+			return cutLine
+		}
+		return cutParagraph
+	case text[i-1] == '\n':
+		if isHeading(text[i:]) {
+			return cutParagraph
+		}
+		return cutLine
+	case text[i-1] == ' ':
+		return cutWord
+	default:
+		return cutAnywhere
+	}
+}
+
+// isHeading проверяет, начинается ли строка с заголовка
+func isHeading(line string) bool {
+	for _, marker := range headingMarkers {
+		if strings.HasPrefix(line, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// tagName возвращает имя тега по открывающему тегу: <a href="..."> → a
+func tagName(openTag string) string {
+	return htmlTagPattern.FindStringSubmatch(openTag)[2]
+}
+
+// visibleLength длина текста так, как ее считает Telegram: без тегов,
+// сущности как один символ, в единицах UTF-16
+func visibleLength(text string) int {
+	return len(utf16.Encode([]rune(htmlToPlain(text))))
+}
+
+// htmlToPlain убирает теги и раскрывает сущности; используется, когда
+// Telegram не принимает HTML
+func htmlToPlain(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		if m := htmlTagPattern.FindString(text[i:]); m != "" {
+			i += len(m)
+			continue
+		}
+		if m := htmlEntityPattern.FindString(text[i:]); m != "" {
+			b.WriteString(html.UnescapeString(m))
+			i += len(m)
+			continue
+		}
+		b.WriteByte(text[i])
+		i++
+	}
+	return b.String()
+}
+
+// sendLongMessage отправляет HTML-текст одним или несколькими сообщениями;
+// клавиатура прикрепляется к последнему
+func sendLongMessage(bot *tgbotapi.BotAPI, chatID int64, text string, keyboard *tgbotapi.InlineKeyboardMarkup) {
+	sendParts(bot, chatID, splitHTML(text, maxMessageLength, maxMessageLength), keyboard)
+}
+
+// sendPhotoWithText отправляет фото с HTML-подписью. Если текст не помещается
+// в подпись, продолжение уходит следующими сообщениями, а клавиатура
+// прикрепляется к последнему из них.
+func sendPhotoWithText(bot *tgbotapi.BotAPI, chatID int64, file tgbotapi.RequestFileData, text string, keyboard *tgbotapi.InlineKeyboardMarkup) {
+	parts := splitHTML(text, maxCaptionLength, maxMessageLength)
+
+	photo := tgbotapi.NewPhoto(chatID, file)
+	photo.Caption = parts[0]
+	photo.ParseMode = "HTML"
+	if len(parts) == 1 && keyboard != nil {
+		photo.ReplyMarkup = *keyboard
+	}
+	if _, err := bot.Send(photo); err != nil {
+		log.Printf("Ошибка отправки фото с HTML подписью: %v", err)
+		photo.Caption = htmlToPlain(parts[0])
+		photo.ParseMode = ""
+		bot.Send(photo)
+	}
+
+	sendParts(bot, chatID, parts[1:], keyboard)
+}
+
+// sendParts отправляет части сообщения по порядку, клавиатуру — с последней.
+// Часть, которую Telegram не принял как HTML, отправляется без разметки.
+func sendParts(bot *tgbotapi.BotAPI, chatID int64, parts []string, keyboard *tgbotapi.InlineKeyboardMarkup) {
+	for i, part := range parts {
+		msg := tgbotapi.NewMessage(chatID, part)
+		msg.ParseMode = "HTML"
+		if i == len(parts)-1 && keyboard != nil {
+			msg.ReplyMarkup = *keyboard
+		}
+		if _, err := bot.Send(msg); err != nil {
+			log.Printf("Ошибка отправки части %d/%d с HTML форматированием: %v", i+1, len(parts), err)
+			msg.Text = htmlToPlain(part)
+			msg.ParseMode = ""
+			bot.Send(msg)
+		}
+	}
+}
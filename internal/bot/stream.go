@@ -0,0 +1,164 @@
+package bot
+
+import (
+	"log"
+	"time"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// streamEditInterval минимальный интервал между правками сообщения,
+	// чтобы не упираться в ограничения Telegram на частоту запросов
+	streamEditInterval = 2 * time.Second
+	// maxMessageLength ограничение Telegram на длину текста сообщения
+	maxMessageLength = 4096
+	// streamCursor добавляется к тексту, пока генерация не закончена
+	streamCursor = " ⏳"
+)
+
+// streamMessage показывает генерируемый текст в одном сообщении,
+// периодически редактируя его по мере генерации
+type streamMessage struct {
+	bot         *tgbotapi.BotAPI
+	chatID      int64
+	messageID   int
+	placeholder string
+	header      string
+	keyboard    *tgbotapi.InlineKeyboardMarkup // кнопки, которые видны во время генерации
+
+	text     string
+	shown    string
+	lastEdit time.Time
+}
+
+// newStreamMessage отправляет начальное сообщение, которое затем будет редактироваться;
+// keyboard показывается под сообщением, пока генерация не закончена
+func newStreamMessage(bot *tgbotapi.BotAPI, chatID int64, placeholder, header string, keyboard *tgbotapi.InlineKeyboardMarkup) *streamMessage {
+	s := &streamMessage{bot: bot, chatID: chatID, placeholder: placeholder, header: header, keyboard: keyboard, lastEdit: time.Now()}
+
+	msg := tgbotapi.NewMessage(chatID, placeholder)
+	if keyboard != nil {
+		msg.ReplyMarkup = *keyboard
+	}
+	sent, err := bot.Send(msg)
+	if err != nil {
+		log.Printf("Ошибка отправки сообщения для потоковой генерации: %v", err)
+		return s
+	}
+	s.messageID = sent.MessageID
+	return s
+}
+
+// Update заменяет показанный текст новым и обновляет сообщение
+// не чаще streamEditInterval. Пустой текст сразу возвращает начальное
+// сообщение: полученный текст отклонен и генерация начинается заново.
+func (s *streamMessage) Update(text string) {
+	if text == "" {
+		s.reset()
+		return
+	}
+	s.text = text
+
+	if s.messageID == 0 || time.Since(s.lastEdit) < streamEditInterval {
+		return
+	}
+	s.flush()
+}
+
+// flush показывает текущий текст без форматирования: незавершенная
+// Markdown-разметка еще не может быть корректно преобразована в HTML
+func (s *streamMessage) flush() {
+	text := truncateTail(s.header+"\n\n"+s.text, maxMessageLength-utf8.RuneCountInString(streamCursor)) + streamCursor
+	if text == s.shown {
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageText(s.chatID, s.messageID, text)
+	edit.ReplyMarkup = s.keyboard
+	if _, err := s.bot.Send(edit); err != nil {
+		log.Printf("Ошибка обновления сообщения при потоковой генерации: %v", err)
+	}
+	s.shown = text
+	s.lastEdit = time.Now()
+}
+
+// reset убирает полученный текст и показывает начальное сообщение
+func (s *streamMessage) reset() {
+	s.text = ""
+	if s.messageID == 0 || s.shown == "" {
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageText(s.chatID, s.messageID, s.placeholder)
+	edit.ReplyMarkup = s.keyboard
+	if _, err := s.bot.Send(edit); err != nil {
+		log.Printf("Ошибка сброса сообщения при потоковой генерации: %v", err)
+	}
+	s.shown = ""
+	s.lastEdit = time.Now()
+}
+
+// Stop завершает неудавшуюся генерацию: уже полученный текст остается
+// в сообщении без курсора и кнопок, а если текста нет, сообщение удаляется
+func (s *streamMessage) Stop() {
+	if s.messageID == 0 {
+		return
+	}
+	if s.text == "" {
+		deleteMessage(s.bot, s.chatID, s.messageID)
+		return
+	}
+
+	text := truncateTail(s.header+"\n\n"+s.text, maxMessageLength)
+	if _, err := s.bot.Send(tgbotapi.NewEditMessageText(s.chatID, s.messageID, text)); err != nil {
+		log.Printf("Ошибка обновления сообщения при остановке генерации: %v", err)
+	}
+}
+
+// Finish заменяет промежуточный текст итоговым HTML-сообщением. Длинный текст
+// делится на части (см. splitHTML): первая заменяет промежуточное сообщение,
+// остальные отправляются следом, клавиатура прикрепляется к последней.
+// Если Telegram отклоняет HTML, часть показывается без разметки; если
+// промежуточное сообщение не удается изменить, оно удаляется и первая
+// часть отправляется новым сообщением.
+func (s *streamMessage) Finish(html string, keyboard tgbotapi.InlineKeyboardMarkup) {
+	parts := splitHTML(html, maxMessageLength, maxMessageLength)
+	if s.messageID != 0 && s.replace(parts[0], len(parts) == 1, keyboard) {
+		parts = parts[1:]
+	}
+	sendParts(s.bot, s.chatID, parts, &keyboard)
+}
+
+// replace заменяет промежуточный текст первой частью итогового;
+// клавиатура прикрепляется, только если частей больше нет
+func (s *streamMessage) replace(part string, last bool, keyboard tgbotapi.InlineKeyboardMarkup) bool {
+	edit := tgbotapi.NewEditMessageText(s.chatID, s.messageID, part)
+	edit.ParseMode = "HTML"
+	if last {
+		edit.ReplyMarkup = &keyboard
+	}
+	_, err := s.bot.Send(edit)
+	if err == nil {
+		return true
+	}
+	log.Printf("Ошибка отправки с HTML форматированием: %v", err)
+
+	edit.Text = htmlToPlain(part)
+	edit.ParseMode = ""
+	if _, err := s.bot.Send(edit); err == nil {
+		return true
+	}
+	deleteMessage(s.bot, s.chatID, s.messageID)
+	return false
+}
+
+// truncateTail обрезает текст до limit символов, сохраняя начало
+func truncateTail(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:limit-1]) + "…"
+}
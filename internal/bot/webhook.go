@@ -0,0 +1,176 @@
+package bot
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"cos-ai-bot/internal/config"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// webhookSecretHeader заголовок, в котором Telegram передает секретный токен вебхука
+const webhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// Ограничения HTTP сервера вебхука: порт публичный, поэтому размер
+// обновления и время чтения запроса ограничены
+const (
+	webhookMaxBodySize       = 1 << 20
+	webhookReadHeaderTimeout = 10 * time.Second
+	webhookReadTimeout       = 30 * time.Second
+	webhookIdleTimeout       = 2 * time.Minute
+)
+
+// setWebhook регистрирует вебхук в Telegram.
+// WebhookConfig из библиотеки не поддерживает secret_token, поэтому запрос собирается вручную.
+func setWebhook(bot *tgbotapi.BotAPI, webhookURL, secret string) error {
+	params := tgbotapi.Params{"url": webhookURL}
+	params["secret_token"] = secret
+
+	if _, err := bot.MakeRequest("setWebhook", params); err != nil {
+		return fmt.Errorf("ошибка регистрации вебхука: %v", err)
+	}
+	return nil
+}
+
+// deleteWebhook удаляет вебхук, чтобы Telegram перестал присылать обновления
+func deleteWebhook(bot *tgbotapi.BotAPI) error {
+	if _, err := bot.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		return fmt.Errorf("ошибка удаления вебхука: %v", err)
+	}
+	return nil
+}
+
+// webhookHandler принимает обновления от Telegram и передает их диспетчеру
+type webhookHandler struct {
+	secret     string
+	dispatcher *Dispatcher
+}
+
+// ServeHTTP проверяет секретный токен, декодирует обновление и ставит его в очередь
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Пустой секрет не пропускает никого: config.Validate требует его в режиме webhook
+	token := r.Header.Get(webhookSecretHeader)
+	if h.secret == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.secret)) != 1 {
+		log.Printf("[WEBHOOK] Запрос с неверным секретным токеном от %s", r.RemoteAddr)
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var update tgbotapi.Update
+	r.Body = http.MaxBytesReader(w, r.Body, webhookMaxBodySize)
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		log.Printf("[WEBHOOK] Ошибка декодирования обновления: %v", err)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "request entity too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	if update.Message != nil || update.CallbackQuery != nil || update.InlineQuery != nil {
+		if err := h.dispatcher.Dispatch(r.Context(), update); err != nil {
+			// Telegram повторит доставку, если не получит 200
+			http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// runWebhook регистрирует вебхук и принимает обновления по HTTP на cfg.Port до отмены ctx
+func runWebhook(ctx context.Context, bot *tgbotapi.BotAPI, cfg *config.Config, dispatcher *Dispatcher) error {
+	webhookURL, err := url.Parse(cfg.WebhookURL)
+	if err != nil {
+		return fmt.Errorf("неверный WEBHOOK_URL: %v", err)
+	}
+	path := webhookURL.Path
+	if path == "" {
+		path = "/"
+	}
+
+	if err := setWebhook(bot, cfg.WebhookURL, cfg.WebhookSecret); err != nil {
+		return err
+	}
+	defer func() {
+		if err := deleteWebhook(bot); err != nil {
+			log.Printf("%v", err)
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.Handle(path, &webhookHandler{secret: cfg.WebhookSecret, dispatcher: dispatcher})
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Port),
+		Handler:           mux,
+		ReadHeaderTimeout: webhookReadHeaderTimeout,
+		ReadTimeout:       webhookReadTimeout,
+		IdleTimeout:       webhookIdleTimeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	log.Printf("Вебхук зарегистрирован, слушаем порт %d, путь %s", cfg.Port, path)
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("Останавливаем HTTP сервер вебхука")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// runPolling получает обновления через long polling до отмены ctx
+func runPolling(ctx context.Context, bot *tgbotapi.BotAPI, dispatcher *Dispatcher) error {
+	// getUpdates не работает, пока зарегистрирован вебхук
+	if err := deleteWebhook(bot); err != nil {
+		return err
+	}
+
+	u := tgbotapi.NewUpdate(0)
+	u.Timeout = 60
+
+	updates := bot.GetUpdatesChan(u)
+	defer bot.StopReceivingUpdates()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Останавливаем получение обновлений")
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			if update.Message == nil && update.CallbackQuery == nil && update.InlineQuery == nil {
+				continue
+			}
+
+			if err := dispatcher.Dispatch(ctx, update); err != nil {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Package catalog описывает справочники ответов анкеты: стабильные коды,
+// которые хранятся в состоянии, и человекочитаемые названия для профиля и промптов.
+package catalog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Value значение справочника
+type Value[T ~string] struct {
+	Code  T
+	Label string
+}
+
+// Enum справочник значений одного типа с разбором в обе стороны:
+// код → название и название → код
+type Enum[T ~string] struct {
+	name    string
+	values  []Value[T]
+	byCode  map[T]int
+	byLabel map[string]int
+}
+
+// newEnum создает справочник. Паникует при повторе кода или названия —
+// это ошибка в описании справочника.
+func newEnum[T ~string](name string, values ...Value[T]) *Enum[T] {
+	e := &Enum[T]{
+		name:    name,
+		values:  values,
+		byCode:  make(map[T]int, len(values)),
+		byLabel: make(map[string]int, len(values)),
+	}
+	for i, value := range values {
+		if _, exists := e.byCode[value.Code]; exists {
+			panic(fmt.Sprintf("catalog: повторный код %q в справочнике %s", value.Code, name))
+		}
+		key := normalizeLabel(value.Label)
+		if _, exists := e.byLabel[key]; exists {
+			panic(fmt.Sprintf("catalog: повторное название %q в справочнике %s", value.Label, name))
+		}
+		e.byCode[value.Code] = i
+		e.byLabel[key] = i
+	}
+	return e
+}
+
+// Name возвращает имя справочника
+func (e *Enum[T]) Name() string {
+	return e.name
+}
+
+// Values возвращает значения в порядке объявления
+func (e *Enum[T]) Values() []Value[T] {
+	return e.values
+}
+
+// Valid проверяет, что код есть в справочнике
+func (e *Enum[T]) Valid(code T) bool {
+	_, ok := e.byCode[code]
+	return ok
+}
+
+// Label возвращает название по коду; для неизвестного кода — сам код
+func (e *Enum[T]) Label(code T) string {
+	if i, ok := e.byCode[code]; ok {
+		return e.values[i].Label
+	}
+	return string(code)
+}
+
+// Parse разбирает код или название значения.
+// Название сравнивается без учета регистра и пробелов по краям.
+func (e *Enum[T]) Parse(s string) (T, bool) {
+	if _, ok := e.byCode[T(s)]; ok {
+		return T(s), true
+	}
+	if i, ok := e.byLabel[normalizeLabel(s)]; ok {
+		return e.values[i].Code, true
+	}
+	return "", false
+}
+
+// Known проверяет, что строка — код из справочника
+func (e *Enum[T]) Known(code string) bool {
+	return e.Valid(T(code))
+}
+
+// LabelOf возвращает название по коду в виде строки.
+// Используется там, где код хранится как string, например в models.UserState.
+func (e *Enum[T]) LabelOf(code string) string {
+	return e.Label(T(code))
+}
+
+// CodeOf возвращает код по коду или названию; нераспознанное значение
+// возвращается без изменений, чтобы не потерять ответ
+func (e *Enum[T]) CodeOf(s string) string {
+	if code, ok := e.Parse(s); ok {
+		return string(code)
+	}
+	return s
+}
+
+// LabelsOf преобразует список кодов в названия
+func (e *Enum[T]) LabelsOf(codes []string) []string {
+	return mapList(codes, e.LabelOf)
+}
+
+// CodesOf преобразует список названий или кодов в коды
+func (e *Enum[T]) CodesOf(labels []string) []string {
+	return mapList(labels, e.CodeOf)
+}
+
+// Dictionary справочник без привязки к типу значения
+type Dictionary interface {
+	Name() string
+	Known(code string) bool
+	LabelOf(code string) string
+	CodeOf(s string) string
+}
+
+// mapList применяет fn к каждому элементу; пустой список остается nil
+func mapList(values []string, fn func(string) string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = fn(value)
+	}
+	return result
+}
+
+// normalizeLabel приводит название к виду для сравнения
+func normalizeLabel(label string) string {
+	return strings.ToLower(strings.TrimSpace(label))
+}
@@ -0,0 +1,235 @@
+package catalog
+
+// SkinType тип кожи
+type SkinType string
+
+const (
+	SkinDry       SkinType = "skin_dry"
+	SkinOily      SkinType = "skin_oily"
+	SkinNormal    SkinType = "skin_normal"
+	SkinSensitive SkinType = "skin_sensitive"
+	SkinCombined  SkinType = "skin_combined"
+	SkinUnknown   SkinType = "skin_unknown"
+)
+
+// SkinTypes справочник типов кожи
+var SkinTypes = newEnum("skin_type",
+	Value[SkinType]{SkinDry, "Сухая"},
+	Value[SkinType]{SkinOily, "Жирная"},
+	Value[SkinType]{SkinNormal, "Нормальная"},
+	Value[SkinType]{SkinSensitive, "Чувствительная"},
+	Value[SkinType]{SkinCombined, "Комбинированная"},
+	Value[SkinType]{SkinUnknown, "Не знаю"},
+)
+
+// AgeGroup возрастная группа
+type AgeGroup string
+
+const (
+	AgeUnder18 AgeGroup = "age_18_minus"
+	Age18To24  AgeGroup = "age_18_24"
+	Age25To34  AgeGroup = "age_25_34"
+	Age35To44  AgeGroup = "age_35_44"
+	Age45Plus  AgeGroup = "age_45_plus"
+	AgeIgnore  AgeGroup = "age_ignore"
+)
+
+// AgeGroups справочник возрастных групп
+var AgeGroups = newEnum("age",
+	Value[AgeGroup]{AgeUnder18, "До 18 лет"},
+	Value[AgeGroup]{Age18To24, "18-24 года"},
+	Value[AgeGroup]{Age25To34, "25-34 года"},
+	Value[AgeGroup]{Age35To44, "35-44 года"},
+	Value[AgeGroup]{Age45Plus, "45+ лет"},
+	Value[AgeGroup]{AgeIgnore, "Не учитывать"},
+)
+
+// Gender пол
+type Gender string
+
+const (
+	GenderMale   Gender = "gender_male"
+	GenderFemale Gender = "gender_female"
+	GenderOther  Gender = "gender_other"
+	GenderIgnore Gender = "gender_ignore"
+)
+
+// Genders справочник значений пола
+var Genders = newEnum("gender",
+	Value[Gender]{GenderMale, "Мужчина"},
+	Value[Gender]{GenderFemale, "Женщина"},
+	Value[Gender]{GenderOther, "Другое"},
+	Value[Gender]{GenderIgnore, "Не учитывать"},
+)
+
+// Pregnancy беременность и лактация
+type Pregnancy string
+
+const (
+	PregnancyPregnant  Pregnancy = "pregnancy"
+	PregnancyLactation Pregnancy = "lactation"
+	PregnancyBoth      Pregnancy = "pregnancy_and_lactation"
+	PregnancyNone      Pregnancy = "none_of_above"
+	PregnancyIgnore    Pregnancy = "pregnancy_ignore"
+)
+
+// PregnancyStatuses справочник значений беременности и лактации
+var PregnancyStatuses = newEnum("pregnancy",
+	Value[Pregnancy]{PregnancyPregnant, "Беременность"},
+	Value[Pregnancy]{PregnancyLactation, "Лактация"},
+	Value[Pregnancy]{PregnancyBoth, "Беременность и лактация"},
+	Value[Pregnancy]{PregnancyNone, "Ничего из перечисленного"},
+	Value[Pregnancy]{PregnancyIgnore, "Не учитывать"},
+)
+
+// Goal цель ухода
+type Goal string
+
+const (
+	GoalHydration  Goal = "goal_hydration"
+	GoalTone       Goal = "goal_tone"
+	GoalAntiage    Goal = "goal_antiage"
+	GoalTexture    Goal = "goal_texture"
+	GoalRefresh    Goal = "goal_refresh"
+	GoalMinimalism Goal = "goal_minimalism"
+	GoalOther      Goal = "goal_other"
+)
+
+// Goals справочник целей ухода
+var Goals = newEnum("goal",
+	Value[Goal]{GoalHydration, "Увлажнение и питание"},
+	Value[Goal]{GoalTone, "Выравнивание тона"},
+	Value[Goal]{GoalAntiage, "Антивозрастной уход"},
+	Value[Goal]{GoalTexture, "Улучшение текстуры"},
+	Value[Goal]{GoalRefresh, "Освежить и поддерживать"},
+	Value[Goal]{GoalMinimalism, "Минимализм, только базовый уход"},
+	Value[Goal]{GoalOther, "Другое"},
+)
+
+// Climate климат
+type Climate string
+
+const (
+	ClimateDry       Climate = "climate_dry"
+	ClimateHumid     Climate = "climate_humid"
+	ClimateHot       Climate = "climate_hot"
+	ClimateCold      Climate = "climate_cold"
+	ClimateTemperate Climate = "climate_temperate"
+	ClimatePolluted  Climate = "climate_polluted"
+	ClimateMultiple  Climate = "climate_multiple"
+	ClimateUnknown   Climate = "climate_unknown"
+)
+
+// Climates справочник климатов
+var Climates = newEnum("climate",
+	Value[Climate]{ClimateDry, "Сухой"},
+	Value[Climate]{ClimateHumid, "Влажный"},
+	Value[Climate]{ClimateHot, "Жаркий"},
+	Value[Climate]{ClimateCold, "Холодный"},
+	Value[Climate]{ClimateTemperate, "Переменный / умеренный"},
+	Value[Climate]{ClimatePolluted, "Загрязнённый (город, смог, пыль)"},
+	Value[Climate]{ClimateMultiple, "Живу в нескольких климатах (путешествую/переезды)"},
+	Value[Climate]{ClimateUnknown, "Не знаю"},
+)
+
+// Fitzpatrick фототип кожи по Фицпатрику
+type Fitzpatrick string
+
+const (
+	Fitzpatrick1       Fitzpatrick = "fitzpatrick_1"
+	Fitzpatrick2       Fitzpatrick = "fitzpatrick_2"
+	Fitzpatrick3       Fitzpatrick = "fitzpatrick_3"
+	Fitzpatrick4       Fitzpatrick = "fitzpatrick_4"
+	Fitzpatrick5       Fitzpatrick = "fitzpatrick_5"
+	Fitzpatrick6       Fitzpatrick = "fitzpatrick_6"
+	FitzpatrickUnknown Fitzpatrick = "fitzpatrick_unknown"
+)
+
+// FitzpatrickTypes справочник фототипов
+var FitzpatrickTypes = newEnum("fitzpatrick",
+	Value[Fitzpatrick]{Fitzpatrick1, "I – очень светлая, всегда обгорает"},
+	Value[Fitzpatrick]{Fitzpatrick2, "II – светлая, обгорает, но может немного загорать"},
+	Value[Fitzpatrick]{Fitzpatrick3, "III – светло-смуглая, легко загорает"},
+	Value[Fitzpatrick]{Fitzpatrick4, "IV – смуглая, редко обгорает"},
+	Value[Fitzpatrick]{Fitzpatrick5, "V – тёмная, почти не обгорает"},
+	Value[Fitzpatrick]{Fitzpatrick6, "VI – очень тёмная, никогда не обгорает"},
+	Value[Fitzpatrick]{FitzpatrickUnknown, "Не знаю / Не хочу указывать"},
+)
+
+// Lifestyle особенность образа жизни
+type Lifestyle string
+
+const (
+	LifestyleStress   Lifestyle = "lifestyle_stress"
+	LifestyleSleep    Lifestyle = "lifestyle_sleep"
+	LifestyleScreen   Lifestyle = "lifestyle_screen"
+	LifestyleSweat    Lifestyle = "lifestyle_sweat"
+	LifestyleComputer Lifestyle = "lifestyle_computer"
+	LifestyleActive   Lifestyle = "lifestyle_active"
+	LifestyleOutdoor  Lifestyle = "lifestyle_outdoor"
+	LifestylePassive  Lifestyle = "lifestyle_passive"
+	LifestyleOther    Lifestyle = "lifestyle_other"
+)
+
+// Lifestyles справочник особенностей образа жизни
+var Lifestyles = newEnum("lifestyle",
+	Value[Lifestyle]{LifestyleStress, "Частые стрессы"},
+	Value[Lifestyle]{LifestyleSleep, "Недосып / сбитый режим"},
+	Value[Lifestyle]{LifestyleScreen, "Много экранного времени"},
+	Value[Lifestyle]{LifestyleSweat, "Часто потею (спорт, жара и т.д.)"},
+	Value[Lifestyle]{LifestyleComputer, "Работаю за компьютером"},
+	Value[Lifestyle]{LifestyleActive, "Активно двигаюсь в течение дня"},
+	Value[Lifestyle]{LifestyleOutdoor, "Регулярно на улице"},
+	Value[Lifestyle]{LifestylePassive, "Пассивный / домашний образ жизни"},
+	Value[Lifestyle]{LifestyleOther, "Другое"},
+)
+
+// Diet особенность питания или убеждения
+type Diet string
+
+const (
+	DietVegan      Diet = "diet_vegan"
+	DietVegetarian Diet = "diet_vegetarian"
+	DietHalal      Diet = "diet_halal"
+	DietKeto       Diet = "diet_keto"
+	DietGlutenFree Diet = "diet_gluten_free"
+	DietNoAlcohol  Diet = "diet_no_alcohol"
+	DietNoAnimal   Diet = "diet_no_animal"
+	DietNone       Diet = "diet_none"
+	DietOther      Diet = "diet_other"
+)
+
+// Diets справочник особенностей питания
+var Diets = newEnum("diet",
+	Value[Diet]{DietVegan, "Веганство"},
+	Value[Diet]{DietVegetarian, "Вегетарианство"},
+	Value[Diet]{DietHalal, "Халяль"},
+	Value[Diet]{DietKeto, "Кето / Палео / Низкоуглеводная"},
+	Value[Diet]{DietGlutenFree, "Безглютеновая"},
+	Value[Diet]{DietNoAlcohol, "Я избегаю спирта в составе"},
+	Value[Diet]{DietNoAnimal, "Я избегаю компонентов животного происхождения"},
+	Value[Diet]{DietNone, "Нет особых ограничений"},
+	Value[Diet]{DietOther, "Другое"},
+)
+
+// Allergy аллергия или непереносимость
+type Allergy string
+
+const (
+	AllergyNone          Allergy = "allergies_none"
+	AllergyNickel        Allergy = "allergies_nickel"
+	AllergyLanolin       Allergy = "allergies_lanolin"
+	AllergyFragrance     Allergy = "allergies_fragrance"
+	AllergyPreservatives Allergy = "allergies_preservatives"
+	AllergyOther         Allergy = "allergies_other"
+)
+
+// Allergies справочник аллергий
+var Allergies = newEnum("allergies",
+	Value[Allergy]{AllergyNone, "Нет аллергий"},
+	Value[Allergy]{AllergyNickel, "Аллергия на никель"},
+	Value[Allergy]{AllergyLanolin, "Аллергия на ланолин"},
+	Value[Allergy]{AllergyFragrance, "Аллергия на отдушки"},
+	Value[Allergy]{AllergyPreservatives, "Аллергия на консерванты"},
+	Value[Allergy]{AllergyOther, "Другое"},
+)
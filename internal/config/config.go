@@ -3,8 +3,37 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
+// Режимы получения обновлений от Telegram
+const (
+	UpdateModePolling = "polling"
+	UpdateModeWebhook = "webhook"
+)
+
+// Провайдеры языковой модели
+const (
+	LLMProviderOpenRouter = "openrouter"
+	LLMProviderOpenAI     = "openai"
+	LLMProviderFake       = "fake"
+)
+
+// Хранилища незавершенных анкет
+const (
+	SessionStoreMemory   = "memory"
+	SessionStorePostgres = "postgres"
+)
+
+// ModelSettings параметры модели для одного типа рекомендаций
+type ModelSettings struct {
+	Model          string
+	FallbackModels []string // пробуются по порядку, если основная модель недоступна
+	Temperature    float64
+	MaxTokens      int
+}
+
 // Config содержит конфигурацию приложения
 type Config struct {
 	BotToken         string
@@ -13,6 +42,29 @@ type Config struct {
 	OpenRouterAPIKey string
 	Debug            bool
 	Port             int
+	Workers          int // максимум одновременно обрабатываемых чатов
+	QueueSize        int // максимум обновлений, ожидающих обработки
+	UpdateMode       string
+	WebhookURL       string
+	WebhookSecret    string
+	ShutdownTimeout  time.Duration // сколько ждать завершения начатой работы при остановке
+
+	LLMProvider   string // openrouter, openai или fake
+	LLMBaseURL    string // адрес OpenAI-совместимого API для провайдера openai
+	LLMAPIKey     string // ключ OpenAI-совместимого API для провайдера openai
+	AnketaModel   ModelSettings
+	ProductsModel ModelSettings
+	GeneralModel  ModelSettings
+
+	LLMMaxAttempts        int           // попыток на одну модель, включая первую
+	LLMRetryBaseDelay     time.Duration // задержка перед первым повтором
+	LLMRetryMaxDelay      time.Duration // верхняя граница задержки между повторами
+	LLMCacheTTL           time.Duration // сколько хранить ответы модели для одинаковых запросов; 0 — не кэшировать
+	LLMCacheStatsInterval time.Duration // как часто писать в лог счетчики кэша ответов модели
+
+	SessionStore           string        // memory или postgres: где хранить анкеты, настройки пользователей, историю рекомендаций и кэш ответов модели
+	SessionTTL             time.Duration // через сколько незавершенная анкета считается брошенной
+	SessionCleanupInterval time.Duration // как часто удалять брошенные анкеты
 }
 
 // Load загружает конфигурацию из переменных окружения
@@ -22,17 +74,130 @@ func Load() *Config {
 	if port == 0 {
 		port = 8080
 	}
+	workers, _ := strconv.Atoi(os.Getenv("BOT_WORKERS"))
+	if workers <= 0 {
+		workers = 16
+	}
+	queueSize, _ := strconv.Atoi(os.Getenv("BOT_QUEUE_SIZE"))
+	if queueSize <= 0 {
+		queueSize = 256
+	}
+
+	shutdownTimeout, err := time.ParseDuration(os.Getenv("SHUTDOWN_TIMEOUT"))
+	if err != nil || shutdownTimeout <= 0 {
+		shutdownTimeout = 30 * time.Second
+	}
+	updateMode := os.Getenv("UPDATE_MODE")
+	if updateMode == "" {
+		updateMode = UpdateModePolling
+	}
+
+	llmProvider := os.Getenv("LLM_PROVIDER")
+	if llmProvider == "" {
+		llmProvider = LLMProviderOpenRouter
+	}
+	defaultModel := loadModelSettings("LLM", ModelSettings{
+		Model:       "deepseek/deepseek-r1",
+		Temperature: 0.7,
+		MaxTokens:   4000,
+	})
+
+	llmMaxAttempts, err := strconv.Atoi(os.Getenv("LLM_MAX_ATTEMPTS"))
+	if err != nil || llmMaxAttempts <= 0 {
+		llmMaxAttempts = 3
+	}
+	llmRetryBaseDelay, err := time.ParseDuration(os.Getenv("LLM_RETRY_BASE_DELAY"))
+	if err != nil || llmRetryBaseDelay <= 0 {
+		llmRetryBaseDelay = time.Second
+	}
+	llmRetryMaxDelay, err := time.ParseDuration(os.Getenv("LLM_RETRY_MAX_DELAY"))
+	if err != nil || llmRetryMaxDelay <= 0 {
+		llmRetryMaxDelay = 20 * time.Second
+	}
+	llmCacheTTL := 24 * time.Hour
+	if value := os.Getenv("LLM_CACHE_TTL"); value != "" {
+		if ttl, err := time.ParseDuration(value); err == nil && ttl >= 0 {
+			llmCacheTTL = ttl
+		}
+	}
+	llmCacheStatsInterval, err := time.ParseDuration(os.Getenv("LLM_CACHE_STATS_INTERVAL"))
+	if err != nil || llmCacheStatsInterval <= 0 {
+		llmCacheStatsInterval = time.Hour
+	}
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	sessionStore := os.Getenv("SESSION_STORE")
+	if sessionStore == "" {
+		sessionStore = SessionStoreMemory
+		if databaseURL != "" {
+			sessionStore = SessionStorePostgres
+		}
+	}
+	sessionTTL, err := time.ParseDuration(os.Getenv("SESSION_TTL"))
+	if err != nil || sessionTTL <= 0 {
+		sessionTTL = 72 * time.Hour
+	}
+	sessionCleanupInterval, err := time.ParseDuration(os.Getenv("SESSION_CLEANUP_INTERVAL"))
+	if err != nil || sessionCleanupInterval <= 0 {
+		sessionCleanupInterval = time.Hour
+	}
 
 	return &Config{
-		BotToken:         os.Getenv("BOT_TOKEN"),
-		DatabaseURL:      os.Getenv("DATABASE_URL"),
-		APIURL:           os.Getenv("API_URL"),
-		OpenRouterAPIKey: os.Getenv("OPENROUTER_API_KEY"),
-		Debug:            debug,
-		Port:             port,
+		BotToken:              os.Getenv("BOT_TOKEN"),
+		DatabaseURL:           databaseURL,
+		APIURL:                os.Getenv("API_URL"),
+		OpenRouterAPIKey:      os.Getenv("OPENROUTER_API_KEY"),
+		Debug:                 debug,
+		Port:                  port,
+		Workers:               workers,
+		QueueSize:             queueSize,
+		UpdateMode:            updateMode,
+		WebhookURL:            os.Getenv("WEBHOOK_URL"),
+		WebhookSecret:         os.Getenv("WEBHOOK_SECRET"),
+		ShutdownTimeout:       shutdownTimeout,
+		LLMProvider:           llmProvider,
+		LLMBaseURL:            os.Getenv("LLM_BASE_URL"),
+		LLMAPIKey:             os.Getenv("LLM_API_KEY"),
+		AnketaModel:           loadModelSettings("LLM_ANKETA", defaultModel),
+		ProductsModel:         loadModelSettings("LLM_PRODUCTS", defaultModel),
+		GeneralModel:          loadModelSettings("LLM_GENERAL", defaultModel),
+		LLMMaxAttempts:        llmMaxAttempts,
+		LLMRetryBaseDelay:     llmRetryBaseDelay,
+		LLMRetryMaxDelay:      llmRetryMaxDelay,
+		LLMCacheTTL:           llmCacheTTL,
+		LLMCacheStatsInterval: llmCacheStatsInterval,
+
+		SessionStore:           sessionStore,
+		SessionTTL:             sessionTTL,
+		SessionCleanupInterval: sessionCleanupInterval,
 	}
 }
 
+// loadModelSettings читает PREFIX_MODEL, PREFIX_FALLBACK_MODELS (через запятую),
+// PREFIX_TEMPERATURE и PREFIX_MAX_TOKENS, подставляя значения из defaults
+// для незаданных переменных
+func loadModelSettings(prefix string, defaults ModelSettings) ModelSettings {
+	settings := defaults
+	if model := os.Getenv(prefix + "_MODEL"); model != "" {
+		settings.Model = model
+	}
+	if fallbacks := os.Getenv(prefix + "_FALLBACK_MODELS"); fallbacks != "" {
+		settings.FallbackModels = nil
+		for _, model := range strings.Split(fallbacks, ",") {
+			if model = strings.TrimSpace(model); model != "" {
+				settings.FallbackModels = append(settings.FallbackModels, model)
+			}
+		}
+	}
+	if temperature, err := strconv.ParseFloat(os.Getenv(prefix+"_TEMPERATURE"), 64); err == nil {
+		settings.Temperature = temperature
+	}
+	if maxTokens, err := strconv.Atoi(os.Getenv(prefix + "_MAX_TOKENS")); err == nil && maxTokens > 0 {
+		settings.MaxTokens = maxTokens
+	}
+	return settings
+}
+
 // Validate проверяет корректность конфигурации
 func (c *Config) Validate() error {
 	if c.BotToken == "" {
@@ -41,8 +206,40 @@ func (c *Config) Validate() error {
 	if c.APIURL == "" {
 		return ErrMissingAPIURL
 	}
-	if c.OpenRouterAPIKey == "" {
-		return ErrMissingOpenRouterAPIKey
+	switch c.LLMProvider {
+	case LLMProviderOpenRouter:
+		if c.OpenRouterAPIKey == "" {
+			return ErrMissingOpenRouterAPIKey
+		}
+	case LLMProviderOpenAI:
+		if c.LLMBaseURL == "" {
+			return ErrMissingLLMBaseURL
+		}
+	case LLMProviderFake:
+	default:
+		return ErrInvalidLLMProvider
+	}
+	switch c.UpdateMode {
+	case UpdateModePolling:
+	case UpdateModeWebhook:
+		if c.WebhookURL == "" {
+			return ErrMissingWebhookURL
+		}
+		// Без секрета любой, кто узнал адрес вебхука, сможет присылать обновления
+		if c.WebhookSecret == "" {
+			return ErrMissingWebhookSecret
+		}
+	default:
+		return ErrInvalidUpdateMode
+	}
+	switch c.SessionStore {
+	case SessionStoreMemory:
+	case SessionStorePostgres:
+		if c.DatabaseURL == "" {
+			return ErrMissingDatabaseURL
+		}
+	default:
+		return ErrInvalidSessionStore
 	}
 	return nil
 }
@@ -52,6 +249,13 @@ var (
 	ErrMissingBotToken         = &ConfigError{"BOT_TOKEN не установлен"}
 	ErrMissingAPIURL           = &ConfigError{"API_URL не установлен"}
 	ErrMissingOpenRouterAPIKey = &ConfigError{"OPENROUTER_API_KEY не установлен"}
+	ErrMissingWebhookURL       = &ConfigError{"WEBHOOK_URL не установлен для режима webhook"}
+	ErrMissingWebhookSecret    = &ConfigError{"WEBHOOK_SECRET не установлен для режима webhook"}
+	ErrInvalidUpdateMode       = &ConfigError{"UPDATE_MODE должен быть polling или webhook"}
+	ErrMissingLLMBaseURL       = &ConfigError{"LLM_BASE_URL не установлен для провайдера openai"}
+	ErrInvalidLLMProvider      = &ConfigError{"LLM_PROVIDER должен быть openrouter, openai или fake"}
+	ErrMissingDatabaseURL      = &ConfigError{"DATABASE_URL не установлен для хранилища сессий postgres"}
+	ErrInvalidSessionStore     = &ConfigError{"SESSION_STORE должен быть memory или postgres"}
 )
 
 // ConfigError представляет ошибку конфигурации
@@ -0,0 +1,92 @@
+package history
+
+import "strings"
+
+// LineOp вид изменения строки текста рекомендации
+type LineOp int
+
+// Виды изменений строки
+const (
+	LineRemoved LineOp = iota
+	LineAdded
+)
+
+// LineChange строка, которая есть только в одной из сравниваемых рекомендаций
+type LineChange struct {
+	Op   LineOp
+	Text string
+}
+
+// DiffProducts сравнивает коллекции двух запусков по ID продукта
+func DiffProducts(before, after []Product) (added, removed []Product) {
+	index := func(products []Product) map[int]bool {
+		ids := make(map[int]bool, len(products))
+		for _, product := range products {
+			ids[product.ID] = true
+		}
+		return ids
+	}
+	beforeIDs, afterIDs := index(before), index(after)
+
+	for _, product := range after {
+		if !beforeIDs[product.ID] {
+			added = append(added, product)
+		}
+	}
+	for _, product := range before {
+		if !afterIDs[product.ID] {
+			removed = append(removed, product)
+		}
+	}
+	return added, removed
+}
+
+// DiffLines сравнивает тексты двух рекомендаций построчно и возвращает
+// удаленные и добавленные строки в порядке следования. Пустые строки
+// и отличия только в пробелах по краям не учитываются.
+func DiffLines(before, after string) []LineChange {
+	a, b := contentLines(before), contentLines(after)
+
+	// Длины наибольших общих подпоследовательностей суффиксов a[i:] и b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var changes []LineChange
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			changes = append(changes, LineChange{Op: LineRemoved, Text: a[i]})
+			i++
+		default:
+			changes = append(changes, LineChange{Op: LineAdded, Text: b[j]})
+			j++
+		}
+	}
+	return changes
+}
+
+// contentLines непустые строки текста без пробелов по краям
+func contentLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
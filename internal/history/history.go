@@ -0,0 +1,56 @@
+// Package history хранит выданные пользователю рекомендации вместе с данными,
+// на основе которых они были составлены, и сравнивает запуски между собой.
+package history
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cos-ai-bot/internal/models"
+	"cos-ai-bot/internal/routine"
+)
+
+// ErrNotFound возвращается, если записи нет или она принадлежит другому пользователю
+var ErrNotFound = errors.New("рекомендация не найдена в истории")
+
+// Product продукт из коллекции пользователя на момент генерации
+type Product struct {
+	ID    int    `json:"id"`
+	Brand string `json:"brand"`
+	Title string `json:"title"`
+}
+
+// Record сохраненная рекомендация
+type Record struct {
+	ID        int64
+	UserID    int64
+	Type      string            // тип рекомендаций: anketa, products, general
+	Profile   *models.UserState // анкета на момент генерации, в кодах ответов
+	Products  []Product         // коллекция на момент генерации; nil, если не использовалась
+	Model     string            // модель, которая фактически ответила
+	Content   string            // текст рекомендации в Markdown, как его увидел пользователь
+	Routine   *routine.Routine  // план ухода из ответа модели; nil для записей до перехода на план
+	CreatedAt time.Time
+}
+
+// Store хранит историю рекомендаций
+type Store interface {
+	// Add сохраняет запись и заполняет ее ID и CreatedAt
+	Add(ctx context.Context, record *Record) error
+	// Get возвращает запись пользователя или ErrNotFound
+	Get(ctx context.Context, userID, id int64) (*Record, error)
+	// Previous возвращает предыдущую запись того же типа или ErrNotFound
+	Previous(ctx context.Context, record *Record) (*Record, error)
+	// List возвращает записи пользователя от новых к старым и общее их количество
+	List(ctx context.Context, userID int64, offset, limit int) ([]Record, int, error)
+}
+
+// ProductsSnapshot сохраняет из коллекции пользователя то, что нужно для сравнения
+func ProductsSnapshot(products []models.APIUserProduct) []Product {
+	snapshot := make([]Product, len(products))
+	for i, product := range products {
+		snapshot[i] = Product{ID: product.ProductID, Brand: product.Brand, Title: product.Title}
+	}
+	return snapshot
+}
@@ -0,0 +1,73 @@
+package history
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore потокобезопасное хранилище истории в памяти процесса.
+// Не переживает перезапуск; используется, когда база данных не настроена.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	nextID  int64
+	records map[int64][]Record // userID -> записи в порядке добавления
+}
+
+// NewMemoryStore создает хранилище истории в памяти
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[int64][]Record)}
+}
+
+// Add сохраняет запись
+func (s *MemoryStore) Add(ctx context.Context, record *Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	record.ID = s.nextID
+	record.CreatedAt = time.Now()
+	s.records[record.UserID] = append(s.records[record.UserID], *record)
+	return nil
+}
+
+// Get возвращает запись пользователя
+func (s *MemoryStore) Get(ctx context.Context, userID, id int64) (*Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, record := range s.records[userID] {
+		if record.ID == id {
+			return &record, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Previous возвращает предыдущую запись того же типа
+func (s *MemoryStore) Previous(ctx context.Context, record *Record) (*Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := s.records[record.UserID]
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].ID < record.ID && records[i].Type == record.Type {
+			previous := records[i]
+			return &previous, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// List возвращает записи пользователя от новых к старым
+func (s *MemoryStore) List(ctx context.Context, userID int64, offset, limit int) ([]Record, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := s.records[userID]
+	var page []Record
+	for i := len(records) - 1 - offset; i >= 0 && len(page) < limit; i-- {
+		page = append(page, records[i])
+	}
+	return page, len(records), nil
+}
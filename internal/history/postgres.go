@@ -0,0 +1,145 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// PostgresStore хранилище истории в таблице recommendation_history
+// (см. migrations/004_recommendation_history.sql и 006_recommendation_routine.sql)
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore создает хранилище поверх открытого подключения
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// recordColumns колонки записи в порядке, который ожидает scanRecord
+const recordColumns = `id, user_id, type, profile, products, model, content, routine, created_at`
+
+// Add сохраняет запись
+func (s *PostgresStore) Add(ctx context.Context, record *Record) error {
+	profile, err := json.Marshal(record.Profile)
+	if err != nil {
+		return fmt.Errorf("ошибка маршалинга анкеты для истории: %v", err)
+	}
+	var products any // NULL, если коллекция в рекомендации не использовалась
+	if record.Products != nil {
+		data, err := json.Marshal(record.Products)
+		if err != nil {
+			return fmt.Errorf("ошибка маршалинга продуктов для истории: %v", err)
+		}
+		products = data
+	}
+	var plan any // NULL, если плана нет
+	if record.Routine != nil {
+		data, err := json.Marshal(record.Routine)
+		if err != nil {
+			return fmt.Errorf("ошибка маршалинга плана ухода для истории: %v", err)
+		}
+		plan = data
+	}
+
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO recommendation_history (user_id, type, profile, products, model, content, routine)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at`,
+		record.UserID, record.Type, profile, products, record.Model, record.Content, plan,
+	).Scan(&record.ID, &record.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения рекомендации в историю: %v", err)
+	}
+	return nil
+}
+
+// Get возвращает запись пользователя
+func (s *PostgresStore) Get(ctx context.Context, userID, id int64) (*Record, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+recordColumns+` FROM recommendation_history WHERE user_id = $1 AND id = $2`,
+		userID, id)
+	return scanRecord(row)
+}
+
+// Previous возвращает предыдущую запись того же типа
+func (s *PostgresStore) Previous(ctx context.Context, record *Record) (*Record, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT `+recordColumns+` FROM recommendation_history
+		WHERE user_id = $1 AND type = $2 AND id < $3
+		ORDER BY id DESC
+		LIMIT 1`,
+		record.UserID, record.Type, record.ID)
+	return scanRecord(row)
+}
+
+// List возвращает записи пользователя от новых к старым
+func (s *PostgresStore) List(ctx context.Context, userID int64, offset, limit int) ([]Record, int, error) {
+	var total int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM recommendation_history WHERE user_id = $1`, userID,
+	).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ошибка чтения истории рекомендаций: %v", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+recordColumns+` FROM recommendation_history
+		WHERE user_id = $1
+		ORDER BY id DESC
+		OFFSET $2 LIMIT $3`,
+		userID, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ошибка чтения истории рекомендаций: %v", err)
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		record, err := scanRecord(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		records = append(records, *record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("ошибка чтения истории рекомендаций: %v", err)
+	}
+	return records, total, nil
+}
+
+// scanRecord читает запись из строки результата с колонками recordColumns
+func scanRecord(row interface{ Scan(dest ...any) error }) (*Record, error) {
+	var (
+		record   Record
+		profile  []byte
+		products []byte
+		plan     []byte
+	)
+	err := row.Scan(&record.ID, &record.UserID, &record.Type, &profile, &products,
+		&record.Model, &record.Content, &plan, &record.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения рекомендации из истории: %v", err)
+	}
+
+	if err := json.Unmarshal(profile, &record.Profile); err != nil {
+		return nil, fmt.Errorf("ошибка разбора анкеты из истории: %v", err)
+	}
+	if products != nil {
+		if err := json.Unmarshal(products, &record.Products); err != nil {
+			return nil, fmt.Errorf("ошибка разбора продуктов из истории: %v", err)
+		}
+	}
+	if plan != nil {
+		if err := json.Unmarshal(plan, &record.Routine); err != nil {
+			return nil, fmt.Errorf("ошибка разбора плана ухода из истории: %v", err)
+		}
+	}
+	return &record, nil
+}
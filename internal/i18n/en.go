@@ -0,0 +1,513 @@
+package i18n
+
+// englishMessages английский каталог; недостающие ключи берутся из русского
+var englishMessages = map[string]string{
+	// Общие кнопки и главное меню
+	"button.back":          "⬅️ Back",
+	"menu.anketa":          "📋 Questionnaire",
+	"menu.recommendations": "🤖 Recommendations",
+	"menu.products":        "🧴 My products",
+	"menu.routine":         "🗓 My routine",
+
+	// Команды
+	"start.caption": `✨ I'm your smart beauty bot, built to finally bring order to your cosmetics bag. This bot is part of the Cos AI project, created to help you put together a personalized skincare routine.
+Want to try? Let's start with a short questionnaire 💬👇`,
+	"help.text": `Available commands:
+/start - Start using the bot
+/help - Show this help
+/form - Fill in the skincare questionnaire
+/myproducts - Show my products
+/language - Choose language
+
+🔍 To search for products use inline mode:
+@cosmetics_lab_ai_bot add [product name]`,
+	"command.unknown": "Unknown command. Use /help for help.",
+
+	// Выбор языка
+	"language.name":    "🇬🇧 English",
+	"language.choose":  "🌐 Choose the interface language:",
+	"language.changed": "✅ Interface language: English",
+
+	// Заполнение анкеты
+	"form.resume":              "📝 You have an unfinished questionnaire (step %d). Continue where you left off?",
+	"form.resume.continue":     "▶️ Continue",
+	"form.resume.restart":      "🔄 Start over",
+	"form.step":                "<b>Step %d of %d</b>\n\n",
+	"form.multi_hint":          "\n\nYou can choose several options, then press «Done»",
+	"form.done":                "Done",
+	"form.skip":                "Skip",
+	"form.too_many.one":        "You can choose at most %d option",
+	"form.too_many.other":      "You can choose at most %d options",
+	"form.too_few.single":      "Choose at least one option",
+	"form.too_few.one":         "Choose at least %d option",
+	"form.too_few.other":       "Choose at least %d options",
+	"form.text.too_long.one":   "The answer is too long. Please keep it within %d character.",
+	"form.text.too_long.other": "The answer is too long. Please keep it within %d characters.",
+	"form.text.too_short":      "The answer is too short. Please tell us more.",
+	"form.text.only_link":      "Please describe your answer in words — a link alone is not enough.",
+	"form.text.only_emoji":     "Please write your answer in words — emoji alone are not enough.",
+	"form.results.header":      "✅ Questionnaire complete! Here are your answers:\n\n",
+	"form.results.line":        "%s %s: %s\n",
+	"form.results.empty":       "not specified",
+	"form.results.footer":      "\nNow I can pick suitable products for you!",
+
+	// Просмотр анкеты
+	"anketa.header":        "📋 <b>Your questionnaire:</b>\n\n",
+	"anketa.line":          "%s <b>%s:</b> %s\n",
+	"anketa.empty":         "📋 You haven't filled in the questionnaire yet.\n\nFill it in to get personalized skincare recommendations!",
+	"anketa.start":         "📝 Fill in the questionnaire",
+	"anketa.edit":          "✏️ %s",
+	"anketa.delete":        "🗑️ Delete questionnaire",
+	"anketa.retake":        "🔄 Start over",
+	"anketa.retake_full":   "🔄 Retake the questionnaire",
+	"anketa.load_failed":   "❌ Could not load your questionnaire. Please try again later.",
+	"anketa.save_failed":   "❌ Could not save the changes. Please try again.",
+	"anketa.deleted":       "✅ Your questionnaire has been deleted!",
+	"anketa.delete_failed": "Failed to delete the questionnaire: %v",
+
+	// Incidecoder
+	"incidecoder.parsing": "Parsing the product from Incidecoder...",
+	"incidecoder.todo":    "Parsing products from Incidecoder will be available in a future version.",
+
+	// Карточка продукта
+	"product.load_failed":            "Failed to load the product: %v",
+	"product.description":            "📝 <b>Description:</b>\n%s\n\n",
+	"product.ingredients":            "🧪 <b>Ingredients:</b>\n",
+	"product.more_ingredients.one":   "... and %d more ingredient",
+	"product.more_ingredients.other": "... and %d more ingredients",
+	"product.add":                    "➕ Add to collection",
+	"product.add_failed":             "Failed to add the product: %v",
+	"product.added":                  "✅ The product has been added to your collection!",
+	"product.remove_failed":          "❌ Failed to remove the product: %v",
+	"product.removed":                "✅ The product has been removed from your collection!",
+
+	// Коллекция продуктов
+	"products.loading":     "🔄 Loading your products...",
+	"products.load_failed": "❌ Failed to load your products: %v",
+	"products.empty": `🧴 <b>Your collection is empty</b>
+
+You haven't added any products to your collection yet.
+
+<b>To search for products type:</b>
+@cosmetics_lab_ai_bot add [product you want to find]
+
+<b>Example:</b>
+@cosmetics_lab_ai_bot add Repair Sunscreen SPF 50`,
+	"products.header.one":          "🧴 <b>Your collection (%d product)</b>\n\n",
+	"products.header.other":        "🧴 <b>Your collection (%d products)</b>\n\n",
+	"products.add_hint":            "💡 <b>To add new products type:</b>\n@cosmetics_lab_ai_bot add [product name]\n\n",
+	"products.more.one":            "... and %d more product\n",
+	"products.more.other":          "... and %d more products\n",
+	"products.added_at":            "   📅 Added: %s\n",
+	"products.delete":              "🗑️ Delete products",
+	"products.delete.none":         "🧴 You have no products to delete.",
+	"products.delete.header.one":   "🗑️ <b>Choose products to delete (%d product):</b>\n\n",
+	"products.delete.header.other": "🗑️ <b>Choose products to delete (%d products):</b>\n\n",
+	"products.back":                "⬅️ Back to products",
+
+	// Проверка состава
+	"screening.check":           "🔍 Check my cosmetics bag",
+	"screening.loading":         "🔍 Checking the ingredients of your products...",
+	"screening.no_restrictions": "🔍 Your questionnaire has no allergies or dietary restrictions that can be checked against ingredients.\n\nAdd them to the questionnaire and I will check every product.",
+	"screening.failed":          "❌ Could not check your collection. Please try again later.",
+	"screening.header":          "🔍 <b>Cosmetics bag check</b>\n\n",
+	"screening.nothing_found":   "✅ None of the ingredient lists contain anything you avoid.\n\n",
+	"screening.flagged.one":     "⚠️ <b>Worth a look: %d product</b>\n\n",
+	"screening.flagged.other":   "⚠️ <b>Worth a look: %d products</b>\n\n",
+	"screening.product":         "🔸 <b>%s %s</b>\n",
+	"screening.flag":            "   • %s (%s): <i>%s</i>\n",
+	"screening.clean.one":       "✅ No matches: %d product\n",
+	"screening.clean.other":     "✅ No matches: %d products\n",
+	"screening.unknown.one":     "❔ Ingredients unknown, %d product not checked:\n",
+	"screening.unknown.other":   "❔ Ingredients unknown, %d products not checked:\n",
+	"screening.unknown_product": "   • %s %s\n",
+	"screening.added_warning":   "⚠️ <b>%s %s</b> contains ingredients you avoid:\n",
+	"screening.disclaimer":      "\n<i>The check matches ingredients against a list of INCI synonyms and may miss something. If your allergy is severe, check the packaging.</i>",
+
+	"screening.concern.fragrance":    "Fragrance",
+	"screening.concern.lanolin":      "Lanolin",
+	"screening.concern.preservative": "Preservatives",
+	"screening.concern.alcohol":      "Alcohol",
+	"screening.concern.animal":       "Animal-derived",
+	"screening.concern.slaughter":    "Obtained by slaughter",
+	"screening.concern.gluten":       "Gluten",
+	"screening.concern.custom":       "Listed by you",
+
+	// Беременность и лактация
+	"safety.header":           "\n🤰 <b>Pregnancy and breastfeeding</b>\n",
+	"safety.flag":             "%s <b>%s</b> — %s: <i>%s</i>\n%s\n",
+	"safety.high_dose":        "According to the description, the concentration is above the safe level.\n",
+	"safety.disclaimer":       "<i>This is reference information, not medical advice.</i>\n",
+	"safety.severity.avoid":   "do not use",
+	"safety.severity.limit":   "low concentrations only",
+	"safety.severity.caution": "ask your doctor",
+
+	"safety.rule.retinoids.name":        "Retinoids",
+	"safety.rule.retinoids.text":        "Oral retinoids cause birth defects and topical ones are not proven safe: stop them during pregnancy and use them while breastfeeding only with your doctor's approval.",
+	"safety.rule.hydroquinone.name":     "Hydroquinone",
+	"safety.rule.hydroquinone.text":     "A noticeable share of hydroquinone is absorbed through the skin, and there is no safety data for the baby.",
+	"safety.rule.salicylic.name":        "Salicylic acid",
+	"safety.rule.salicylic.text":        "Considered acceptable in rinse-off products and at up to 2%; peels and high concentrations are not recommended.",
+	"safety.rule.benzoyl_peroxide.name": "Benzoyl peroxide",
+	"safety.rule.benzoyl_peroxide.text": "Poorly absorbed: spot use at up to 5% is usually acceptable, large areas of skin are not.",
+	"safety.rule.arbutin.name":          "Arbutin",
+	"safety.rule.arbutin.text":          "Can turn into hydroquinone in the skin, and there is little safety data for pregnancy.",
+	"safety.rule.oxybenzone.name":       "Oxybenzone",
+	"safety.rule.oxybenzone.text":       "A chemical UV filter that reaches the bloodstream; mineral filters such as zinc oxide and titanium dioxide are safer.",
+	"safety.rule.kojic.name":            "Kojic acid",
+	"safety.rule.kojic.text":            "There are no safety studies in pregnancy; postpone brightening or discuss it with your doctor.",
+
+	// План ухода
+	"routine.morning":  "☀️ Morning",
+	"routine.evening":  "🌙 Evening",
+	"routine.warnings": "⚠️ Warnings",
+	"routine.missing":  "🧩 What is missing",
+	"routine.tips":     "💡 Tips",
+
+	"routine.category.cleanser":    "cleanser",
+	"routine.category.toner":       "toner",
+	"routine.category.exfoliant":   "exfoliant",
+	"routine.category.serum":       "serum",
+	"routine.category.treatment":   "treatment",
+	"routine.category.eye_care":    "eye care",
+	"routine.category.moisturizer": "moisturizer",
+	"routine.category.oil":         "oil",
+	"routine.category.spf":         "SPF",
+	"routine.category.mask":        "mask",
+	"routine.category.other":       "other",
+
+	"routine.frequency.daily":           "daily",
+	"routine.frequency.every_other_day": "every other day",
+	"routine.frequency.twice_weekly":    "twice a week",
+	"routine.frequency.weekly":          "once a week",
+	"routine.frequency.as_needed":       "as needed",
+
+	// Мой уход
+	"routine.builder.header":            "🗓 <b>My routine</b>\n\n",
+	"routine.builder.named":             "🗓 <b>%s</b>\n\n",
+	"routine.builder.slot":              "<b>%s</b>\n",
+	"routine.builder.step":              "%d. <b>%s %s</b> — %s, %s\n",
+	"routine.builder.slot_empty":        "<i>No steps yet</i>\n",
+	"routine.builder.empty_hint":        "Build your morning and evening routine from the products in your collection, in the order you apply them. Product recommendations will take into account how you use them.\n",
+	"routine.builder.issues":            "⚠️ <b>Order of application</b>\nProducts go from cleansing to protection: cleanser → toner → serum → moisturizer → SPF.\n",
+	"routine.builder.issue.order":       "• %s, step %d: %s (%s) comes after %s (%s)\n",
+	"routine.builder.issue.evening_spf": "• %s, step %d: %s — SPF is not needed in the evening\n",
+	"routine.builder.add":               "➕ %s",
+	"routine.builder.edit":              "✏️ %s",
+	"routine.builder.sort":              "🔃 Put in order",
+	"routine.builder.rename":            "🏷 Name",
+	"routine.builder.clear":             "🗑 Clear routine",
+	"routine.builder.back":              "⬅️ Back to routine",
+	"routine.builder.done":              "✅ Done",
+	"routine.builder.cancel":            "✖️ Cancel",
+	"routine.builder.up":                "⬆️ %d",
+	"routine.builder.down":              "⬇️ %d",
+	"routine.builder.remove":            "❌ %d",
+	"routine.builder.load_failed":       "❌ Could not load your routine. Please try again later.",
+	"routine.builder.save_failed":       "❌ Could not save your routine. Please try again later.",
+	"routine.builder.collection_empty":  "🧴 Your collection has no products yet.\n\n<b>To search for products type:</b>\n@cosmetics_lab_ai_bot add [product name]",
+	"routine.builder.all_added":         "<b>%s</b>\n\nAll products from your collection are already in this routine.",
+	"routine.builder.choose_product":    "<b>%s</b>\n\nChoose a product from your collection:",
+	"routine.builder.choose_category":   "<b>%s</b>\n\n<b>%s %s</b>\nAt which step of the routine do you use it?",
+	"routine.builder.choose_frequency":  "<b>%s</b>\n\n<b>%s %s</b> — %s\nHow often?",
+	"routine.builder.not_in_collection": "❌ This product is no longer in your collection.",
+	"routine.builder.edit_header":       "<b>%s</b>\nReorder the steps with the arrows or remove the ones you don't need:\n\n",
+	"routine.builder.name_prompt":       "🏷 Send the name of your routine in one message, up to %d characters.",
+	"routine.builder.name_invalid":      "❌ The name must not be empty or longer than %d characters. Please send another one.",
+
+	// Совместимость активов
+	"conflicts.check":            "⚗️ Product compatibility",
+	"conflicts.loading":          "⚗️ Checking which products should not be applied together...",
+	"conflicts.failed":           "❌ Could not check compatibility. Please try again later.",
+	"conflicts.header":           "⚗️ <b>Product compatibility</b>\n\n",
+	"conflicts.nothing_found":    "✅ No conflicting actives found in your collection.\n\n",
+	"conflicts.found.one":        "⚠️ <b>%d conflict found</b>\n\n",
+	"conflicts.found.other":      "⚠️ <b>%d conflicts found</b>\n\n",
+	"conflicts.product":          "🔸 <b>%s %s</b> — %s: <i>%s</i>\n",
+	"conflicts.reason":           "%s\n",
+	"conflicts.advice.split":     "👉 Split them: %s in the %s, %s in the %s.\n",
+	"conflicts.advice.alternate": "👉 Do not apply them on the same day, alternate them.\n",
+	"conflicts.slot.am":          "morning",
+	"conflicts.slot.pm":          "evening",
+	"conflicts.partial":          "\n⚠️ Some ingredient data did not load in time, so the check may have missed conflicts. Try again a bit later.\n",
+	"conflicts.disclaimer":       "\n<i>The check looks for known actives in the ingredient lists and does not account for concentrations. If your skin tolerates a combination well, you can keep it.</i>",
+
+	"conflicts.class.retinoid":         "retinoids",
+	"conflicts.class.acid":             "acids (AHA/BHA/PHA)",
+	"conflicts.class.vitamin_c":        "vitamin C",
+	"conflicts.class.benzoyl_peroxide": "benzoyl peroxide",
+	"conflicts.class.copper_peptide":   "copper peptides",
+	"conflicts.class.hydroquinone":     "hydroquinone",
+
+	"conflicts.rule.retinoid_acid":      "Retinoids and acids together cause strong irritation and thin the outer layer of the skin.",
+	"conflicts.rule.retinoid_retinoid":  "Two retinoids on the same day double the irritation without extra benefit.",
+	"conflicts.rule.retinoid_bpo":       "Benzoyl peroxide oxidises retinoids and makes them less effective, and together they dry the skin out.",
+	"conflicts.rule.retinoid_vitamin_c": "Vitamin C works best in the morning as an antioxidant, while retinoids break down in light and belong in the evening.",
+	"conflicts.rule.vitamin_c_acid":     "Acids change the pH and add to the irritation from vitamin C.",
+	"conflicts.rule.vitamin_c_bpo":      "Benzoyl peroxide oxidises vitamin C so it stops working.",
+	"conflicts.rule.vitamin_c_copper":   "Copper ions break down vitamin C, and vitamin C breaks down copper peptides.",
+	"conflicts.rule.acid_copper":        "Acids break down copper peptides and make them less effective.",
+	"conflicts.rule.acid_bpo":           "Acids and benzoyl peroxide together dry out and irritate the skin.",
+	"conflicts.rule.bpo_hydroquinone":   "Benzoyl peroxide with hydroquinone temporarily stains the skin.",
+
+	// Рекомендации
+	"recs.caption": `🤖 <b>Recommendations</b>
+
+We can give you skincare advice based on your questionnaire or your current products, or point out what is missing from your routine.
+
+<i>These recommendations are for information only. They do not replace a consultation with a dermatologist and do not make diagnoses.</i>`,
+	"recs.anketa.title":       "Recommendations based on the questionnaire",
+	"recs.anketa.progress":    "🤖 Generating recommendations based on your questionnaire...\n\n⏳ This may take up to 2 minutes. Please wait...",
+	"recs.products.title":     "Recommendations for my products",
+	"recs.products.progress":  "🤖 Generating recommendations for your products...\n\n⏳ This may take up to 2 minutes. Please wait...",
+	"recs.general.title":      "General recommendations",
+	"recs.general.progress":   "🤖 Generating general recommendations...\n\n⏳ This may take up to 2 minutes. Please wait...",
+	"recs.retry":              "🔄 Try again",
+	"recs.regenerate":         "🔄 Generate again",
+	"recs.cancel":             "✖️ Cancel",
+	"recs.cancel.done":        "Generation cancelled",
+	"recs.cancel.nothing":     "Generation has already finished",
+	"recs.cancelled":          "⛔ Recommendation generation was cancelled.",
+	"recs.in_progress":        "⏳ Already generating… The answer will appear in the message above, no need to tap again.",
+	"recs.back":               "⬅️ Back to recommendations",
+	"recs.error.timeout":      "⏰ The request timed out. The model is slow right now. Please try again in a few minutes.",
+	"recs.error.rate_limited": "🚦 Too many requests to the model. Please try again in a minute.",
+	"recs.error.overloaded":   "🔥 The model is overloaded right now. Please try again a bit later.",
+	"recs.error.unauthorized": "🔑 Authentication error. Check the API settings.",
+	"recs.error.bad_request":  "❌ The model could not process the request. Try changing your questionnaire or product list.",
+	"recs.error.malformed":    "❌ The model returned the plan in an invalid format. Please try again.",
+	"recs.error.unknown":      "❌ Could not get recommendations. Please try again later.",
+
+	// История рекомендаций
+	"recs.history":                  "📜 History",
+	"history.empty":                 "📜 Your history is empty. Your recommendations will be saved here.",
+	"history.load_failed":           "❌ Could not load your recommendation history. Please try again later.",
+	"history.not_found":             "❌ This recommendation was not found in your history.",
+	"history.header":                "📜 <b>Recommendation history</b> (page %d of %d)\n\nChoose a recommendation to open it or compare it with the previous one.",
+	"history.item":                  "%s %s · %s",
+	"history.newer":                 "◀️ Newer",
+	"history.older":                 "Older ▶️",
+	"history.back":                  "⬅️ Back to history",
+	"history.open":                  "📄 Open recommendation",
+	"history.view.header":           "%s <b>%s</b>\n🕒 %s · 🤖 %s\n\n",
+	"history.diff":                  "🔀 What changed",
+	"history.diff.none":             "This is the first recommendation of this kind — there is nothing to compare it with.",
+	"history.diff.header":           "🔀 <b>%s: what changed</b>\n🕒 %s → %s\n\n",
+	"history.diff.profile":          "<b>Questionnaire</b>\n",
+	"history.diff.profile_same":     "The questionnaire did not change.\n",
+	"history.diff.answer":           "%s %s: %s → %s\n",
+	"history.diff.products":         "\n<b>Collection</b>\n",
+	"history.diff.products_same":    "\nThe collection did not change.\n",
+	"history.diff.product_added":    "➕ %s %s\n",
+	"history.diff.product_removed":  "➖ %s %s\n",
+	"history.diff.model":            "\n🤖 Model: %s → %s\n",
+	"history.diff.text":             "\n<b>Recommendation text</b>\n",
+	"history.diff.text_same":        "\nThe recommendation text did not change.\n",
+	"history.diff.line_added":       "➕ %s\n",
+	"history.diff.line_removed":     "➖ %s\n",
+	"history.diff.more_lines.one":   "… and %d more changed line\n",
+	"history.diff.more_lines.other": "… and %d more changed lines\n",
+
+	// Inline-поиск
+	"inline.too_short.title":       "⚠️ Query is too short",
+	"inline.too_short.text":        "Enter at least 3 characters to search for products.",
+	"inline.too_short.description": "At least 3 characters to search",
+	"inline.error.title":           "❌ Search error",
+	"inline.error.text":            "An error occurred while searching for products. Please try again later.",
+	"inline.error.description":     "Server connection error",
+	"inline.not_found.title":       "❌ Product not found",
+	"inline.not_found.text":        "Nothing was found for your query. Try a different search.",
+	"inline.not_found.description": "Nothing found for '%s'",
+
+	// Вопросы анкеты
+	"question.skin_type.title":   "Skin type",
+	"question.skin_type.text":    "What is your skin type?\n\nSkin type affects the choice of textures and active ingredients — it determines how well a product will work",
+	"question.age.title":         "Age",
+	"question.age.text":          "How old are you?\n\nSkin needs different things at 20, 30 and 50. Your age helps us pick what suits you",
+	"question.gender.title":      "Gender",
+	"question.gender.text":       "What is your gender?\n\nMale and female skin differ in structure and hormones — this helps us choose your care more precisely",
+	"question.pregnancy.title":   "Pregnancy/breastfeeding",
+	"question.pregnancy.text":    "Are you currently pregnant or breastfeeding?\n\nSome ingredients are not recommended during this period. We will pick safe alternatives.",
+	"question.concerns.title":    "Concerns",
+	"question.concerns.text":     "What bothers you the most?\n\nWhat do you expect from your routine: fix a problem, prevent it, refresh your look? Answer in your own words, for example:\n\nI want to calm my sensitive skin, and I'm also bothered by acne and blackheads",
+	"question.goal.title":        "Goal",
+	"question.goal.text":         "What result do you want to get?\n\nYour goal is our strategy. If none of the options fits, you can write your own answer",
+	"question.climate.title":     "Climate",
+	"question.climate.text":      "What is your climate like?\n\nClimate affects how much hydration and protection your skin needs",
+	"question.fitzpatrick.title": "Fitzpatrick skin type",
+	"question.fitzpatrick.text":  "How would you describe your skin's reaction to the sun?\n\nThis helps us choose the right sun protection",
+	"question.lifestyle.title":   "Lifestyle",
+	"question.lifestyle.text":    "What is your pace of life?\n\nLifestyle affects the choice of products and your routine",
+	"question.diet.title":        "Diet",
+	"question.diet.text":         "Do you have any dietary habits or beliefs we should take into account?\n\nThis helps us choose suitable ingredients",
+	"question.allergies.title":   "Allergies",
+	"question.allergies.text":    "Do you have any allergies or intolerances?\n\nWe need to know this to exclude problematic ingredients",
+
+	// Названия вариантов ответа
+	"answer.skin_dry":                "Dry",
+	"answer.skin_oily":               "Oily",
+	"answer.skin_normal":             "Normal",
+	"answer.skin_sensitive":          "Sensitive",
+	"answer.skin_combined":           "Combination",
+	"answer.skin_unknown":            "Don't know",
+	"answer.age_18_minus":            "Under 18",
+	"answer.age_18_24":               "18-24 years",
+	"answer.age_25_34":               "25-34 years",
+	"answer.age_35_44":               "35-44 years",
+	"answer.age_45_plus":             "45+ years",
+	"answer.age_ignore":              "Don't take into account",
+	"answer.gender_male":             "Male",
+	"answer.gender_female":           "Female",
+	"answer.gender_other":            "Other",
+	"answer.gender_ignore":           "Don't take into account",
+	"answer.pregnancy":               "Pregnancy",
+	"answer.lactation":               "Breastfeeding",
+	"answer.pregnancy_and_lactation": "Pregnancy and breastfeeding",
+	"answer.none_of_above":           "None of the above",
+	"answer.pregnancy_ignore":        "Don't take into account",
+	"answer.goal_hydration":          "Hydration and nourishment",
+	"answer.goal_tone":               "Even skin tone",
+	"answer.goal_antiage":            "Anti-aging care",
+	"answer.goal_texture":            "Better texture",
+	"answer.goal_refresh":            "Refresh and maintain",
+	"answer.goal_minimalism":         "Minimalism, basic care only",
+	"answer.goal_other":              "Other",
+	"answer.climate_dry":             "Dry",
+	"answer.climate_humid":           "Humid",
+	"answer.climate_hot":             "Hot",
+	"answer.climate_cold":            "Cold",
+	"answer.climate_temperate":       "Changeable / temperate",
+	"answer.climate_polluted":        "Polluted (city, smog, dust)",
+	"answer.climate_multiple":        "I live in several climates (travel/moving)",
+	"answer.climate_unknown":         "Don't know",
+	"answer.fitzpatrick_1":           "I – very fair, always burns",
+	"answer.fitzpatrick_2":           "II – fair, burns but may tan slightly",
+	"answer.fitzpatrick_3":           "III – light olive, tans easily",
+	"answer.fitzpatrick_4":           "IV – olive, rarely burns",
+	"answer.fitzpatrick_5":           "V – dark, almost never burns",
+	"answer.fitzpatrick_6":           "VI – very dark, never burns",
+	"answer.fitzpatrick_unknown":     "Don't know / Prefer not to say",
+	"answer.lifestyle_stress":        "Frequent stress",
+	"answer.lifestyle_sleep":         "Lack of sleep / irregular schedule",
+	"answer.lifestyle_screen":        "A lot of screen time",
+	"answer.lifestyle_sweat":         "I sweat a lot (sports, heat, etc.)",
+	"answer.lifestyle_computer":      "I work at a computer",
+	"answer.lifestyle_active":        "I move a lot during the day",
+	"answer.lifestyle_outdoor":       "I'm outdoors regularly",
+	"answer.lifestyle_passive":       "Sedentary / home lifestyle",
+	"answer.lifestyle_other":         "Other",
+	"answer.diet_vegan":              "Vegan",
+	"answer.diet_vegetarian":         "Vegetarian",
+	"answer.diet_halal":              "Halal",
+	"answer.diet_keto":               "Keto / Paleo / Low-carb",
+	"answer.diet_gluten_free":        "Gluten-free",
+	"answer.diet_no_alcohol":         "I avoid alcohol in ingredients",
+	"answer.diet_no_animal":          "I avoid animal-derived ingredients",
+	"answer.diet_none":               "No special restrictions",
+	"answer.diet_other":              "Other",
+	"answer.allergies_none":          "No allergies",
+	"answer.allergies_nickel":        "Nickel allergy",
+	"answer.allergies_lanolin":       "Lanolin allergy",
+	"answer.allergies_fragrance":     "Fragrance allergy",
+	"answer.allergies_preservatives": "Preservative allergy",
+	"answer.allergies_other":         "Other",
+
+	// Текст кнопки, если он отличается от названия варианта
+	"option.skin_unknown":            "I don't know my skin type",
+	"option.age_18_minus":            "<18",
+	"option.age_18_24":               "18–24",
+	"option.age_25_34":               "25–34",
+	"option.age_35_44":               "35–44",
+	"option.age_45_plus":             "45+",
+	"option.pregnancy_and_lactation": "Both",
+	"option.goal_other":              "Other (I'll write it)",
+	"option.allergies_other":         "Other (I'll write it)",
+
+	// Просьбы уточнить ответ «Другое»
+	"followup.goal_other":      "Describe in your own words what result you want to get",
+	"followup.lifestyle_other": "Tell us what else about your lifestyle we should take into account",
+	"followup.diet_other":      "Write which dietary habits or beliefs we should take into account",
+	"followup.allergies_other": "Write what you are allergic or intolerant to",
+
+	// Промпты для языковой модели
+	"prompt.language":        "Answer in English.",
+	"prompt.answer":          "%s: %s",
+	"prompt.answer_other":    "%s: %s (user's clarification: %s)",
+	"prompt.no_products":     "The user hasn't added any products yet.",
+	"prompt.product":         "- %s (%s), id %d",
+	"prompt.product_details": "  Description: %s",
+
+	"prompt.safety.header":           "MANDATORY SAFETY CONSTRAINTS. The user stated in the questionnaire: %s. These rules override any other wishes and must not be broken:\n",
+	"prompt.safety.rule":             "- %s: %s.\n",
+	"prompt.safety.severity.avoid":   "do not recommend in any form or concentration",
+	"prompt.safety.severity.limit":   "only low concentrations in rinse-off or spot products; do not recommend peels or high concentrations",
+	"prompt.safety.severity.caution": "recommend only with an explicit note to discuss it with a doctor",
+	"prompt.safety.products":         "\nThe user's collection contains products with these actives. For each one, say plainly that it should be paused or limited and suggest a safe replacement:\n",
+	"prompt.safety.product":          "- %s %s: %s (%s) — %s.\n",
+
+	"prompt.conflicts": "\n\nThe user's collection contains products that must not be applied at the same time. The analysis is in JSON: advice = \"split\" means one product in the morning and the other in the evening, with the time in slot (am — morning, pm — evening); advice = \"alternate\" means on different days. Do not put such products in the same routine step and explain to the user how to separate them:\n%s",
+
+	"prompt.user_routine":                   "The user built their own routine. Keep its order and frequency unless there is a good reason to change them, and explain every change. Steps are listed in order of application:",
+	"prompt.user_routine.named":             "The user built their own routine \"%s\". Keep its order and frequency unless there is a good reason to change them, and explain every change. Steps are listed in order of application:",
+	"prompt.user_routine.slot.am":           "Morning:",
+	"prompt.user_routine.slot.pm":           "Evening:",
+	"prompt.user_routine.slot_empty":        "- no steps",
+	"prompt.user_routine.step":              "%d. %s (%s), id %d — step: %s, frequency: %s",
+	"prompt.user_routine.unused":            "Products from the collection that the user did not include in the routine:",
+	"prompt.user_routine.issues":            "Order-of-application problems (cleanser → toner → serum → moisturizer → SPF) that need fixing:",
+	"prompt.user_routine.issue.order":       "- %s %s (%s) comes after %s (%s)",
+	"prompt.user_routine.issue.evening_spf": "- Evening: %s — sunscreen is not needed in the evening",
+
+	"prompt.routine": `Answer with exactly one JSON object matching the skincare_routine schema, with no Markdown or text around it. Write all texts inside the JSON in the user's language.
+- summary: 2–4 sentences with the main conclusion about the skin and the routine.
+- morning, evening: steps in order of application. category is the routine stage; product is the name of a product from the collection or a description of a product worth buying; product_id is the id of a product from the collection, or null if the product is not in the collection; purpose is why the step is needed; frequency is how often (daily, every_other_day, twice_weekly, weekly, as_needed).
+- warnings: warnings about allergens, incompatible actives and restrictions.
+- missing: routine stages that are missing, with the reason.
+- tips: general advice on skincare, lifestyle, diet and seasons.
+If a section is not needed, leave an empty array.`,
+	"prompt.routine.retry": "The answer failed validation: %s. Return the corrected plan as exactly one JSON object matching the skincare_routine schema.",
+
+	"prompt.anketa": `You are a professional cosmetologist and dermatology consultant.
+
+Based on the questionnaire, write recommendations:
+- Describe the optimal skincare plan (morning/evening) with a step-by-step order of application.
+- Take into account skin type, age, gender, pregnancy, ingredient allergies, climate and skincare goals.
+- If a potential allergen is found in the ingredients, warn about it.
+- The recommendations should be clear and careful, as if you were a dermatology consultant.
+- Specify which products can be used in the morning, which in the evening, which every other day, and which are incompatible with each other.
+- Finish with 2–3 recommendations for products that are clearly missing.
+
+**User's questionnaire:**
+%s`,
+	"prompt.products": `You are a professional cosmetologist and dermatology consultant.
+
+Based on the questionnaire and the list of cosmetic products, write recommendations:
+- Describe the optimal skincare plan (morning/evening) with a step-by-step order of application.
+- Use only the products the user already has, **but point out if a step is missing**.
+- Take into account skin type, age, gender, pregnancy, ingredient allergies, climate and skincare goals.
+- If a potential allergen is found in the ingredients, warn about it.
+- The recommendations should be clear and careful, as if you were a dermatology consultant.
+- Specify which products can be used in the morning, which in the evening, which every other day, and which are incompatible with each other.
+- Finish with 2–3 recommendations for products that are clearly missing.
+
+**User's questionnaire:**
+%s
+
+**User's products:**
+%s`,
+	"prompt.general": `You are a professional cosmetologist and dermatology consultant.
+
+Based on the questionnaire and the list of cosmetic products, write general recommendations:
+- Analyze the current routine and give general advice on improving it.
+- Point out which skincare steps are missing or underdeveloped.
+- Give lifestyle recommendations to improve the skin's condition.
+- Suggest general skincare principles suitable for this skin type and age.
+- Describe seasonal skincare considerations.
+- Give diet and lifestyle advice for healthy skin.
+
+**User's questionnaire:**
+%s
+
+**User's products:**
+%s`,
+}
@@ -0,0 +1,133 @@
+// Package i18n переводит тексты бота: каталоги сообщений по языкам,
+// правила множественного числа и выбор языка пользователя.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Lang код языка интерфейса
+type Lang string
+
+// Поддерживаемые языки
+const (
+	Russian Lang = "ru"
+	English Lang = "en"
+)
+
+// Default язык по умолчанию; его каталог полный, остальные при нехватке
+// сообщения используют его
+const Default = Russian
+
+// Languages поддерживаемые языки в порядке показа в /language
+var Languages = []Lang{Russian, English}
+
+// bundle каталог сообщений одного языка
+type bundle struct {
+	plural   PluralRule
+	messages map[string]string
+}
+
+// bundles каталоги сообщений по языкам
+var bundles = map[Lang]*bundle{
+	Russian: {plural: russianPlural, messages: russianMessages},
+	English: {plural: englishPlural, messages: englishMessages},
+}
+
+// Parse разбирает код поддерживаемого языка
+func Parse(code string) (Lang, bool) {
+	lang := Lang(strings.ToLower(strings.TrimSpace(code)))
+	_, ok := bundles[lang]
+	return lang, ok
+}
+
+// Match подбирает язык интерфейса по IETF-тегу из Telegram (LanguageCode).
+// Русский используется для русскоязычных и соседних языков, а также
+// если тег не передан; для остальных — английский.
+func Match(languageCode string) Lang {
+	tag := strings.ToLower(languageCode)
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	switch tag {
+	case "", "ru", "uk", "be", "kk", "ky", "uz":
+		return Russian
+	}
+	if lang, ok := Parse(tag); ok {
+		return lang
+	}
+	return English
+}
+
+// Localizer переводит сообщения на один язык
+type Localizer struct {
+	lang     Lang
+	bundle   *bundle
+	fallback *bundle
+}
+
+// For возвращает переводчик для языка; неподдерживаемый язык заменяется Default
+func For(lang Lang) *Localizer {
+	b, ok := bundles[lang]
+	if !ok {
+		lang, b = Default, bundles[Default]
+	}
+	return &Localizer{lang: lang, bundle: b, fallback: bundles[Default]}
+}
+
+// Lang возвращает язык переводчика
+func (l *Localizer) Lang() Lang {
+	return l.lang
+}
+
+// Lookup ищет сообщение в каталоге языка, затем в каталоге по умолчанию
+func (l *Localizer) Lookup(key string) (string, bool) {
+	if message, ok := l.bundle.messages[key]; ok {
+		return message, true
+	}
+	message, ok := l.fallback.messages[key]
+	return message, ok
+}
+
+// T возвращает сообщение, подставляя args через fmt.Sprintf.
+// Для неизвестного ключа возвращается сам ключ, чтобы пропуск был заметен.
+func (l *Localizer) T(key string, args ...any) string {
+	message, ok := l.Lookup(key)
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// N возвращает форму сообщения для числа n: ищется ключ вида key.one,
+// key.few, key.many, а при его отсутствии key.other. Число n подставляется
+// первым аргументом, за ним args.
+func (l *Localizer) N(key string, n int, args ...any) string {
+	args = append([]any{n}, args...)
+	form := l.bundle.plural(n)
+	if message, ok := l.bundle.messages[key+"."+string(form)]; ok {
+		return fmt.Sprintf(message, args...)
+	}
+	return l.T(key+"."+string(Other), args...)
+}
+
+// localizerKey ключ переводчика в контексте
+type localizerKey struct{}
+
+// WithLocalizer сохраняет переводчик пользователя в контексте обработки обновления
+func WithLocalizer(ctx context.Context, l *Localizer) context.Context {
+	return context.WithValue(ctx, localizerKey{}, l)
+}
+
+// FromContext возвращает переводчик из контекста или переводчик языка по умолчанию
+func FromContext(ctx context.Context) *Localizer {
+	if l, ok := ctx.Value(localizerKey{}).(*Localizer); ok {
+		return l
+	}
+	return For(Default)
+}
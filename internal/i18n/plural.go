@@ -0,0 +1,39 @@
+package i18n
+
+// PluralForm форма множественного числа по классификации CLDR
+type PluralForm string
+
+// Формы множественного числа
+const (
+	One   PluralForm = "one"
+	Few   PluralForm = "few"
+	Many  PluralForm = "many"
+	Other PluralForm = "other"
+)
+
+// PluralRule выбирает форму множественного числа для n
+type PluralRule func(n int) PluralForm
+
+// russianPlural: 1, 21, 101 продукт; 2–4, 22–24 продукта; 0, 5–20, 25 продуктов
+func russianPlural(n int) PluralForm {
+	if n < 0 {
+		n = -n
+	}
+	mod10, mod100 := n%10, n%100
+	switch {
+	case mod10 == 1 && mod100 != 11:
+		return One
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return Few
+	default:
+		return Many
+	}
+}
+
+// englishPlural: 1 product; 0, 2, 10 products
+func englishPlural(n int) PluralForm {
+	if n == 1 || n == -1 {
+		return One
+	}
+	return Other
+}
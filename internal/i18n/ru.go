@@ -0,0 +1,458 @@
+package i18n
+
+// russianMessages каталог по умолчанию: содержит все ключи.
+// Названия вариантов ответа (answer.*) здесь не повторяются —
+// на русском они берутся из справочников пакета catalog.
+var russianMessages = map[string]string{
+	// Общие кнопки и главное меню
+	"button.back":          "⬅️ Назад",
+	"menu.anketa":          "📋 Анкета",
+	"menu.recommendations": "🤖 Рекомендации",
+	"menu.products":        "🧴 Мои продукты",
+	"menu.routine":         "🗓 Мой уход",
+
+	// Команды
+	"start.caption": `✨ Я — твой умный бьюти-бот, созданный, чтобы наконец навести порядок в косметичке. Этот бот - часть проекта Cos AI, созданного для того, чтобы помочь тебе собрать персонализированный уход за кожей.
+Хочешь попробовать? Давай начнем с небольшой анкеты 💬👇`,
+	"help.text": `Доступные команды:
+/start - Начать работу с ботом
+/help - Показать эту справку
+/form - Заполнить форму подбора ухода
+/myproducts - Показать мои продукты
+/language - Выбрать язык
+
+🔍 Для поиска продуктов используйте inline режим:
+@cosmetics_lab_ai_bot add [название продукта]`,
+	"command.unknown": "Неизвестная команда. Используйте /help для справки.",
+
+	// Выбор языка
+	"language.name":    "🇷🇺 Русский",
+	"language.choose":  "🌐 Выберите язык интерфейса:",
+	"language.changed": "✅ Язык интерфейса: русский",
+
+	// Заполнение анкеты
+	"form.resume":             "📝 У вас есть незавершенная анкета (шаг %d). Продолжить с того места, где вы остановились?",
+	"form.resume.continue":    "▶️ Продолжить",
+	"form.resume.restart":     "🔄 Начать заново",
+	"form.step":               "<b>Шаг %d из %d</b>\n\n",
+	"form.multi_hint":         "\n\nМожно выбрать несколько вариантов, затем нажмите «Готово»",
+	"form.done":               "Готово",
+	"form.skip":               "Пропустить",
+	"form.too_many.one":       "Можно выбрать не больше %d варианта",
+	"form.too_many.few":       "Можно выбрать не больше %d вариантов",
+	"form.too_many.many":      "Можно выбрать не больше %d вариантов",
+	"form.too_few.single":     "Выберите хотя бы один вариант",
+	"form.too_few.one":        "Выберите не меньше %d варианта",
+	"form.too_few.few":        "Выберите не меньше %d вариантов",
+	"form.too_few.many":       "Выберите не меньше %d вариантов",
+	"form.text.too_long.one":  "Ответ слишком длинный. Пожалуйста, уложитесь в %d символ.",
+	"form.text.too_long.few":  "Ответ слишком длинный. Пожалуйста, уложитесь в %d символа.",
+	"form.text.too_long.many": "Ответ слишком длинный. Пожалуйста, уложитесь в %d символов.",
+	"form.text.too_short":     "Ответ слишком короткий. Пожалуйста, напишите подробнее.",
+	"form.text.only_link":     "Пожалуйста, опишите ответ словами — одной ссылки недостаточно.",
+	"form.text.only_emoji":    "Пожалуйста, напишите ответ словами — одних эмодзи недостаточно.",
+	"form.results.header":     "✅ Форма заполнена! Вот ваши данные:\n\n",
+	"form.results.line":       "%s %s: %s\n",
+	"form.results.empty":      "не указано",
+	"form.results.footer":     "\nТеперь я могу подобрать для вас подходящие средства!",
+
+	// Просмотр анкеты
+	"anketa.header":        "📋 <b>Ваша анкета:</b>\n\n",
+	"anketa.line":          "%s <b>%s:</b> %s\n",
+	"anketa.empty":         "📋 У вас пока нет заполненной анкеты.\n\nЗаполните анкету, чтобы получить персонализированные рекомендации по уходу за кожей!",
+	"anketa.start":         "📝 Пройти анкету",
+	"anketa.edit":          "✏️ %s",
+	"anketa.delete":        "🗑️ Удалить анкету",
+	"anketa.retake":        "🔄 Пройти заново",
+	"anketa.retake_full":   "🔄 Пройти анкету заново",
+	"anketa.load_failed":   "❌ Не удалось загрузить анкету. Попробуйте еще раз позже.",
+	"anketa.save_failed":   "❌ Не удалось сохранить изменения. Попробуйте еще раз.",
+	"anketa.deleted":       "✅ Ваша анкета удалена!",
+	"anketa.delete_failed": "Ошибка удаления анкеты: %v",
+
+	// Incidecoder
+	"incidecoder.parsing": "Парсинг продукта с Incidecoder...",
+	"incidecoder.todo":    "Функция парсинга продуктов с Incidecoder будет реализована в следующих версиях.",
+
+	// Карточка продукта
+	"product.load_failed":           "Ошибка получения продукта: %v",
+	"product.description":           "📝 <b>Описание:</b>\n%s\n\n",
+	"product.ingredients":           "🧪 <b>Ингредиенты:</b>\n",
+	"product.more_ingredients.one":  "... и еще %d ингредиент",
+	"product.more_ingredients.few":  "... и еще %d ингредиента",
+	"product.more_ingredients.many": "... и еще %d ингредиентов",
+	"product.add":                   "➕ Добавить в коллекцию",
+	"product.add_failed":            "Ошибка добавления продукта: %v",
+	"product.added":                 "✅ Продукт успешно добавлен в вашу коллекцию!",
+	"product.remove_failed":         "❌ Ошибка удаления продукта: %v",
+	"product.removed":               "✅ Продукт успешно удален из вашей коллекции!",
+
+	// Коллекция продуктов
+	"products.loading":     "🔄 Загружаю ваши продукты...",
+	"products.load_failed": "❌ Ошибка получения ваших продуктов: %v",
+	"products.empty": `🧴 <b>Ваша коллекция пуста</b>
+
+У вас пока нет добавленных продуктов в коллекцию.
+
+<b>Для поиска продуктов введите:</b>
+@cosmetics_lab_ai_bot add [продукт который хотите найти]
+
+<b>Пример:</b>
+@cosmetics_lab_ai_bot add Repair Sunscreen SPF 50`,
+	"products.header.one":         "🧴 <b>Ваша коллекция (%d продукт)</b>\n\n",
+	"products.header.few":         "🧴 <b>Ваша коллекция (%d продукта)</b>\n\n",
+	"products.header.many":        "🧴 <b>Ваша коллекция (%d продуктов)</b>\n\n",
+	"products.add_hint":           "💡 <b>Для добавления новых продуктов введите:</b>\n@cosmetics_lab_ai_bot add [название продукта]\n\n",
+	"products.more.one":           "... и еще %d продукт\n",
+	"products.more.few":           "... и еще %d продукта\n",
+	"products.more.many":          "... и еще %d продуктов\n",
+	"products.added_at":           "   📅 Добавлено: %s\n",
+	"products.delete":             "🗑️ Удалить продукты",
+	"products.delete.none":        "🧴 У вас нет продуктов для удаления.",
+	"products.delete.header.one":  "🗑️ <b>Выберите продукты для удаления (%d продукт):</b>\n\n",
+	"products.delete.header.few":  "🗑️ <b>Выберите продукты для удаления (%d продукта):</b>\n\n",
+	"products.delete.header.many": "🗑️ <b>Выберите продукты для удаления (%d продуктов):</b>\n\n",
+	"products.back":               "⬅️ Назад к продуктам",
+
+	// Проверка состава
+	"screening.check":           "🔍 Проверить мою косметичку",
+	"screening.loading":         "🔍 Проверяю составы ваших продуктов...",
+	"screening.no_restrictions": "🔍 В анкете не указаны аллергии или особенности питания, которые можно проверить по составу.\n\nОтметьте их в анкете, и я проверю каждый продукт.",
+	"screening.failed":          "❌ Не удалось проверить коллекцию. Попробуйте еще раз позже.",
+	"screening.header":          "🔍 <b>Проверка косметички</b>\n\n",
+	"screening.nothing_found":   "✅ Ни в одном составе не найдено ингредиентов, которых вы избегаете.\n\n",
+	"screening.flagged.one":     "⚠️ <b>Стоит обратить внимание: %d продукт</b>\n\n",
+	"screening.flagged.few":     "⚠️ <b>Стоит обратить внимание: %d продукта</b>\n\n",
+	"screening.flagged.many":    "⚠️ <b>Стоит обратить внимание: %d продуктов</b>\n\n",
+	"screening.product":         "🔸 <b>%s %s</b>\n",
+	"screening.flag":            "   • %s (%s): <i>%s</i>\n",
+	"screening.clean.one":       "✅ Без совпадений: %d продукт\n",
+	"screening.clean.few":       "✅ Без совпадений: %d продукта\n",
+	"screening.clean.many":      "✅ Без совпадений: %d продуктов\n",
+	"screening.unknown.one":     "❔ Состав неизвестен, не проверен %d продукт:\n",
+	"screening.unknown.few":     "❔ Состав неизвестен, не проверены %d продукта:\n",
+	"screening.unknown.many":    "❔ Состав неизвестен, не проверены %d продуктов:\n",
+	"screening.unknown_product": "   • %s %s\n",
+	"screening.added_warning":   "⚠️ <b>%s %s</b> содержит ингредиенты, которых вы избегаете:\n",
+	"screening.disclaimer":      "\n<i>Проверка ищет ингредиенты по списку синонимов INCI и может что-то пропустить. При сильной аллергии сверяйтесь с упаковкой.</i>",
+
+	"screening.concern.fragrance":    "Отдушки",
+	"screening.concern.lanolin":      "Ланолин",
+	"screening.concern.preservative": "Консерванты",
+	"screening.concern.alcohol":      "Спирт",
+	"screening.concern.animal":       "Животного происхождения",
+	"screening.concern.slaughter":    "Получено при забое животных",
+	"screening.concern.gluten":       "Глютен",
+	"screening.concern.custom":       "Указано вами",
+
+	// Беременность и лактация
+	"safety.header":           "\n🤰 <b>Беременность и лактация</b>\n",
+	"safety.flag":             "%s <b>%s</b> — %s: <i>%s</i>\n%s\n",
+	"safety.high_dose":        "Судя по описанию, концентрация выше безопасной.\n",
+	"safety.disclaimer":       "<i>Это справка, а не назначение врача.</i>\n",
+	"safety.severity.avoid":   "не использовать",
+	"safety.severity.limit":   "только низкие концентрации",
+	"safety.severity.caution": "обсудите с врачом",
+
+	"safety.rule.retinoids.name":        "Ретиноиды",
+	"safety.rule.retinoids.text":        "Ретиноиды внутрь вызывают пороки развития плода, а безопасность наружных не доказана: при беременности их отменяют, при кормлении — только с разрешения врача.",
+	"safety.rule.hydroquinone.name":     "Гидрохинон",
+	"safety.rule.hydroquinone.text":     "Заметная часть гидрохинона всасывается через кожу, данных о безопасности для плода и ребенка нет.",
+	"safety.rule.salicylic.name":        "Салициловая кислота",
+	"safety.rule.salicylic.text":        "В смываемых средствах и в концентрации до 2% считается допустимой; пилинги и высокие концентрации не рекомендуются.",
+	"safety.rule.benzoyl_peroxide.name": "Бензоилпероксид",
+	"safety.rule.benzoyl_peroxide.text": "Всасывается слабо: точечно и в концентрации до 5% обычно допустим, на больших участках кожи — нет.",
+	"safety.rule.arbutin.name":          "Арбутин",
+	"safety.rule.arbutin.text":          "В коже может превращаться в гидрохинон, данных о безопасности при беременности мало.",
+	"safety.rule.oxybenzone.name":       "Оксибензон",
+	"safety.rule.oxybenzone.text":       "Химический УФ-фильтр, который попадает в кровь; безопаснее минеральные фильтры — оксид цинка и диоксид титана.",
+	"safety.rule.kojic.name":            "Койевая кислота",
+	"safety.rule.kojic.text":            "Исследований безопасности при беременности нет; осветление лучше отложить или обсудить с врачом.",
+
+	// План ухода
+	"routine.morning":  "☀️ Утро",
+	"routine.evening":  "🌙 Вечер",
+	"routine.warnings": "⚠️ Предупреждения",
+	"routine.missing":  "🧩 Чего не хватает",
+	"routine.tips":     "💡 Советы",
+
+	"routine.category.cleanser":    "очищение",
+	"routine.category.toner":       "тонер",
+	"routine.category.exfoliant":   "отшелушивание",
+	"routine.category.serum":       "сыворотка",
+	"routine.category.treatment":   "точечное средство",
+	"routine.category.eye_care":    "уход за кожей вокруг глаз",
+	"routine.category.moisturizer": "увлажнение",
+	"routine.category.oil":         "масло",
+	"routine.category.spf":         "SPF",
+	"routine.category.mask":        "маска",
+	"routine.category.other":       "другое",
+
+	"routine.frequency.daily":           "каждый день",
+	"routine.frequency.every_other_day": "через день",
+	"routine.frequency.twice_weekly":    "2 раза в неделю",
+	"routine.frequency.weekly":          "раз в неделю",
+	"routine.frequency.as_needed":       "по необходимости",
+
+	// Мой уход
+	"routine.builder.header":            "🗓 <b>Мой уход</b>\n\n",
+	"routine.builder.named":             "🗓 <b>%s</b>\n\n",
+	"routine.builder.slot":              "<b>%s</b>\n",
+	"routine.builder.step":              "%d. <b>%s %s</b> — %s, %s\n",
+	"routine.builder.slot_empty":        "<i>Шагов пока нет</i>\n",
+	"routine.builder.empty_hint":        "Соберите утренний и вечерний уход из продуктов коллекции в том порядке, в котором вы их наносите. Рекомендации по продуктам будут учитывать, как вы ими пользуетесь.\n",
+	"routine.builder.issues":            "⚠️ <b>Порядок нанесения</b>\nСредства наносят от очищения к защите: очищение → тонер → сыворотка → увлажнение → SPF.\n",
+	"routine.builder.issue.order":       "• %s, шаг %d: %s (%s) стоит после %s (%s)\n",
+	"routine.builder.issue.evening_spf": "• %s, шаг %d: %s — SPF вечером не нужен\n",
+	"routine.builder.add":               "➕ %s",
+	"routine.builder.edit":              "✏️ %s",
+	"routine.builder.sort":              "🔃 Расставить по порядку",
+	"routine.builder.rename":            "🏷 Название",
+	"routine.builder.clear":             "🗑 Очистить уход",
+	"routine.builder.back":              "⬅️ Назад к уходу",
+	"routine.builder.done":              "✅ Готово",
+	"routine.builder.cancel":            "✖️ Отмена",
+	"routine.builder.up":                "⬆️ %d",
+	"routine.builder.down":              "⬇️ %d",
+	"routine.builder.remove":            "❌ %d",
+	"routine.builder.load_failed":       "❌ Не удалось загрузить ваш уход. Попробуйте еще раз позже.",
+	"routine.builder.save_failed":       "❌ Не удалось сохранить уход. Попробуйте еще раз позже.",
+	"routine.builder.collection_empty":  "🧴 В коллекции пока нет продуктов.\n\n<b>Для поиска продуктов введите:</b>\n@cosmetics_lab_ai_bot add [название продукта]",
+	"routine.builder.all_added":         "<b>%s</b>\n\nВсе продукты коллекции уже есть в этом уходе.",
+	"routine.builder.choose_product":    "<b>%s</b>\n\nВыберите продукт из коллекции:",
+	"routine.builder.choose_category":   "<b>%s</b>\n\n<b>%s %s</b>\nНа каком этапе ухода вы его используете?",
+	"routine.builder.choose_frequency":  "<b>%s</b>\n\n<b>%s %s</b> — %s\nКак часто?",
+	"routine.builder.not_in_collection": "❌ Этого продукта уже нет в вашей коллекции.",
+	"routine.builder.edit_header":       "<b>%s</b>\nПереставьте шаги стрелками или уберите лишние:\n\n",
+	"routine.builder.name_prompt":       "🏷 Отправьте название ухода одним сообщением, до %d символов.",
+	"routine.builder.name_invalid":      "❌ Название должно быть непустым и не длиннее %d символов. Отправьте другое.",
+
+	// Совместимость активов
+	"conflicts.check":            "⚗️ Совместимость средств",
+	"conflicts.loading":          "⚗️ Проверяю, какие средства нельзя наносить вместе...",
+	"conflicts.failed":           "❌ Не удалось проверить совместимость. Попробуйте еще раз позже.",
+	"conflicts.header":           "⚗️ <b>Совместимость средств</b>\n\n",
+	"conflicts.nothing_found":    "✅ Конфликтующих активов в коллекции не найдено.\n\n",
+	"conflicts.found.one":        "⚠️ <b>Найден %d конфликт</b>\n\n",
+	"conflicts.found.few":        "⚠️ <b>Найдено %d конфликта</b>\n\n",
+	"conflicts.found.many":       "⚠️ <b>Найдено %d конфликтов</b>\n\n",
+	"conflicts.product":          "🔸 <b>%s %s</b> — %s: <i>%s</i>\n",
+	"conflicts.reason":           "%s\n",
+	"conflicts.advice.split":     "👉 Разнесите: %s — %s, %s — %s.\n",
+	"conflicts.advice.alternate": "👉 Не наносите в один день, чередуйте.\n",
+	"conflicts.slot.am":          "утром",
+	"conflicts.slot.pm":          "вечером",
+	"conflicts.partial":          "\n⚠️ Часть данных об ингредиентах не успела загрузиться, проверка могла пропустить конфликты. Попробуйте еще раз чуть позже.\n",
+	"conflicts.disclaimer":       "\n<i>Проверка ищет известные активы по составу и не учитывает концентрации. Если кожа спокойно переносит сочетание, его можно оставить.</i>",
+
+	"conflicts.class.retinoid":         "ретиноиды",
+	"conflicts.class.acid":             "кислоты (AHA/BHA/PHA)",
+	"conflicts.class.vitamin_c":        "витамин C",
+	"conflicts.class.benzoyl_peroxide": "бензоилпероксид",
+	"conflicts.class.copper_peptide":   "пептиды меди",
+	"conflicts.class.hydroquinone":     "гидрохинон",
+
+	"conflicts.rule.retinoid_acid":      "Ретиноиды и кислоты вместе сильно раздражают и истончают роговой слой.",
+	"conflicts.rule.retinoid_retinoid":  "Два ретиноида в один день удваивают раздражение без дополнительной пользы.",
+	"conflicts.rule.retinoid_bpo":       "Бензоилпероксид окисляет ретиноиды и снижает их действие, а вместе они пересушивают кожу.",
+	"conflicts.rule.retinoid_vitamin_c": "Витамин C лучше работает утром как антиоксидант, ретиноиды разрушаются на свету и нужны вечером.",
+	"conflicts.rule.vitamin_c_acid":     "Кислоты меняют pH и вместе с витамином C усиливают раздражение.",
+	"conflicts.rule.vitamin_c_bpo":      "Бензоилпероксид окисляет витамин C, и тот перестает работать.",
+	"conflicts.rule.vitamin_c_copper":   "Ионы меди разрушают витамин C, а он — пептиды меди.",
+	"conflicts.rule.acid_copper":        "Кислоты разрушают пептиды меди и снижают их действие.",
+	"conflicts.rule.acid_bpo":           "Кислоты и бензоилпероксид вместе пересушивают и раздражают кожу.",
+	"conflicts.rule.bpo_hydroquinone":   "Бензоилпероксид с гидрохиноном временно окрашивают кожу.",
+
+	// Рекомендации
+	"recs.caption": `🤖 <b>Рекомендации</b>
+
+Мы можем предложить тебе советы по уходу на основе анкеты, твоих текущих продуктов, или подсказать, чего не хватает в твоем уходе.
+
+<i>Данные рекомендации только для ознакомления и не заменяют консультацию дерматолога и не ставят точные диагнозы.</i>`,
+	"recs.anketa.title":       "Рекомендации на основе анкеты",
+	"recs.anketa.progress":    "🤖 Генерирую рекомендации на основе вашей анкеты...\n\n⏳ Это может занять до 2 минут. Пожалуйста, подождите...",
+	"recs.products.title":     "Рекомендации с учётом моих продуктов",
+	"recs.products.progress":  "🤖 Генерирую рекомендации с учётом ваших продуктов...\n\n⏳ Это может занять до 2 минут. Пожалуйста, подождите...",
+	"recs.general.title":      "Общие рекомендации",
+	"recs.general.progress":   "🤖 Генерирую общие рекомендации...\n\n⏳ Это может занять до 2 минут. Пожалуйста, подождите...",
+	"recs.retry":              "🔄 Попробовать снова",
+	"recs.regenerate":         "🔄 Сгенерировать заново",
+	"recs.cancel":             "✖️ Отменить",
+	"recs.cancel.done":        "Генерация отменена",
+	"recs.cancel.nothing":     "Генерация уже завершилась",
+	"recs.cancelled":          "⛔ Генерация рекомендаций отменена.",
+	"recs.in_progress":        "⏳ Уже генерирую… Ответ появится в сообщении выше, повторно нажимать не нужно.",
+	"recs.back":               "⬅️ Назад к рекомендациям",
+	"recs.error.timeout":      "⏰ Время ожидания истекло. Нейросеть работает медленно. Попробуйте еще раз через несколько минут.",
+	"recs.error.rate_limited": "🚦 Слишком много запросов к нейросети. Попробуйте еще раз через минуту.",
+	"recs.error.overloaded":   "🔥 Нейросеть сейчас перегружена. Попробуйте еще раз чуть позже.",
+	"recs.error.unauthorized": "🔑 Ошибка аутентификации. Проверьте настройки API.",
+	"recs.error.bad_request":  "❌ Нейросеть не смогла обработать запрос. Попробуйте изменить анкету или список продуктов.",
+	"recs.error.malformed":    "❌ Нейросеть вернула план в неверном формате. Попробуйте еще раз.",
+	"recs.error.unknown":      "❌ Не удалось получить рекомендации. Попробуйте еще раз позже.",
+
+	// История рекомендаций
+	"recs.history":                 "📜 История",
+	"history.empty":                "📜 История пуста. Здесь будут сохраняться ваши рекомендации.",
+	"history.load_failed":          "❌ Не удалось загрузить историю рекомендаций. Попробуйте позже.",
+	"history.not_found":            "❌ Эта рекомендация не найдена в истории.",
+	"history.header":               "📜 <b>История рекомендаций</b> (страница %d из %d)\n\nВыберите рекомендацию, чтобы открыть ее или сравнить с предыдущей.",
+	"history.item":                 "%s %s · %s",
+	"history.newer":                "◀️ Новее",
+	"history.older":                "Старше ▶️",
+	"history.back":                 "⬅️ К истории",
+	"history.open":                 "📄 Открыть рекомендацию",
+	"history.view.header":          "%s <b>%s</b>\n🕒 %s · 🤖 %s\n\n",
+	"history.diff":                 "🔀 Что изменилось",
+	"history.diff.none":            "Это первая рекомендация такого типа — сравнивать не с чем.",
+	"history.diff.header":          "🔀 <b>%s: что изменилось</b>\n🕒 %s → %s\n\n",
+	"history.diff.profile":         "<b>Анкета</b>\n",
+	"history.diff.profile_same":    "Анкета не менялась.\n",
+	"history.diff.answer":          "%s %s: %s → %s\n",
+	"history.diff.products":        "\n<b>Коллекция</b>\n",
+	"history.diff.products_same":   "\nКоллекция не менялась.\n",
+	"history.diff.product_added":   "➕ %s %s\n",
+	"history.diff.product_removed": "➖ %s %s\n",
+	"history.diff.model":           "\n🤖 Модель: %s → %s\n",
+	"history.diff.text":            "\n<b>Текст рекомендации</b>\n",
+	"history.diff.text_same":       "\nТекст рекомендации не изменился.\n",
+	"history.diff.line_added":      "➕ %s\n",
+	"history.diff.line_removed":    "➖ %s\n",
+	"history.diff.more_lines.one":  "… и еще %d измененная строка\n",
+	"history.diff.more_lines.few":  "… и еще %d измененные строки\n",
+	"history.diff.more_lines.many": "… и еще %d измененных строк\n",
+
+	// Inline-поиск
+	"inline.too_short.title":       "⚠️ Запрос слишком короткий",
+	"inline.too_short.text":        "Введите минимум 3 символа для поиска продуктов.",
+	"inline.too_short.description": "Минимум 3 символа для поиска",
+	"inline.error.title":           "❌ Ошибка поиска",
+	"inline.error.text":            "Произошла ошибка при поиске продуктов. Попробуйте позже.",
+	"inline.error.description":     "Ошибка соединения с сервером",
+	"inline.not_found.title":       "❌ Продукт не найден",
+	"inline.not_found.text":        "По вашему запросу ничего не найдено. Попробуйте другой поисковый запрос.",
+	"inline.not_found.description": "По запросу '%s' ничего не найдено",
+
+	// Вопросы анкеты: question.<id>.title и question.<id>.text
+	"question.skin_type.title":   "Тип кожи",
+	"question.skin_type.text":    "Какой ваш тип кожи?\n\nТип кожи влияет на выбор текстур и активных ингредиентов — от этого зависит, как хорошо средство будет работать",
+	"question.age.title":         "Возраст",
+	"question.age.text":          "Какой ваш возраст?\n\nВ 20, 30 и 50 лет коже нужны разные вещи. Уточним возраст, чтобы подобрать то, что подходит именно вам",
+	"question.gender.title":      "Пол",
+	"question.gender.text":       "Укажите ваш пол\n\nМужская и женская кожа отличаются по структуре и гормональному фону — это помогает нам точнее подобрать уход",
+	"question.pregnancy.title":   "Беременность/лактация",
+	"question.pregnancy.text":    "Находитесь ли вы сейчас в периоде беременности или кормления?\n\nНекоторые ингредиенты не рекомендуются в этот период. Мы подберём безопасные альтернативы.",
+	"question.concerns.title":    "Проблемы",
+	"question.concerns.text":     "Что беспокоит вас больше всего?\n\nЧто вы ждёте от ухода: убрать проблему, предотвратить, освежить внешний вид? Ответ в свободной форме, например:\n\nХочу исправить повышенную чувствительность у моей кожи, а так же меня беспокоит акне и чёрные точки",
+	"question.goal.title":        "Цель",
+	"question.goal.text":         "Какой результат вы хотите получить?\n\nВаша цель = наша стратегия. Разберёмся, куда стремиться. Если ни один из вариантов не подходит, вы можете написать ответ в свободной форме",
+	"question.climate.title":     "Климат",
+	"question.climate.text":      "Какой у вас климат?\n\nКлимат влияет на потребности кожи в увлажнении и защите",
+	"question.fitzpatrick.title": "Тип кожи по Фицпатрику",
+	"question.fitzpatrick.text":  "Как бы вы описали свою кожу по реакции на солнце?\n\nЭто поможет подобрать правильную защиту от солнца",
+	"question.lifestyle.title":   "Образ жизни",
+	"question.lifestyle.text":    "Какой у вас ритм жизни?\n\nОбраз жизни влияет на выбор средств и режим ухода",
+	"question.diet.title":        "Питание",
+	"question.diet.text":         "Есть ли у вас особенности в питании или убеждения, которые важно учесть?\n\nЭто поможет подобрать подходящие ингредиенты",
+	"question.allergies.title":   "Аллергии",
+	"question.allergies.text":    "Есть ли у вас аллергии или непереносимость?\n\nВажно знать, чтобы исключить проблемные ингредиенты",
+
+	// Текст кнопки, если он короче или иначе сформулирован, чем название в справочнике
+	"option.skin_unknown":            "Я не знаю какой у меня тип",
+	"option.age_18_minus":            "<18",
+	"option.age_18_24":               "18–24",
+	"option.age_25_34":               "25–34",
+	"option.age_35_44":               "35–44",
+	"option.age_45_plus":             "45+",
+	"option.pregnancy_and_lactation": "И то, и другое",
+	"option.goal_other":              "Другое (напишу сам)",
+	"option.allergies_other":         "Другое (напишу сам)",
+
+	// Просьбы уточнить ответ «Другое»
+	"followup.goal_other":      "Опишите своими словами, какой результат вы хотите получить",
+	"followup.lifestyle_other": "Расскажите, что еще в вашем образе жизни важно учесть",
+	"followup.diet_other":      "Напишите, какие особенности питания или убеждения нужно учесть",
+	"followup.allergies_other": "Напишите, на что у вас аллергия или непереносимость",
+
+	// Промпты для языковой модели
+	"prompt.language":        "Отвечай на русском языке.",
+	"prompt.answer":          "%s: %s",
+	"prompt.answer_other":    "%s: %s (уточнение пользователя: %s)",
+	"prompt.no_products":     "У пользователя пока нет добавленных продуктов.",
+	"prompt.product":         "- %s (%s), id %d",
+	"prompt.product_details": "  Описание: %s",
+
+	"prompt.safety.header":           "ОБЯЗАТЕЛЬНЫЕ ОГРАНИЧЕНИЯ БЕЗОПАСНОСТИ. Пользователь указал в анкете: %s. Эти правила важнее любых других пожеланий и нарушать их нельзя:\n",
+	"prompt.safety.rule":             "- %s: %s.\n",
+	"prompt.safety.severity.avoid":   "не рекомендовать ни в каком виде и ни в какой концентрации",
+	"prompt.safety.severity.limit":   "только низкие концентрации в смываемых или точечных средствах; пилинги и высокие концентрации не рекомендовать",
+	"prompt.safety.severity.caution": "рекомендовать только с прямой оговоркой, что применение нужно обсудить с врачом",
+	"prompt.safety.products":         "\nВ коллекции пользователя есть продукты с этими активами. Для каждого прямо напиши, что его нужно отложить или ограничить, и предложи безопасную замену:\n",
+	"prompt.safety.product":          "- %s %s: %s (%s) — %s.\n",
+
+	"prompt.conflicts": "\n\nВ коллекции пользователя найдены средства, которые нельзя наносить в одно время. Анализ в формате JSON: advice = \"split\" — одно средство утром, другое вечером, время указано в slot (am — утро, pm — вечер); advice = \"alternate\" — в разные дни. Не ставь такие средства в один шаг ухода и объясни пользователю, как их разнести:\n%s",
+
+	"prompt.user_routine":                   "Пользователь сам составил свой уход. Сохрани его порядок и частоту, если нет веских причин их менять; каждое изменение объясни. Шаги указаны в порядке нанесения:",
+	"prompt.user_routine.named":             "Пользователь сам составил свой уход «%s». Сохрани его порядок и частоту, если нет веских причин их менять; каждое изменение объясни. Шаги указаны в порядке нанесения:",
+	"prompt.user_routine.slot.am":           "Утро:",
+	"prompt.user_routine.slot.pm":           "Вечер:",
+	"prompt.user_routine.slot_empty":        "- шагов нет",
+	"prompt.user_routine.step":              "%d. %s (%s), id %d — этап: %s, частота: %s",
+	"prompt.user_routine.unused":            "Продукты коллекции, которые пользователь не включил в уход:",
+	"prompt.user_routine.issues":            "Нарушения порядка нанесения (очищение → тонер → сыворотка → увлажнение → SPF), которые нужно исправить:",
+	"prompt.user_routine.issue.order":       "- %s %s (%s) стоит после %s (%s)",
+	"prompt.user_routine.issue.evening_spf": "- Вечер: %s — солнцезащитное средство вечером не нужно",
+
+	"prompt.routine": `Ответ — строго один JSON-объект по схеме skincare_routine, без Markdown и текста вокруг. Все тексты внутри JSON пиши на языке пользователя.
+- summary: 2–4 предложения — главный вывод о коже и уходе.
+- morning, evening: шаги в порядке нанесения. category — этап ухода; product — название продукта из коллекции или описание средства, которое стоит купить; product_id — id продукта из коллекции или null, если средства в коллекции нет; purpose — зачем этот шаг; frequency — как часто (daily, every_other_day, twice_weekly, weekly, as_needed).
+- warnings: предупреждения об аллергенах, несовместимых активах и ограничениях.
+- missing: этапы, которых не хватает, с причиной.
+- tips: общие советы по уходу, образу жизни, питанию и сезонности.
+Если раздел не нужен, оставь пустой массив.`,
+	"prompt.routine.retry": "Ответ не прошел проверку: %s. Верни исправленный план — строго один JSON-объект по схеме skincare_routine.",
+
+	"prompt.anketa": `Ты — профессиональный косметолог и дерматолог-консультант.
+
+На основе анкеты, составь рекомендации:
+- Распиши оптимальный план ухода (утро/вечер) с последовательностью применения (step-by-step).
+- Учитывай тип кожи, возраст, пол, беременность, аллергию на ингредиенты, климат и цели ухода.
+- Если найден потенциальный аллерген в составе — предупреди.
+- Рекомендации должны быть понятными и аккуратными, как будто ты — дерматолог-консультант.
+- Укажи, какие продукты можно использовать утром, какие вечером, какие через день, какие несовместимы между собой.
+- В конце дай 2–3 рекомендации по продуктам, которых явно не хватает.
+
+**Анкета пользователя:**
+%s`,
+	"prompt.products": `Ты — профессиональный косметолог и дерматолог-консультант.
+
+На основе анкеты и списка косметических средств, составь рекомендации:
+- Распиши оптимальный план ухода (утро/вечер) с последовательностью применения (step-by-step).
+- Используй только средства, которые уже есть у пользователя, **но укажи, если какого-то этапа не хватает**.
+- Учитывай тип кожи, возраст, пол, беременность, аллергию на ингредиенты, климат и цели ухода.
+- Если найден потенциальный аллерген в составе — предупреди.
+- Рекомендации должны быть понятными и аккуратными, как будто ты — дерматолог-консультант.
+- Укажи, какие продукты можно использовать утром, какие вечером, какие через день, какие несовместимы между собой.
+- В конце дай 2–3 рекомендации по продуктам, которых явно не хватает.
+
+**Анкета пользователя:**
+%s
+
+**Продукты пользователя:**
+%s`,
+	"prompt.general": `Ты — профессиональный косметолог и дерматолог-консультант.
+
+На основе анкеты и списка косметических средств, составь общие рекомендации:
+- Проанализируй текущий уход и дай общие советы по улучшению.
+- Укажи, какие этапы ухода отсутствуют или недостаточно проработаны.
+- Дай рекомендации по изменению образа жизни для улучшения состояния кожи.
+- Предложи общие принципы ухода, которые подходят для данного типа кожи и возраста.
+- Укажи сезонные особенности ухода.
+- Дай советы по питанию и образу жизни для здоровья кожи.
+
+**Анкета пользователя:**
+%s
+
+**Продукты пользователя:**
+%s`,
+}
@@ -0,0 +1,73 @@
+// Package llmcache кэширует ответы языковой модели: одинаковый запрос
+// (промпт, модель и параметры) в течение TTL не отправляется модели повторно.
+package llmcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"cos-ai-bot/internal/api"
+)
+
+// ErrNotFound ответа с таким ключом нет в кэше или срок его хранения истек
+var ErrNotFound = errors.New("ответ не найден в кэше")
+
+// Store хранилище ответов модели по ключу запроса
+type Store interface {
+	// Get возвращает сохраненный ответ или ErrNotFound
+	Get(ctx context.Context, key string) (*api.ChatResponse, error)
+	// Set сохраняет ответ, заменяя предыдущий с тем же ключом
+	Set(ctx context.Context, key string, response *api.ChatResponse) error
+	// DeleteExpired удаляет ответы старше TTL и возвращает их количество
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// Key вычисляет ключ кэша: SHA-256 от всего, что влияет на ответ модели —
+// сообщений, основной и резервных моделей, параметров генерации и формата ответа
+func Key(req api.ChatRequest) string {
+	data, _ := json.Marshal(struct {
+		Model          string              `json:"model"`
+		FallbackModels []string            `json:"fallback_models"`
+		Messages       []api.Message       `json:"messages"`
+		MaxTokens      int                 `json:"max_tokens"`
+		Temperature    float64             `json:"temperature"`
+		ResponseFormat *api.ResponseFormat `json:"response_format,omitempty"`
+	}{req.Model, req.FallbackModels, req.Messages, req.MaxTokens, req.Temperature, req.ResponseFormat})
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// bypassKey ключ контекста, отключающего чтение из кэша
+type bypassKey struct{}
+
+// WithBypass возвращает контекст, в котором ответ из кэша не используется:
+// запрос уходит модели, а новый ответ заменяет сохраненный
+func WithBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassKey{}, true)
+}
+
+// bypassed проверяет, запрошен ли обход кэша
+func bypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassKey{}).(bool)
+	return bypass
+}
+
+// validatorKey ключ контекста с проверкой ответа
+type validatorKey struct{}
+
+// WithValidator возвращает контекст, в котором кэш принимает только ответы,
+// прошедшие validate: негодный ответ не сохраняется, а сохраненный ранее
+// считается отсутствующим
+func WithValidator(ctx context.Context, validate func(content string) error) context.Context {
+	return context.WithValue(ctx, validatorKey{}, validate)
+}
+
+// valid проверяет ответ проверкой из контекста, если она задана
+func valid(ctx context.Context, content string) bool {
+	validate, ok := ctx.Value(validatorKey{}).(func(content string) error)
+	return !ok || validate(content) == nil
+}
@@ -0,0 +1,73 @@
+package llmcache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"cos-ai-bot/internal/api"
+)
+
+// MemoryStore хранилище ответов в памяти процесса; теряется при перезапуске
+type MemoryStore struct {
+	mu      sync.RWMutex
+	entries map[string]memoryEntry
+	ttl     time.Duration
+}
+
+// memoryEntry ответ вместе со временем сохранения
+type memoryEntry struct {
+	response  api.ChatResponse
+	createdAt time.Time
+}
+
+// NewMemoryStore создает хранилище, в котором ответы живут ttl
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]memoryEntry),
+		ttl:     ttl,
+	}
+}
+
+// Get возвращает копию сохраненного ответа
+func (s *MemoryStore) Get(ctx context.Context, key string) (*api.ChatResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.entries[key]
+	if !ok || s.expired(entry, time.Now()) {
+		return nil, ErrNotFound
+	}
+	response := entry.response
+	return &response, nil
+}
+
+// Set сохраняет копию ответа
+func (s *MemoryStore) Set(ctx context.Context, key string, response *api.ChatResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = memoryEntry{response: *response, createdAt: time.Now()}
+	return nil
+}
+
+// DeleteExpired удаляет ответы старше TTL
+func (s *MemoryStore) DeleteExpired(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var deleted int64
+	for key, entry := range s.entries {
+		if s.expired(entry, now) {
+			delete(s.entries, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// expired проверяет, истек ли срок хранения ответа
+func (s *MemoryStore) expired(entry memoryEntry, now time.Time) bool {
+	return now.Sub(entry.createdAt) > s.ttl
+}
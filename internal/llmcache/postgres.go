@@ -0,0 +1,65 @@
+package llmcache
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"cos-ai-bot/internal/api"
+)
+
+// PostgresStore хранилище ответов в таблице llm_cache
+// (см. migrations/005_llm_cache.sql); кэш общий для всех экземпляров бота
+type PostgresStore struct {
+	db  *sql.DB
+	ttl time.Duration
+}
+
+// NewPostgresStore создает хранилище поверх открытого подключения
+func NewPostgresStore(db *sql.DB, ttl time.Duration) *PostgresStore {
+	return &PostgresStore{db: db, ttl: ttl}
+}
+
+// Get возвращает ответ, сохраненный не раньше чем TTL назад
+func (s *PostgresStore) Get(ctx context.Context, key string) (*api.ChatResponse, error) {
+	var response api.ChatResponse
+	err := s.db.QueryRowContext(ctx, `
+		SELECT model, content FROM llm_cache
+		WHERE key = $1 AND created_at >= CURRENT_TIMESTAMP - make_interval(secs => $2::float8)`,
+		key, s.ttl.Seconds(),
+	).Scan(&response.Model, &response.Content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения ответа из кэша: %v", err)
+	}
+	return &response, nil
+}
+
+// Set сохраняет ответ, обновляя время сохранения
+func (s *PostgresStore) Set(ctx context.Context, key string, response *api.ChatResponse) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO llm_cache (key, model, content, created_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+		ON CONFLICT (key) DO UPDATE
+		SET model = EXCLUDED.model, content = EXCLUDED.content, created_at = EXCLUDED.created_at`,
+		key, response.Model, response.Content)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения ответа в кэш: %v", err)
+	}
+	return nil
+}
+
+// DeleteExpired удаляет ответы старше TTL
+func (s *PostgresStore) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM llm_cache WHERE created_at < CURRENT_TIMESTAMP - make_interval(secs => $1::float8)`,
+		s.ttl.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("ошибка удаления устаревших ответов из кэша: %v", err)
+	}
+	return result.RowsAffected()
+}
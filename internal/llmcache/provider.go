@@ -0,0 +1,131 @@
+package llmcache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync/atomic"
+
+	"cos-ai-bot/internal/api"
+)
+
+// Счетчики кэша с момента запуска, см. Snapshot
+var (
+	hits     atomic.Int64 // ответ взят из кэша
+	misses   atomic.Int64 // ответа в кэше не было, запрос ушел модели
+	bypasses atomic.Int64 // пользователь попросил сгенерировать заново
+	failures atomic.Int64 // хранилище вернуло ошибку
+)
+
+// Stats значения счетчиков кэша
+type Stats struct {
+	Hits     int64
+	Misses   int64
+	Bypasses int64
+	Errors   int64
+}
+
+// Snapshot возвращает текущие значения счетчиков кэша
+func Snapshot() Stats {
+	return Stats{
+		Hits:     hits.Load(),
+		Misses:   misses.Load(),
+		Bypasses: bypasses.Load(),
+		Errors:   failures.Load(),
+	}
+}
+
+// Requests число запросов, прошедших через кэш
+func (s Stats) Requests() int64 {
+	return s.Hits + s.Misses + s.Bypasses
+}
+
+// HitRatio доля запросов, на которые ответ нашелся в кэше
+func (s Stats) HitRatio() float64 {
+	if s.Requests() == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Requests())
+}
+
+// Provider отвечает на повторные запросы из кэша, не обращаясь к модели.
+// Ошибки хранилища не мешают генерации: запрос просто уходит модели.
+type Provider struct {
+	provider api.LLMProvider
+	store    Store
+}
+
+// NewProvider оборачивает провайдер кэшем ответов
+func NewProvider(provider api.LLMProvider, store Store) *Provider {
+	return &Provider{provider: provider, store: store}
+}
+
+// ChatCompletion возвращает ответ из кэша или запрашивает модель
+func (p *Provider) ChatCompletion(ctx context.Context, req api.ChatRequest) (*api.ChatResponse, error) {
+	return p.do(ctx, req, nil, func() (*api.ChatResponse, error) {
+		return p.provider.ChatCompletion(ctx, req)
+	})
+}
+
+// ChatCompletionStream возвращает ответ из кэша одним фрагментом
+// или генерирует его потоком
+func (p *Provider) ChatCompletionStream(ctx context.Context, req api.ChatRequest, onDelta api.DeltaFunc) (*api.ChatResponse, error) {
+	streaming, ok := p.provider.(api.StreamingLLMProvider)
+	if !ok {
+		return p.do(ctx, req, onDelta, func() (*api.ChatResponse, error) {
+			response, err := p.provider.ChatCompletion(ctx, req)
+			if err == nil && onDelta != nil {
+				onDelta(response.Content)
+			}
+			return response, err
+		})
+	}
+	return p.do(ctx, req, onDelta, func() (*api.ChatResponse, error) {
+		return streaming.ChatCompletionStream(ctx, req, onDelta)
+	})
+}
+
+// do ищет ответ в кэше, а если его нет — вызывает call и сохраняет результат
+func (p *Provider) do(ctx context.Context, req api.ChatRequest, onDelta api.DeltaFunc, call func() (*api.ChatResponse, error)) (*api.ChatResponse, error) {
+	key := Key(req)
+
+	if bypassed(ctx) {
+		bypasses.Add(1)
+	} else {
+		cached, err := p.store.Get(ctx, key)
+		switch {
+		case err == nil && !valid(ctx, cached.Content):
+			misses.Add(1)
+			log.Printf("Ответ модели %s из кэша не прошел проверку, запрашиваем заново", cached.Model)
+		case err == nil:
+			hits.Add(1)
+			log.Printf("Ответ модели %s взят из кэша, доля попаданий %.0f%%", cached.Model, Snapshot().HitRatio()*100)
+			if onDelta != nil {
+				onDelta(cached.Content)
+			}
+			cached.Cached = true
+			return cached, nil
+		case errors.Is(err, ErrNotFound):
+			misses.Add(1)
+		default:
+			failures.Add(1)
+			misses.Add(1)
+			log.Printf("Ошибка чтения кэша ответов модели: %v", err)
+		}
+	}
+
+	response, err := call()
+	if err != nil {
+		return nil, err
+	}
+
+	// Пустой или негодный ответ не кэшируем: следующий запрос должен дать модели еще шанс
+	if strings.TrimSpace(response.Content) != "" && valid(ctx, response.Content) {
+		if err := p.store.Set(ctx, key, response); err != nil {
+			failures.Add(1)
+			log.Printf("Ошибка сохранения ответа модели в кэш: %v", err)
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,283 @@
+package markdown
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tag набор тегов, открытых вокруг разбираемого текста
+type tag uint8
+
+const (
+	tagBold tag = 1 << iota
+	tagItalic
+	tagStrike
+	tagLink
+)
+
+// linkSchemes схемы ссылок, которые показываются ссылками; для остальных
+// остается только текст ссылки
+var linkSchemes = []string{"http://", "https://", "tg://", "mailto:"}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+)
+
+// escapeText экранирует символы, которые Telegram считает разметкой
+func escapeText(text string) string {
+	return textEscaper.Replace(text)
+}
+
+// escapeAttr экранирует значение атрибута тега
+func escapeAttr(text string) string {
+	return attrEscaper.Replace(text)
+}
+
+// renderInline преобразует строчную разметку: **жирный**, *курсив*, ~~зачеркнутый~~,
+// `код` и [ссылки](url). Каждая пара разделителей разбирается целиком вместе
+// с содержимым, поэтому теги всегда закрываются в обратном порядке.
+// Теги из open уже открыты снаружи и повторно не открываются.
+func renderInline(text string, open tag) string {
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch c {
+		case '\\':
+			if i+1 < len(text) && isEscapable(text[i+1]) {
+				b.WriteString(escapeText(text[i+1 : i+2]))
+				i += 2
+				continue
+			}
+		case '`':
+			n := runLength(text, i)
+			if end := findCodeEnd(text, i+n, n); end >= 0 {
+				b.WriteString("<code>" + escapeText(trimCodeSpan(text[i+n:end])) + "</code>")
+				i = end + n
+			} else {
+				b.WriteString(text[i : i+n])
+				i += n
+			}
+			continue
+		case '[':
+			if label, url, end, ok := parseLink(text, i); ok {
+				b.WriteString(renderLink(label, url, open))
+				i = end
+				continue
+			}
+		case '*', '_', '~':
+			n := runLength(text, i)
+			if isDelimiter(c, n) && canOpen(text, i, n) {
+				if end := findCloser(text, i+n, c, n); end >= 0 {
+					b.WriteString(wrap(delimiterTags(c, n), text[i+n:end], open))
+					i = end + n
+					continue
+				}
+			}
+			b.WriteString(text[i : i+n])
+			i += n
+			continue
+		}
+		b.WriteString(escapeText(text[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+// wrap оборачивает содержимое в теги, которые еще не открыты
+func wrap(tags tag, inner string, open tag) string {
+	tags &^= open
+	var before, after string
+	if tags&tagBold != 0 {
+		before, after = before+"<b>", "</b>"+after
+	}
+	if tags&tagItalic != 0 {
+		before, after = before+"<i>", "</i>"+after
+	}
+	if tags&tagStrike != 0 {
+		before, after = before+"<s>", "</s>"+after
+	}
+	return before + renderInline(inner, open|tags) + after
+}
+
+// renderLink рисует ссылку; вложенные ссылки и ссылки с неподдерживаемой
+// схемой показываются только текстом
+func renderLink(label, url string, open tag) string {
+	if open&tagLink != 0 || !hasLinkScheme(url) {
+		return renderInline(label, open)
+	}
+	return `<a href="` + escapeAttr(url) + `">` + renderInline(label, open|tagLink) + "</a>"
+}
+
+// hasLinkScheme проверяет, что ссылка ведет туда, куда Telegram умеет ссылаться
+func hasLinkScheme(url string) bool {
+	lower := strings.ToLower(url)
+	for _, scheme := range linkSchemes {
+		if strings.HasPrefix(lower, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDelimiter проверяет, что серия символов — разделитель разметки:
+// * и _ по одному, два или три раза, ~ ровно два раза
+func isDelimiter(c byte, n int) bool {
+	if c == '~' {
+		return n == 2
+	}
+	return n <= 3
+}
+
+// delimiterTags теги, которые открывает серия разделителей
+func delimiterTags(c byte, n int) tag {
+	switch {
+	case c == '~':
+		return tagStrike
+	case n == 1:
+		return tagItalic
+	case n == 2:
+		return tagBold
+	default:
+		return tagBold | tagItalic
+	}
+}
+
+// findCloser ищет закрывающую серию той же длины, пропуская экранированные
+// символы, код и серии другой длины (они разбираются при разборе содержимого)
+func findCloser(text string, from int, c byte, n int) int {
+	for i := from; i < len(text); {
+		switch text[i] {
+		case '\\':
+			i += 2
+			continue
+		case '`':
+			m := runLength(text, i)
+			if end := findCodeEnd(text, i+m, m); end >= 0 {
+				i = end + m
+			} else {
+				i += m
+			}
+			continue
+		case c:
+			m := runLength(text, i)
+			if m == n && i > from && canClose(text, i, m) {
+				return i
+			}
+			i += m
+			continue
+		}
+		i++
+	}
+	return -1
+}
+
+// canOpen проверяет, что серия в позиции i может открывать выделение:
+// за ней идет не пробел, а _ не стоит внутри слова (snake_case не курсив)
+func canOpen(text string, i, n int) bool {
+	next, _ := utf8.DecodeRuneInString(text[i+n:])
+	if i+n >= len(text) || unicode.IsSpace(next) {
+		return false
+	}
+	if text[i] == '_' {
+		prev, _ := utf8.DecodeLastRuneInString(text[:i])
+		return i == 0 || !isWordRune(prev)
+	}
+	return true
+}
+
+// canClose проверяет, что серия в позиции i может закрывать выделение
+func canClose(text string, i, n int) bool {
+	prev, _ := utf8.DecodeLastRuneInString(text[:i])
+	if i == 0 || unicode.IsSpace(prev) {
+		return false
+	}
+	if text[i] == '_' {
+		next, _ := utf8.DecodeRuneInString(text[i+n:])
+		return i+n >= len(text) || !isWordRune(next)
+	}
+	return true
+}
+
+// findCodeEnd ищет закрывающую серию обратных кавычек длины n
+func findCodeEnd(text string, from, n int) int {
+	for i := from; i < len(text); {
+		if text[i] != '`' {
+			i++
+			continue
+		}
+		m := runLength(text, i)
+		if m == n {
+			return i
+		}
+		i += m
+	}
+	return -1
+}
+
+// trimCodeSpan убирает по одному пробелу с обоих краев кода, как в CommonMark
+func trimCodeSpan(code string) string {
+	if len(code) >= 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+		return code[1 : len(code)-1]
+	}
+	return code
+}
+
+// parseLink разбирает [текст](url) начиная с позиции i;
+// end — позиция сразу после закрывающей скобки
+func parseLink(text string, i int) (label, url string, end int, ok bool) {
+	closeLabel := matchBracket(text, i, '[', ']')
+	if closeLabel < 0 || closeLabel+1 >= len(text) || text[closeLabel+1] != '(' {
+		return "", "", 0, false
+	}
+	closeURL := matchBracket(text, closeLabel+1, '(', ')')
+	if closeURL < 0 {
+		return "", "", 0, false
+	}
+
+	// Заголовок ссылки [текст](url "заголовок") Telegram не поддерживает
+	fields := strings.Fields(text[closeLabel+2 : closeURL])
+	if len(fields) > 0 {
+		url = strings.Trim(fields[0], "<>")
+	}
+	return text[i+1 : closeLabel], url, closeURL + 1, true
+}
+
+// matchBracket находит парную закрывающую скобку с учетом вложенности и экранирования
+func matchBracket(text string, i int, open, close byte) int {
+	depth := 0
+	for j := i; j < len(text); j++ {
+		switch text[j] {
+		case '\\':
+			j++
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// runLength длина серии одинаковых символов, начинающейся в позиции i
+func runLength(text string, i int) int {
+	n := 1
+	for i+n < len(text) && text[i+n] == text[i] {
+		n++
+	}
+	return n
+}
+
+// isEscapable проверяет, можно ли экранировать символ обратной косой чертой
+func isEscapable(c byte) bool {
+	return strings.IndexByte("\\`*_~{}[]()#+-.!|<>", c) >= 0
+}
+
+// isWordRune проверяет, что символ — часть слова
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
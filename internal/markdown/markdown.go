@@ -0,0 +1,221 @@
+// Package markdown преобразует Markdown из ответов модели в HTML,
+// который принимает Telegram (parse_mode=HTML).
+//
+// Telegram понимает только небольшое подмножество тегов: b, i, u, s, code,
+// pre, a, blockquote. Заголовки, списки и таблицы поэтому рисуются текстом
+// с этими тегами, а весь остальной текст экранируется, так что результат
+// всегда принимается Telegram: теги закрыты и вложены правильно.
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// ruleLine замена горизонтальной линии
+	ruleLine = "━━━━━━━━━━━━━━━━━━━━"
+	// maxListLevel максимальная вложенность списков; глубже отступ не растет
+	maxListLevel = 3
+)
+
+var (
+	headingPattern   = regexp.MustCompile(`^ {0,3}(#{1,6})[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+	rulePattern      = regexp.MustCompile(`^ {0,3}[-*_](?:[ \t]*[-*_])*[ \t]*$`)
+	bulletPattern    = regexp.MustCompile(`^([ \t]*)[-*+][ \t]+(.*)$`)
+	orderedPattern   = regexp.MustCompile(`^([ \t]*)(\d{1,9})[.)][ \t]+(.*)$`)
+	quotePattern     = regexp.MustCompile(`^ {0,3}>[ \t]?(.*)$`)
+	fencePattern     = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})[ \t]*([^`\\s]*)")
+	separatorPattern = regexp.MustCompile(`^[ \t]*\|?(?:[ \t]*:?-+:?[ \t]*\|)*[ \t]*:?-+:?[ \t]*\|?[ \t]*$`)
+)
+
+// keycaps эмодзи для номеров пунктов верхнего уровня
+var keycaps = []string{"1️⃣", "2️⃣", "3️⃣", "4️⃣", "5️⃣", "6️⃣", "7️⃣", "8️⃣", "9️⃣", "🔟"}
+
+// ToTelegramHTML преобразует Markdown в HTML для Telegram.
+//
+// Поддерживаются заголовки, жирный и курсивный текст, зачеркивание,
+// код и блоки кода, ссылки, цитаты, списки, таблицы и горизонтальные линии.
+// Непарные символы разметки остаются в тексте как есть.
+func ToTelegramHTML(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	lines := strings.Split(source, "\n")
+
+	var out []string
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if m := fencePattern.FindStringSubmatch(line); m != nil {
+			var code []string
+			for i++; i < len(lines); i++ {
+				if isClosingFence(lines[i], m[1]) {
+					break
+				}
+				code = append(code, lines[i])
+			}
+			out = append(out, renderCodeBlock(m[2], strings.Join(code, "\n")))
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			out = append(out, "")
+			continue
+		}
+
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			out = append(out, renderHeading(len(m[1]), m[2]))
+			continue
+		}
+
+		if rulePattern.MatchString(line) && isRule(line) {
+			out = append(out, ruleLine)
+			continue
+		}
+
+		if m := bulletPattern.FindStringSubmatch(line); m != nil {
+			level := listLevel(m[1])
+			bullet := "•"
+			if level > 0 {
+				bullet = "◦"
+			}
+			out = append(out, strings.Repeat("   ", level)+bullet+" "+renderInline(m[2], 0))
+			continue
+		}
+
+		if m := orderedPattern.FindStringSubmatch(line); m != nil {
+			level := listLevel(m[1])
+			out = append(out, strings.Repeat("   ", level)+listNumber(m[2], level)+" "+renderInline(m[3], 0))
+			continue
+		}
+
+		if quotePattern.MatchString(line) {
+			var quote []string
+			for ; i < len(lines); i++ {
+				m := quotePattern.FindStringSubmatch(lines[i])
+				if m == nil {
+					break
+				}
+				quote = append(quote, renderInline(m[1], 0))
+			}
+			i--
+			out = append(out, "<blockquote>"+strings.Join(quote, "\n")+"</blockquote>")
+			continue
+		}
+
+		if isTableRow(line) {
+			var rows []string
+			for ; i < len(lines) && isTableRow(lines[i]); i++ {
+				if separatorPattern.MatchString(lines[i]) {
+					continue
+				}
+				rows = append(rows, renderTableRow(lines[i], len(rows) == 0))
+			}
+			i--
+			out = append(out, strings.Join(rows, "\n"))
+			continue
+		}
+
+		out = append(out, renderInline(strings.TrimRight(line, " \t"), 0))
+	}
+
+	return collapseBlankLines(out)
+}
+
+// renderHeading рисует заголовок жирным с маркером уровня.
+// Маркер не добавляется, если модель сама начала заголовок с эмодзи.
+func renderHeading(level int, text string) string {
+	marker := "🔹 "
+	if level >= 4 {
+		marker = "🔸 "
+	}
+	if r, _ := utf8.DecodeRuneInString(text); unicode.Is(unicode.So, r) {
+		marker = ""
+	}
+	return marker + "<b>" + renderInline(text, tagBold) + "</b>"
+}
+
+// renderCodeBlock рисует блок кода; язык передается так, как ждет Telegram
+func renderCodeBlock(language, code string) string {
+	if language == "" {
+		return "<pre>" + escapeText(code) + "</pre>"
+	}
+	return `<pre><code class="language-` + escapeAttr(language) + `">` + escapeText(code) + "</code></pre>"
+}
+
+// isClosingFence проверяет, закрывает ли строка блок кода, открытый fence
+func isClosingFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, fence[:3]) &&
+		strings.Trim(trimmed, fence[:1]) == "" &&
+		len(trimmed) >= len(fence)
+}
+
+// isRule проверяет, что строка из символов линии (см. rulePattern) состоит
+// из одного символа, повторенного не меньше трех раз
+func isRule(line string) bool {
+	trimmed := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' {
+			return -1
+		}
+		return r
+	}, line)
+	return len(trimmed) >= 3 && strings.Count(trimmed, trimmed[:1]) == len(trimmed)
+}
+
+// listLevel вычисляет уровень вложенности пункта списка по отступу
+func listLevel(indent string) int {
+	width := 0
+	for _, r := range indent {
+		if r == '\t' {
+			width += 4
+		} else {
+			width++
+		}
+	}
+	return min(width/2, maxListLevel)
+}
+
+// listNumber номер пункта: эмодзи для первых десяти пунктов верхнего уровня
+func listNumber(number string, level int) string {
+	n, err := strconv.Atoi(number)
+	if err == nil && level == 0 && n >= 1 && n <= len(keycaps) {
+		return keycaps[n-1]
+	}
+	return number + "."
+}
+
+// isTableRow проверяет, похожа ли строка на строку таблицы: | a | b |
+func isTableRow(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "|") && strings.Count(trimmed, "|") >= 2
+}
+
+// renderTableRow рисует строку таблицы ячейками через разделитель;
+// Telegram не поддерживает таблицы, поэтому заголовок выделяется жирным
+func renderTableRow(line string, header bool) string {
+	trimmed := strings.Trim(strings.TrimSpace(line), "|")
+	cells := strings.Split(trimmed, "|")
+	for i, cell := range cells {
+		if header {
+			cells[i] = "<b>" + renderInline(strings.TrimSpace(cell), tagBold) + "</b>"
+		} else {
+			cells[i] = renderInline(strings.TrimSpace(cell), 0)
+		}
+	}
+	return strings.Join(cells, " | ")
+}
+
+// collapseBlankLines склеивает строки, оставляя не больше одной пустой строки подряд
+func collapseBlankLines(lines []string) string {
+	var kept []string
+	for _, line := range lines {
+		if line == "" && (len(kept) == 0 || kept[len(kept)-1] == "") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.TrimRight(strings.Join(kept, "\n"), "\n")
+}
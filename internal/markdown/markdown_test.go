@@ -0,0 +1,135 @@
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestToTelegramHTML эталонные преобразования для ошибок старого
+// форматирования: непарные *, -- и номера внутри текста, экранирование
+// и вложенная разметка
+func TestToTelegramHTML(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"одиночная звездочка", "2 * 3 = 6", "2 * 3 = 6"},
+		{"звездочка между словами", "a * b", "a * b"},
+		{"незакрытый жирный", "**незакрытый", "**незакрытый"},
+		{"парная звездочка", "*курсив*", "<i>курсив</i>"},
+		{"парные две звездочки", "**жирный**", "<b>жирный</b>"},
+		{"парные три звездочки", "***оба***", "<b><i>оба</i></b>"},
+		{"жирный и курсив рядом", "**жирный** и *курсив*", "<b>жирный</b> и <i>курсив</i>"},
+
+		{"двойной дефис в слове", "anti--aging", "anti--aging"},
+		{"двойной дефис между словами", "до -- после", "до -- после"},
+		{"двойной дефис в числах", "pre--post и 10--20%", "pre--post и 10--20%"},
+
+		{"пункт 1", "1. Пункт", "1️⃣ Пункт"},
+		{"пункт 11", "11. Пункт", "11. Пункт"},
+		{"11. внутри текста", "Шаг 11. Дальше", "Шаг 11. Дальше"},
+		{"1. внутри текста", "цена 1. 5", "цена 1. 5"},
+
+		{"угловые скобки и амперсанд", "a < b > c & d", "a &lt; b &gt; c &amp; d"},
+		{"теги из ответа модели", "<script>alert(1)</script>", "&lt;script&gt;alert(1)&lt;/script&gt;"},
+		{"готовая сущность", "&amp;", "&amp;amp;"},
+		{"экранирование в коде", "`a < b`", "<code>a &lt; b</code>"},
+		{"экранирование в ссылке", "[ссылка](https://example.com?a=1&b=2)", `<a href="https://example.com?a=1&amp;b=2">ссылка</a>`},
+
+		{"курсив в жирном", "**жирный *курсив* внутри**", "<b>жирный <i>курсив</i> внутри</b>"},
+		{"жирный в курсиве", "*курсив **жирный** внутри*", "<i>курсив <b>жирный</b> внутри</i>"},
+		{"код в жирном", "**`код` в жирном**", "<b><code>код</code> в жирном</b>"},
+		{"разметка в коде", "`**не жирный**`", "<code>**не жирный**</code>"},
+		{"жирный в заголовке", "## Уход **утром**", "🔹 <b>Уход утром</b>"},
+		{"snake_case", "snake_case_name", "snake_case_name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToTelegramHTML(tt.source)
+			if got != tt.want {
+				t.Errorf("ToTelegramHTML(%q) = %q, want %q", tt.source, got, tt.want)
+			}
+			if err := checkTelegramHTML(got); err != nil {
+				t.Errorf("ToTelegramHTML(%q) = %q: %v", tt.source, got, err)
+			}
+		})
+	}
+}
+
+// FuzzToTelegramHTML проверяет, что на любом входе результат принимается
+// Telegram: только разрешенные теги, все закрыты и правильно вложены,
+// а < и & вне тегов и сущностей экранированы
+func FuzzToTelegramHTML(f *testing.F) {
+	for _, seed := range []string{
+		"", "2 * 3", "**a *b* c**", "***x***", "~~s~~", "`a < b`", "anti--aging", "11. x\n1. y",
+		"# h\n- a\n  - b\n> q\n| a | b |\n|---|---|\n| c | d |\n---",
+		"```go\nx := a < b && c\n```", "[l](https://e.com/?a&b) [x](javascript:alert(1))",
+		"\\*a\\* _b_ snake_case **[l](http://e.com)**", "<b>&amp;</b>", "*a **b* c**",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, source string) {
+		got := ToTelegramHTML(source)
+		if err := checkTelegramHTML(got); err != nil {
+			t.Errorf("ToTelegramHTML(%q) = %q: %v", source, got, err)
+		}
+	})
+}
+
+var (
+	// telegramTag теги, которые Telegram принимает в parse_mode=HTML, в том
+	// виде, в котором их выводит пакет
+	telegramTag = regexp.MustCompile(`^<(/?)(b|i|u|s|code|pre|a|blockquote)((?: href="[^"<>]*")|(?: class="language-[^"<>]*"))?>`)
+	// telegramEntity сущности, которые выводит пакет
+	telegramEntity = regexp.MustCompile(`^&(?:amp|lt|gt|quot);`)
+)
+
+// checkTelegramHTML проверяет HTML по правилам Telegram
+func checkTelegramHTML(html string) error {
+	var stack []string
+	for i := 0; i < len(html); {
+		switch html[i] {
+		case '<':
+			m := telegramTag.FindStringSubmatch(html[i:])
+			if m == nil {
+				return fmt.Errorf("неразрешенный тег или неэкранированный < в позиции %d", i)
+			}
+			closing, name, attr := m[1] == "/", m[2], m[3]
+			switch {
+			case closing && attr != "":
+				return fmt.Errorf("атрибут в закрывающем теге </%s>", name)
+			case closing && (len(stack) == 0 || stack[len(stack)-1] != name):
+				return fmt.Errorf("тег </%s> закрывает незакрытый тег или не в том порядке: открыты %v", name, stack)
+			case closing:
+				stack = stack[:len(stack)-1]
+			case name == "a" && !strings.HasPrefix(attr, " href="):
+				return fmt.Errorf("ссылка без href")
+			case name == "code" && attr != "" && (len(stack) == 0 || stack[len(stack)-1] != "pre"):
+				return fmt.Errorf("язык кода указан вне <pre>")
+			case name != "a" && name != "code" && attr != "":
+				return fmt.Errorf("атрибут у тега <%s>", name)
+			default:
+				stack = append(stack, name)
+			}
+			i += len(m[0])
+		case '&':
+			m := telegramEntity.FindString(html[i:])
+			if m == "" {
+				return fmt.Errorf("неэкранированный & в позиции %d", i)
+			}
+			i += len(m)
+		case '>':
+			return fmt.Errorf("неэкранированный > в позиции %d", i)
+		default:
+			i++
+		}
+	}
+	if len(stack) > 0 {
+		return fmt.Errorf("незакрытые теги %v", stack)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// StringList список ответов на вопрос с несколькими вариантами.
+// При разборе JSON принимает и одиночную строку: так хранились ответы
+// до появления множественного выбора.
+type StringList []string
+
+// UnmarshalJSON разбирает массив строк или одиночную строку
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*l = list
+		return nil
+	}
+
+	var single string
+	if err := json.Unmarshal(data, &single); err != nil {
+		return err
+	}
+	if single == "" {
+		*l = nil
+	} else {
+		*l = StringList{single}
+	}
+	return nil
+}
+
+// Contains проверяет, есть ли значение в списке
+func (l StringList) Contains(value string) bool {
+	for _, item := range l {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// String возвращает значения через запятую
+func (l StringList) String() string {
+	return strings.Join(l, ", ")
+}
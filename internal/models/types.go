@@ -75,18 +75,29 @@ type Product struct {
 
 // ========== Структуры для пользователей ==========
 type UserState struct {
-	Step        int
-	SkinType    string
-	Age         string
-	Gender      string
-	Pregnancy   string
-	Concerns    string
-	Goal        string
-	Climate     string
-	Fitzpatrick string
-	Lifestyle   string
-	Diet        string
-	Allergies   string
+	Step        int        `json:"step"`
+	SkinType    string     `json:"skin_type"`
+	Age         string     `json:"age"`
+	Gender      string     `json:"gender"`
+	Pregnancy   string     `json:"pregnancy"`
+	Concerns    string     `json:"concerns"`
+	Goal        string     `json:"goal"`
+	Climate     string     `json:"climate"`
+	Fitzpatrick string     `json:"fitzpatrick"`
+	Lifestyle   StringList `json:"lifestyle"`
+	Diet        StringList `json:"diet"`
+	Allergies   StringList `json:"allergies"`
+
+	// Уточнения к вариантам «Другое», написанные пользователем
+	GoalOther      string `json:"goal_other,omitempty"`
+	LifestyleOther string `json:"lifestyle_other,omitempty"`
+	DietOther      string `json:"diet_other,omitempty"`
+	AllergiesOther string `json:"allergies_other,omitempty"`
+
+	// FollowUp id вопроса, для которого ждем уточнение текстом
+	FollowUp string `json:"follow_up,omitempty"`
+	// Editing анкета уже заполнена, пользователь меняет отдельный ответ
+	Editing bool `json:"editing,omitempty"`
 }
 
 // ========== Структуры для API ==========
@@ -139,35 +150,46 @@ type APIUserProduct struct {
 
 // APIUserProfile представляет профиль пользователя из API
 type APIUserProfile struct {
-	UserID      int64  `json:"user_id"`
-	SkinType    string `json:"skin_type"`
-	Age         string `json:"age"`
-	Gender      string `json:"gender"`
-	Pregnancy   string `json:"pregnancy"`
-	Concern     string `json:"concern"`
-	Goal        string `json:"goal"`
-	Climate     string `json:"climate"`
-	Fitzpatrick string `json:"fitzpatrick"`
-	Lifestyle   string `json:"lifestyle"`
-	Diet        string `json:"diet"`
-	Allergy     string `json:"allergy"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	UserID      int64      `json:"user_id"`
+	SkinType    string     `json:"skin_type"`
+	Age         string     `json:"age"`
+	Gender      string     `json:"gender"`
+	Pregnancy   string     `json:"pregnancy"`
+	Concern     string     `json:"concern"`
+	Goal        string     `json:"goal"`
+	Climate     string     `json:"climate"`
+	Fitzpatrick string     `json:"fitzpatrick"`
+	Lifestyle   StringList `json:"lifestyle"`
+	Diet        StringList `json:"diet"`
+	Allergy     StringList `json:"allergy"`
+
+	GoalOther      string `json:"goal_other,omitempty"`
+	LifestyleOther string `json:"lifestyle_other,omitempty"`
+	DietOther      string `json:"diet_other,omitempty"`
+	AllergyOther   string `json:"allergy_other,omitempty"`
+
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
 }
 
 // APIUserProfileUpdate представляет данные для обновления профиля пользователя
 type APIUserProfileUpdate struct {
-	SkinType    string `json:"skin_type"`
-	Age         string `json:"age"`
-	Gender      string `json:"gender"`
-	Pregnancy   string `json:"pregnancy"`
-	Concern     string `json:"concern"`
-	Goal        string `json:"goal"`
-	Climate     string `json:"climate"`
-	Fitzpatrick string `json:"fitzpatrick"`
-	Lifestyle   string `json:"lifestyle"`
-	Diet        string `json:"diet"`
-	Allergy     string `json:"allergy"`
+	SkinType    string     `json:"skin_type"`
+	Age         string     `json:"age"`
+	Gender      string     `json:"gender"`
+	Pregnancy   string     `json:"pregnancy"`
+	Concern     string     `json:"concern"`
+	Goal        string     `json:"goal"`
+	Climate     string     `json:"climate"`
+	Fitzpatrick string     `json:"fitzpatrick"`
+	Lifestyle   StringList `json:"lifestyle"`
+	Diet        StringList `json:"diet"`
+	Allergy     StringList `json:"allergy"`
+
+	GoalOther      string `json:"goal_other,omitempty"`
+	LifestyleOther string `json:"lifestyle_other,omitempty"`
+	DietOther      string `json:"diet_other,omitempty"`
+	AllergyOther   string `json:"allergy_other,omitempty"`
 }
 
 // APIProductCreate представляет данные для создания нового продукта
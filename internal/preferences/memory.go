@@ -0,0 +1,39 @@
+package preferences
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStore потокобезопасное хранилище настроек в памяти процесса.
+// Не переживает перезапуск; используется, когда база данных не настроена.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	languages map[int64]string
+}
+
+// NewMemoryStore создает хранилище настроек в памяти
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{languages: make(map[int64]string)}
+}
+
+// Language возвращает выбранный язык
+func (s *MemoryStore) Language(ctx context.Context, userID int64) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	lang, ok := s.languages[userID]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return lang, nil
+}
+
+// SetLanguage сохраняет выбранный язык
+func (s *MemoryStore) SetLanguage(ctx context.Context, userID int64, lang string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.languages[userID] = lang
+	return nil
+}
@@ -0,0 +1,48 @@
+package preferences
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// PostgresStore хранилище настроек в таблице user_preferences
+// (см. migrations/003_user_preferences.sql)
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore создает хранилище поверх открытого подключения
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Language возвращает выбранный язык
+func (s *PostgresStore) Language(ctx context.Context, userID int64) (string, error) {
+	var lang sql.NullString
+	err := s.db.QueryRowContext(ctx,
+		`SELECT language FROM user_preferences WHERE user_id = $1`, userID,
+	).Scan(&lang)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !lang.Valid) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("ошибка чтения языка пользователя: %v", err)
+	}
+	return lang.String, nil
+}
+
+// SetLanguage сохраняет выбранный язык
+func (s *PostgresStore) SetLanguage(ctx context.Context, userID int64, lang string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO user_preferences (user_id, language, updated_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP)
+		ON CONFLICT (user_id) DO UPDATE
+		SET language = EXCLUDED.language, updated_at = EXCLUDED.updated_at`,
+		userID, lang)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения языка пользователя: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+// Package preferences хранит настройки пользователя, выбранные в боте.
+package preferences
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound возвращается, если пользователь не менял настройку
+var ErrNotFound = errors.New("настройка пользователя не задана")
+
+// Store хранит настройки пользователей бота
+type Store interface {
+	// Language возвращает выбранный через /language язык или ErrNotFound
+	Language(ctx context.Context, userID int64) (string, error)
+	// SetLanguage сохраняет выбранный язык
+	SetLanguage(ctx context.Context, userID int64, lang string) error
+}
@@ -0,0 +1,606 @@
+package questionnaire
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"cos-ai-bot/internal/catalog"
+	"cos-ai-bot/internal/i18n"
+	"cos-ai-bot/internal/models"
+)
+
+// Ошибки обработки ответа на вопрос анкеты
+var (
+	ErrNotInProgress   = errors.New("анкета не заполняется")
+	ErrUnexpectedInput = errors.New("вопрос не принимает такой тип ответа")
+	ErrUnknownOption   = errors.New("вариант ответа не относится к текущему вопросу")
+	ErrEmptyAnswer     = errors.New("пустой ответ")
+	ErrTooFewSelected  = errors.New("выбрано слишком мало вариантов")
+	ErrTooManySelected = errors.New("выбрано слишком много вариантов")
+	ErrUnknownQuestion = errors.New("вопрос не найден")
+	ErrQuestionSkipped = errors.New("вопрос пропускается при текущих ответах")
+	ErrNoPrevious      = errors.New("это первый вопрос анкеты")
+)
+
+// Kind способ ответа на вопрос
+type Kind int
+
+const (
+	// SingleChoice выбор одного варианта кнопкой
+	SingleChoice Kind = iota
+	// MultiChoice выбор нескольких вариантов с подтверждением кнопкой «Готово»
+	MultiChoice
+	// FreeText ответ сообщением в свободной форме
+	FreeText
+)
+
+// Option вариант ответа. Тексты варианта берутся из каталогов i18n:
+// название — answer.<code> или справочник Question.Catalog,
+// текст кнопки, если он отличается от названия, — option.<code>.
+type Option struct {
+	Code string // код из справочника Question.Catalog, сохраняется в состоянии анкеты
+	Wide bool   // кнопка занимает отдельный ряд
+
+	// FollowUp для вариантов «Другое»: после выбора просим уточнить ответ
+	// текстом (followup.<code>). Уточнение сохраняется в Question.OtherField.
+	FollowUp bool
+
+	// Exclusive для MultiChoice: вариант вида «Нет аллергий» снимает
+	// остальные отметки, а выбор любого другого варианта снимает его
+	Exclusive bool
+}
+
+// Condition выполняется, если ответ на вопрос Question — один из Codes
+type Condition struct {
+	Question string
+	Codes    []string
+}
+
+// Question вопрос анкеты. Короткое название и текст вопроса берутся
+// из каталогов i18n по ключам question.<id>.title и question.<id>.text.
+type Question struct {
+	ID      string   // идентификатор вопроса
+	Emoji   string   // значок в сводке ответов
+	Kind    Kind     // способ ответа
+	Options []Option // варианты для SingleChoice и MultiChoice
+	Columns int      // кнопок в ряду; по умолчанию 2
+
+	// Catalog справочник кодов и названий вариантов ответа
+	Catalog catalog.Dictionary
+
+	// Ограничения MultiChoice по аналогии с ChecklistOptions; 0 — без ограничения
+	MinSelected int
+	MaxSelected int
+
+	// SkipIf пропускает вопрос, если выполнено любое из условий.
+	// Пропущенный вопрос получает ответ SkipAnswer.
+	SkipIf     []Condition
+	SkipAnswer string
+
+	// Field указывает на поле состояния с ответом на SingleChoice и FreeText,
+	// ListField — на поле со списком ответов MultiChoice
+	Field     func(state *models.UserState) *string
+	ListField func(state *models.UserState) *models.StringList
+
+	// OtherField указывает на поле с уточнением к варианту с FollowUp
+	OtherField func(state *models.UserState) *string
+}
+
+// Option ищет вариант ответа по коду
+func (q *Question) Option(code string) (Option, bool) {
+	for _, option := range q.Options {
+		if option.Code == code {
+			return option, true
+		}
+	}
+	return Option{}, false
+}
+
+// Title возвращает короткое название вопроса для сводки ответов
+func (q *Question) Title(loc *i18n.Localizer) string {
+	return loc.T("question." + q.ID + ".title")
+}
+
+// Text возвращает текст вопроса с пояснением
+func (q *Question) Text(loc *i18n.Localizer) string {
+	return loc.T("question." + q.ID + ".text")
+}
+
+// AnswerLabel возвращает название ответа на языке пользователя.
+// Если перевода нет, используется название из справочника,
+// а ответ в свободной форме возвращается как есть.
+func (q *Question) AnswerLabel(loc *i18n.Localizer, code string) string {
+	if label, ok := loc.Lookup("answer." + code); ok {
+		return label
+	}
+	if q.Catalog == nil {
+		return code
+	}
+	return q.Catalog.LabelOf(code)
+}
+
+// ButtonLabel возвращает текст кнопки варианта
+func (q *Question) ButtonLabel(loc *i18n.Localizer, option Option) string {
+	if label, ok := loc.Lookup("option." + option.Code); ok {
+		return label
+	}
+	return q.AnswerLabel(loc, option.Code)
+}
+
+// FollowUpText возвращает просьбу уточнить ответ для варианта «Другое»
+func (q *Question) FollowUpText(loc *i18n.Localizer, option Option) string {
+	return loc.T("followup." + option.Code)
+}
+
+// Answers возвращает ответы на вопрос из состояния
+func (q *Question) Answers(state *models.UserState) []string {
+	if q.Kind == MultiChoice {
+		return *q.ListField(state)
+	}
+	if answer := *q.Field(state); answer != "" {
+		return []string{answer}
+	}
+	return nil
+}
+
+// Selected проверяет, отмечен ли вариант в ответе на вопрос
+func (q *Question) Selected(state *models.UserState, code string) bool {
+	for _, answer := range q.Answers(state) {
+		if answer == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Display возвращает человекочитаемый ответ на вопрос из состояния
+// вместе с уточнением к варианту «Другое»
+func (q *Question) Display(loc *i18n.Localizer, state *models.UserState) string {
+	answers := q.Answers(state)
+	values := make([]string, 0, len(answers))
+	for _, answer := range answers {
+		value := q.AnswerLabel(loc, answer)
+		option, ok := q.Option(answer)
+		if other := q.Other(state); ok && option.FollowUp && other != "" {
+			value += ": " + other
+		}
+		values = append(values, value)
+	}
+	return strings.Join(values, ", ")
+}
+
+// Labels возвращает названия ответов через запятую, без уточнения к «Другое»
+func (q *Question) Labels(loc *i18n.Localizer, state *models.UserState) string {
+	answers := q.Answers(state)
+	labels := make([]string, len(answers))
+	for i, answer := range answers {
+		labels[i] = q.AnswerLabel(loc, answer)
+	}
+	return strings.Join(labels, ", ")
+}
+
+// Other возвращает уточнение к варианту «Другое»
+func (q *Question) Other(state *models.UserState) string {
+	if q.OtherField == nil {
+		return ""
+	}
+	return *q.OtherField(state)
+}
+
+// followUpOption возвращает отмеченный вариант, требующий уточнения текстом
+func (q *Question) followUpOption(state *models.UserState) (Option, bool) {
+	for _, option := range q.Options {
+		if option.FollowUp && q.Selected(state, option.Code) {
+			return option, true
+		}
+	}
+	return Option{}, false
+}
+
+// pruneOther удаляет уточнение, если вариант «Другое» больше не выбран
+func (q *Question) pruneOther(state *models.UserState) {
+	if _, ok := q.followUpOption(state); !ok && q.OtherField != nil {
+		*q.OtherField(state) = ""
+	}
+}
+
+// setAnswer записывает ответ; для MultiChoice пустой код очищает список
+func (q *Question) setAnswer(state *models.UserState, code string) {
+	if q.Kind != MultiChoice {
+		*q.Field(state) = code
+		return
+	}
+	if code == "" {
+		*q.ListField(state) = nil
+	} else {
+		*q.ListField(state) = models.StringList{code}
+	}
+}
+
+// Form анкета: упорядоченный список вопросов и правила переходов между ними.
+// Номер шага в UserState.Step — позиция вопроса, начиная с 1;
+// шаг Len()+1 означает, что анкета заполнена.
+type Form struct {
+	questions []Question
+	index     map[string]int
+}
+
+// NewForm создает анкету из вопросов.
+// Паникует при повторных id или ссылке условия на неизвестный или более поздний вопрос —
+// это ошибка в описании анкеты.
+func NewForm(questions ...Question) *Form {
+	f := &Form{questions: questions, index: make(map[string]int, len(questions))}
+	defaults := i18n.For(i18n.Default)
+
+	for i, q := range questions {
+		if _, exists := f.index[q.ID]; exists {
+			panic(fmt.Sprintf("questionnaire: повторный id вопроса %q", q.ID))
+		}
+		if (q.Kind == MultiChoice && q.ListField == nil) || (q.Kind != MultiChoice && q.Field == nil) {
+			panic(fmt.Sprintf("questionnaire: у вопроса %q не задано поле ответа", q.ID))
+		}
+		if _, ok := defaults.Lookup("question." + q.ID + ".title"); !ok {
+			panic(fmt.Sprintf("questionnaire: нет текстов вопроса %q в каталоге сообщений", q.ID))
+		}
+		if len(q.Options) > 0 && q.Catalog == nil {
+			panic(fmt.Sprintf("questionnaire: у вопроса %q не задан справочник вариантов", q.ID))
+		}
+		for _, option := range q.Options {
+			if !q.Catalog.Known(option.Code) {
+				panic(fmt.Sprintf("questionnaire: вариант %q вопроса %q не найден в справочнике %s", option.Code, q.ID, q.Catalog.Name()))
+			}
+			if option.FollowUp && q.OtherField == nil {
+				panic(fmt.Sprintf("questionnaire: у вопроса %q не задано поле уточнения для %q", q.ID, option.Code))
+			}
+			if _, ok := defaults.Lookup("followup." + option.Code); option.FollowUp && !ok {
+				panic(fmt.Sprintf("questionnaire: нет просьбы уточнить ответ %q в каталоге сообщений", option.Code))
+			}
+		}
+		for _, cond := range q.SkipIf {
+			if _, ok := f.index[cond.Question]; !ok {
+				panic(fmt.Sprintf("questionnaire: условие вопроса %q ссылается на %q, который не задан раньше", q.ID, cond.Question))
+			}
+		}
+		f.index[q.ID] = i
+	}
+	return f
+}
+
+// Len возвращает число вопросов
+func (f *Form) Len() int {
+	return len(f.questions)
+}
+
+// Questions возвращает вопросы в порядке анкеты
+func (f *Form) Questions() []Question {
+	return f.questions
+}
+
+// Step возвращает вопрос для шага
+func (f *Form) Step(step int) (*Question, bool) {
+	if step < 1 || step > len(f.questions) {
+		return nil, false
+	}
+	return &f.questions[step-1], true
+}
+
+// Question возвращает вопрос по id
+func (f *Form) Question(id string) (*Question, bool) {
+	i, ok := f.index[id]
+	if !ok {
+		return nil, false
+	}
+	return &f.questions[i], true
+}
+
+// Start сбрасывает состояние на первый вопрос
+func (f *Form) Start(state *models.UserState) {
+	*state = models.UserState{}
+	f.advance(state)
+}
+
+// Edit переводит заполненную анкету к одному вопросу.
+// После ответа на него задаются только вопросы, зависящие от измененного ответа,
+// а затем анкета снова считается заполненной.
+func (f *Form) Edit(state *models.UserState, id string) error {
+	i, ok := f.index[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownQuestion, id)
+	}
+	if f.Skipped(&f.questions[i], state) {
+		return fmt.Errorf("%w: %s", ErrQuestionSkipped, id)
+	}
+
+	state.Step = i + 1
+	state.FollowUp = ""
+	state.Editing = true
+	return nil
+}
+
+// Done сообщает, что на все вопросы получены ответы
+func (f *Form) Done(state *models.UserState) bool {
+	return state.Step > len(f.questions)
+}
+
+// Pending возвращает вопрос и вариант, для которых ждем уточнение текстом
+func (f *Form) Pending(state *models.UserState) (*Question, Option, bool) {
+	if state.FollowUp == "" {
+		return nil, Option{}, false
+	}
+	q, ok := f.Question(state.FollowUp)
+	if !ok {
+		return nil, Option{}, false
+	}
+	option, ok := q.followUpOption(state)
+	if !ok {
+		return nil, Option{}, false
+	}
+	return q, option, true
+}
+
+// Answer принимает выбранный вариант для текущего вопроса и переходит к следующему.
+// Для варианта с FollowUp переход откладывается до уточнения текстом.
+func (f *Form) Answer(state *models.UserState, code string) error {
+	q, ok := f.Step(state.Step)
+	if !ok {
+		return ErrNotInProgress
+	}
+	if q.Kind != SingleChoice {
+		return fmt.Errorf("%w: вопрос %s", ErrUnexpectedInput, q.ID)
+	}
+	option, ok := q.Option(code)
+	if !ok {
+		return fmt.Errorf("%w: %s (вопрос %s)", ErrUnknownOption, code, q.ID)
+	}
+
+	*q.Field(state) = code
+	state.FollowUp = ""
+	if option.FollowUp {
+		state.FollowUp = q.ID
+		return nil
+	}
+	q.pruneOther(state)
+	f.advance(state)
+	return nil
+}
+
+// Toggle отмечает или снимает вариант текущего вопроса с несколькими ответами.
+// Переход к следующему вопросу выполняет Submit.
+func (f *Form) Toggle(state *models.UserState, code string) error {
+	q, ok := f.Step(state.Step)
+	if !ok {
+		return ErrNotInProgress
+	}
+	if q.Kind != MultiChoice {
+		return fmt.Errorf("%w: вопрос %s", ErrUnexpectedInput, q.ID)
+	}
+	option, ok := q.Option(code)
+	if !ok {
+		return fmt.Errorf("%w: %s (вопрос %s)", ErrUnknownOption, code, q.ID)
+	}
+
+	state.FollowUp = ""
+	defer q.pruneOther(state)
+
+	list := q.ListField(state)
+	if list.Contains(code) {
+		*list = removeAnswer(*list, code)
+		return nil
+	}
+
+	if option.Exclusive {
+		*list = models.StringList{code}
+		return nil
+	}
+	// Обычный вариант несовместим с исключающими
+	for _, other := range q.Options {
+		if other.Exclusive {
+			*list = removeAnswer(*list, other.Code)
+		}
+	}
+	if q.MaxSelected > 0 && len(*list) >= q.MaxSelected {
+		return fmt.Errorf("%w: не больше %d (вопрос %s)", ErrTooManySelected, q.MaxSelected, q.ID)
+	}
+	*list = append(*list, code)
+	return nil
+}
+
+// Submit подтверждает выбор на текущем вопросе с несколькими ответами
+// и переходит к следующему
+func (f *Form) Submit(state *models.UserState) error {
+	q, ok := f.Step(state.Step)
+	if !ok {
+		return ErrNotInProgress
+	}
+	if q.Kind != MultiChoice {
+		return fmt.Errorf("%w: вопрос %s", ErrUnexpectedInput, q.ID)
+	}
+	if len(*q.ListField(state)) < q.MinSelected {
+		return fmt.Errorf("%w: нужно не меньше %d (вопрос %s)", ErrTooFewSelected, q.MinSelected, q.ID)
+	}
+
+	if _, ok := q.followUpOption(state); ok && q.Other(state) == "" {
+		state.FollowUp = q.ID
+		return nil
+	}
+	f.advance(state)
+	return nil
+}
+
+// AnswerText принимает текстовый ответ для текущего вопроса или уточнение
+// к варианту «Другое» и переходит к следующему вопросу
+func (f *Form) AnswerText(state *models.UserState, text string) error {
+	if q, _, ok := f.Pending(state); ok {
+		text, err := ValidateText(text)
+		if err != nil {
+			return err
+		}
+		*q.OtherField(state) = text
+		state.FollowUp = ""
+		f.advance(state)
+		return nil
+	}
+
+	q, ok := f.Step(state.Step)
+	if !ok {
+		return ErrNotInProgress
+	}
+	if q.Kind != FreeText {
+		return fmt.Errorf("%w: вопрос %s", ErrUnexpectedInput, q.ID)
+	}
+	text, err := ValidateText(text)
+	if err != nil {
+		return err
+	}
+
+	*q.Field(state) = text
+	f.advance(state)
+	return nil
+}
+
+// Skip оставляет текущий вопрос без ответа и переходит к следующему.
+// Если ждем уточнение к варианту «Другое», пропускается только уточнение.
+func (f *Form) Skip(state *models.UserState) error {
+	q, ok := f.Step(state.Step)
+	if !ok {
+		return ErrNotInProgress
+	}
+
+	if state.FollowUp != "" {
+		state.FollowUp = ""
+		f.advance(state)
+		return nil
+	}
+
+	q.setAnswer(state, "")
+	q.pruneOther(state)
+	f.advance(state)
+	return nil
+}
+
+// Back возвращает к предыдущему заданному вопросу, минуя пропущенные по условиям.
+// Если ждем уточнение к варианту «Другое», возвращает к выбору варианта.
+func (f *Form) Back(state *models.UserState) error {
+	if _, ok := f.Step(state.Step); !ok {
+		return ErrNotInProgress
+	}
+
+	if state.FollowUp != "" {
+		state.FollowUp = ""
+		return nil
+	}
+
+	previous, ok := f.previous(state)
+	if !ok {
+		return ErrNoPrevious
+	}
+	state.Step = previous
+	return nil
+}
+
+// HasPrevious сообщает, можно ли вернуться к предыдущему вопросу
+func (f *Form) HasPrevious(state *models.UserState) bool {
+	if state.FollowUp != "" {
+		return true
+	}
+	_, ok := f.previous(state)
+	return ok
+}
+
+// previous ищет ближайший предыдущий шаг, который не пропускается при текущих ответах
+func (f *Form) previous(state *models.UserState) (int, bool) {
+	for step := state.Step - 1; step >= 1; step-- {
+		if !f.Skipped(&f.questions[step-1], state) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// Progress возвращает номер текущего вопроса и общее число вопросов
+// без пропускаемых при текущих ответах
+func (f *Form) Progress(state *models.UserState) (current, total int) {
+	for i := range f.questions {
+		if f.Skipped(&f.questions[i], state) {
+			continue
+		}
+		total++
+		if i+1 <= state.Step {
+			current = total
+		}
+	}
+	return current, total
+}
+
+// advance переходит к следующему вопросу, пропуская те, чьи условия выполнены
+func (f *Form) advance(state *models.UserState) {
+	if state.Editing {
+		f.advanceEdit(state)
+		return
+	}
+
+	state.Step++
+	for {
+		q, ok := f.Step(state.Step)
+		if !ok || !f.Skipped(q, state) {
+			return
+		}
+		q.setAnswer(state, q.SkipAnswer)
+		state.Step++
+	}
+}
+
+// advanceEdit после изменения ответа пересчитывает зависящие от него вопросы:
+// пропускаемые получают SkipAnswer, остальные задаются заново
+func (f *Form) advanceEdit(state *models.UserState) {
+	edited, _ := f.Step(state.Step)
+
+	for step := state.Step + 1; step <= len(f.questions); step++ {
+		q := &f.questions[step-1]
+		if !dependsOn(q, edited.ID) {
+			continue
+		}
+		if f.Skipped(q, state) {
+			q.setAnswer(state, q.SkipAnswer)
+			continue
+		}
+		state.Step = step
+		return
+	}
+	state.Step = len(f.questions) + 1
+}
+
+// dependsOn проверяет, ссылаются ли условия вопроса на вопрос id
+func dependsOn(q *Question, id string) bool {
+	for _, cond := range q.SkipIf {
+		if cond.Question == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Skipped проверяет условия пропуска вопроса
+func (f *Form) Skipped(q *Question, state *models.UserState) bool {
+	for _, cond := range q.SkipIf {
+		dependency, _ := f.Question(cond.Question)
+		for _, code := range cond.Codes {
+			if dependency.Selected(state, code) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// removeAnswer возвращает список без значения code
+func removeAnswer(list models.StringList, code string) models.StringList {
+	result := list[:0:0]
+	for _, item := range list {
+		if item != code {
+			result = append(result, item)
+		}
+	}
+	return result
+}
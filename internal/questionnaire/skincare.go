@@ -0,0 +1,185 @@
+package questionnaire
+
+import (
+	"cos-ai-bot/internal/catalog"
+	"cos-ai-bot/internal/models"
+)
+
+// Skincare анкета для подбора ухода за кожей.
+// Тексты вопросов и вариантов — в каталогах пакета i18n.
+var Skincare = NewForm(
+	Question{
+		ID:      "skin_type",
+		Emoji:   "👤",
+		Kind:    SingleChoice,
+		Catalog: catalog.SkinTypes,
+		Options: []Option{
+			{Code: string(catalog.SkinDry)},
+			{Code: string(catalog.SkinOily)},
+			{Code: string(catalog.SkinNormal)},
+			{Code: string(catalog.SkinSensitive)},
+			{Code: string(catalog.SkinCombined), Wide: true},
+			{Code: string(catalog.SkinUnknown), Wide: true},
+		},
+		Field: func(s *models.UserState) *string { return &s.SkinType },
+	},
+	Question{
+		ID:      "age",
+		Emoji:   "📅",
+		Kind:    SingleChoice,
+		Catalog: catalog.AgeGroups,
+		Options: []Option{
+			{Code: string(catalog.AgeUnder18)},
+			{Code: string(catalog.Age18To24)},
+			{Code: string(catalog.Age25To34)},
+			{Code: string(catalog.Age35To44)},
+			{Code: string(catalog.Age45Plus)},
+			{Code: string(catalog.AgeIgnore)},
+		},
+		Field: func(s *models.UserState) *string { return &s.Age },
+	},
+	Question{
+		ID:      "gender",
+		Emoji:   "🚻",
+		Kind:    SingleChoice,
+		Catalog: catalog.Genders,
+		Options: []Option{
+			{Code: string(catalog.GenderMale)},
+			{Code: string(catalog.GenderFemale)},
+			{Code: string(catalog.GenderOther)},
+			{Code: string(catalog.GenderIgnore)},
+		},
+		Field: func(s *models.UserState) *string { return &s.Gender },
+	},
+	Question{
+		ID:      "pregnancy",
+		Emoji:   "🤱",
+		Kind:    SingleChoice,
+		Catalog: catalog.PregnancyStatuses,
+		Options: []Option{
+			{Code: string(catalog.PregnancyPregnant)},
+			{Code: string(catalog.PregnancyLactation)},
+			{Code: string(catalog.PregnancyBoth)},
+			{Code: string(catalog.PregnancyNone)},
+			{Code: string(catalog.PregnancyIgnore)},
+		},
+		SkipIf:     []Condition{{Question: "gender", Codes: []string{string(catalog.GenderMale)}}},
+		SkipAnswer: string(catalog.PregnancyNone),
+		Field:      func(s *models.UserState) *string { return &s.Pregnancy },
+	},
+	Question{
+		ID:    "concerns",
+		Emoji: "💭",
+		Kind:  FreeText,
+		Field: func(s *models.UserState) *string { return &s.Concerns },
+	},
+	Question{
+		ID:      "goal",
+		Emoji:   "🎯",
+		Kind:    SingleChoice,
+		Columns: 1,
+		Catalog: catalog.Goals,
+		Options: []Option{
+			{Code: string(catalog.GoalHydration)},
+			{Code: string(catalog.GoalTone)},
+			{Code: string(catalog.GoalAntiage)},
+			{Code: string(catalog.GoalTexture)},
+			{Code: string(catalog.GoalRefresh)},
+			{Code: string(catalog.GoalMinimalism)},
+			{Code: string(catalog.GoalOther), FollowUp: true},
+		},
+		Field:      func(s *models.UserState) *string { return &s.Goal },
+		OtherField: func(s *models.UserState) *string { return &s.GoalOther },
+	},
+	Question{
+		ID:      "climate",
+		Emoji:   "🌍",
+		Kind:    SingleChoice,
+		Catalog: catalog.Climates,
+		Options: []Option{
+			{Code: string(catalog.ClimateDry)},
+			{Code: string(catalog.ClimateHumid)},
+			{Code: string(catalog.ClimateHot)},
+			{Code: string(catalog.ClimateCold)},
+			{Code: string(catalog.ClimateTemperate)},
+			{Code: string(catalog.ClimatePolluted)},
+			{Code: string(catalog.ClimateMultiple)},
+			{Code: string(catalog.ClimateUnknown)},
+		},
+		Field: func(s *models.UserState) *string { return &s.Climate },
+	},
+	Question{
+		ID:      "fitzpatrick",
+		Emoji:   "☀️",
+		Kind:    SingleChoice,
+		Columns: 1,
+		Catalog: catalog.FitzpatrickTypes,
+		Options: []Option{
+			{Code: string(catalog.Fitzpatrick1)},
+			{Code: string(catalog.Fitzpatrick2)},
+			{Code: string(catalog.Fitzpatrick3)},
+			{Code: string(catalog.Fitzpatrick4)},
+			{Code: string(catalog.Fitzpatrick5)},
+			{Code: string(catalog.Fitzpatrick6)},
+			{Code: string(catalog.FitzpatrickUnknown)},
+		},
+		Field: func(s *models.UserState) *string { return &s.Fitzpatrick },
+	},
+	Question{
+		ID:          "lifestyle",
+		Emoji:       "🏃",
+		Kind:        MultiChoice,
+		MinSelected: 1,
+		Catalog:     catalog.Lifestyles,
+		Options: []Option{
+			{Code: string(catalog.LifestyleStress)},
+			{Code: string(catalog.LifestyleSleep)},
+			{Code: string(catalog.LifestyleScreen)},
+			{Code: string(catalog.LifestyleSweat)},
+			{Code: string(catalog.LifestyleComputer)},
+			{Code: string(catalog.LifestyleActive)},
+			{Code: string(catalog.LifestyleOutdoor)},
+			{Code: string(catalog.LifestylePassive)},
+			{Code: string(catalog.LifestyleOther), Wide: true, FollowUp: true},
+		},
+		ListField:  func(s *models.UserState) *models.StringList { return &s.Lifestyle },
+		OtherField: func(s *models.UserState) *string { return &s.LifestyleOther },
+	},
+	Question{
+		ID:          "diet",
+		Emoji:       "🥗",
+		Kind:        MultiChoice,
+		MinSelected: 1,
+		Catalog:     catalog.Diets,
+		Options: []Option{
+			{Code: string(catalog.DietVegan)},
+			{Code: string(catalog.DietVegetarian)},
+			{Code: string(catalog.DietHalal)},
+			{Code: string(catalog.DietKeto)},
+			{Code: string(catalog.DietGlutenFree)},
+			{Code: string(catalog.DietNoAlcohol)},
+			{Code: string(catalog.DietNoAnimal)},
+			{Code: string(catalog.DietNone), Exclusive: true},
+			{Code: string(catalog.DietOther), Wide: true, FollowUp: true},
+		},
+		ListField:  func(s *models.UserState) *models.StringList { return &s.Diet },
+		OtherField: func(s *models.UserState) *string { return &s.DietOther },
+	},
+	Question{
+		ID:          "allergies",
+		Emoji:       "⚠️",
+		Kind:        MultiChoice,
+		MinSelected: 1,
+		Catalog:     catalog.Allergies,
+		Options: []Option{
+			{Code: string(catalog.AllergyNone), Wide: true, Exclusive: true},
+			{Code: string(catalog.AllergyNickel)},
+			{Code: string(catalog.AllergyLanolin)},
+			{Code: string(catalog.AllergyFragrance)},
+			{Code: string(catalog.AllergyPreservatives)},
+			{Code: string(catalog.AllergyOther), Wide: true, FollowUp: true},
+		},
+		ListField:  func(s *models.UserState) *models.StringList { return &s.Allergies },
+		OtherField: func(s *models.UserState) *string { return &s.AllergiesOther },
+	},
+)
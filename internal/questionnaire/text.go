@@ -0,0 +1,61 @@
+package questionnaire
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Ограничения длины ответа в свободной форме
+const (
+	MinTextLength = 2
+	MaxTextLength = 500
+)
+
+// Ошибки проверки ответа в свободной форме
+var (
+	ErrTextTooShort = errors.New("ответ слишком короткий")
+	ErrTextTooLong  = errors.New("ответ слишком длинный")
+	ErrOnlyLink     = errors.New("ответ состоит только из ссылки")
+	ErrOnlyEmoji    = errors.New("ответ состоит только из эмодзи")
+)
+
+// linkPattern ссылки с протоколом, с www и голые домены вида site.com/path
+var linkPattern = regexp.MustCompile(`(?i)(?:https?://\S+|www\.\S+|[a-z0-9-]+(?:\.[a-z0-9-]+)*\.[a-z]{2,}(?:/\S*)?)`)
+
+// ValidateText проверяет ответ в свободной форме и возвращает его без
+// лишних пробелов по краям
+func ValidateText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", ErrEmptyAnswer
+	}
+
+	length := utf8.RuneCountInString(text)
+	if length > MaxTextLength {
+		return "", ErrTextTooLong
+	}
+
+	if !hasWords(text) {
+		return "", ErrOnlyEmoji
+	}
+	if !hasWords(linkPattern.ReplaceAllString(text, "")) {
+		return "", ErrOnlyLink
+	}
+	if length < MinTextLength {
+		return "", ErrTextTooShort
+	}
+	return text, nil
+}
+
+// hasWords проверяет, есть ли в тексте буквы или цифры
+func hasWords(text string) bool {
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return false
+}
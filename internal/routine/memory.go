@@ -0,0 +1,56 @@
+package routine
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore потокобезопасное хранилище ухода в памяти процесса.
+// Не переживает перезапуск; используется, когда база данных не настроена.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	routines map[int64]UserRoutine
+}
+
+// NewMemoryStore создает хранилище ухода в памяти
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{routines: make(map[int64]UserRoutine)}
+}
+
+// Get возвращает уход пользователя
+func (s *MemoryStore) Get(ctx context.Context, userID int64) (*UserRoutine, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	routine, ok := s.routines[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	// Копии шагов, чтобы изменения вызывающего не попали в хранилище без Save
+	routine.Morning = append([]Entry(nil), routine.Morning...)
+	routine.Evening = append([]Entry(nil), routine.Evening...)
+	return &routine, nil
+}
+
+// Save сохраняет уход
+func (s *MemoryStore) Save(ctx context.Context, routine *UserRoutine) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	routine.UpdatedAt = time.Now()
+	saved := *routine
+	saved.Morning = append([]Entry(nil), routine.Morning...)
+	saved.Evening = append([]Entry(nil), routine.Evening...)
+	s.routines[routine.UserID] = saved
+	return nil
+}
+
+// Delete удаляет уход пользователя
+func (s *MemoryStore) Delete(ctx context.Context, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.routines, userID)
+	return nil
+}
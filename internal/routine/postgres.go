@@ -0,0 +1,77 @@
+package routine
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// PostgresStore хранилище ухода в таблице user_routines
+// (см. migrations/007_user_routines.sql)
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore создает хранилище поверх открытого подключения
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Get возвращает уход пользователя
+func (s *PostgresStore) Get(ctx context.Context, userID int64) (*UserRoutine, error) {
+	routine := &UserRoutine{UserID: userID}
+	var morning, evening []byte
+	err := s.db.QueryRowContext(ctx,
+		`SELECT name, morning, evening, updated_at FROM user_routines WHERE user_id = $1`, userID,
+	).Scan(&routine.Name, &morning, &evening, &routine.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения ухода пользователя: %v", err)
+	}
+	if err := json.Unmarshal(morning, &routine.Morning); err != nil {
+		return nil, fmt.Errorf("ошибка разбора утреннего ухода: %v", err)
+	}
+	if err := json.Unmarshal(evening, &routine.Evening); err != nil {
+		return nil, fmt.Errorf("ошибка разбора вечернего ухода: %v", err)
+	}
+	return routine, nil
+}
+
+// Save сохраняет уход
+func (s *PostgresStore) Save(ctx context.Context, routine *UserRoutine) error {
+	// Пустой уход хранится как [], а не null
+	morning, err := json.Marshal(append([]Entry{}, routine.Morning...))
+	if err != nil {
+		return fmt.Errorf("ошибка маршалинга утреннего ухода: %v", err)
+	}
+	evening, err := json.Marshal(append([]Entry{}, routine.Evening...))
+	if err != nil {
+		return fmt.Errorf("ошибка маршалинга вечернего ухода: %v", err)
+	}
+
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO user_routines (user_id, name, morning, evening, updated_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+		ON CONFLICT (user_id) DO UPDATE
+		SET name = EXCLUDED.name, morning = EXCLUDED.morning,
+		    evening = EXCLUDED.evening, updated_at = EXCLUDED.updated_at
+		RETURNING updated_at`,
+		routine.UserID, routine.Name, morning, evening,
+	).Scan(&routine.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения ухода пользователя: %v", err)
+	}
+	return nil
+}
+
+// Delete удаляет уход пользователя
+func (s *PostgresStore) Delete(ctx context.Context, userID int64) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM user_routines WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("ошибка удаления ухода пользователя: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package routine
+
+import (
+	"log"
+	"strings"
+	"text/template"
+
+	"cos-ai-bot/internal/i18n"
+)
+
+// planTemplate шаблон плана в Markdown; подписи берутся из переводов routine.*,
+// текст модели экранируется, чтобы не ломать разметку
+var planTemplate = template.Must(template.New("routine").Funcs(templateFuncs(nil)).Parse(`
+{{- with .Routine.Summary}}{{md .}}
+
+{{end}}
+{{- if .Routine.Morning}}## {{t "routine.morning"}}
+{{range $i, $step := .Routine.Morning}}{{template "step" (step $i $step)}}
+{{end}}
+{{end}}
+{{- if .Routine.Evening}}## {{t "routine.evening"}}
+{{range $i, $step := .Routine.Evening}}{{template "step" (step $i $step)}}
+{{end}}
+{{end}}
+{{- if .Routine.Warnings}}## {{t "routine.warnings"}}
+{{range .Routine.Warnings}}- {{md .}}
+{{end}}
+{{end}}
+{{- if .Routine.Missing}}## {{t "routine.missing"}}
+{{range .Routine.Missing}}- **{{category .Category}}**{{with .Reason}} — {{md .}}{{end}}
+{{end}}
+{{end}}
+{{- if .Routine.Tips}}## {{t "routine.tips"}}
+{{range .Routine.Tips}}- {{md .}}
+{{end}}
+{{end}}
+
+{{- define "step"}}{{.Number}}. **{{md .Step.Product}}**
+{{- with category .Step.Category}} ({{.}}{{with frequency $.Step.Frequency}}, {{.}}{{end}}){{end}}
+{{- with .Step.Purpose}} — {{md .}}{{end}}{{end}}`))
+
+// markdownEscaper экранирует символы, которые Markdown принял бы за разметку
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `~`, `\~`, `[`, `\[`, `]`, `\]`, `|`, `\|`,
+)
+
+// numberedStep шаг с номером для шаблона
+type numberedStep struct {
+	Number int
+	Step   Step
+}
+
+// Render показывает план в Markdown на языке loc. Подходит и для
+// незаконченного плана из ParsePartial: пустые поля пропускаются.
+func Render(loc *i18n.Localizer, routine *Routine) string {
+	var b strings.Builder
+	tmpl := template.Must(planTemplate.Clone()).Funcs(templateFuncs(loc))
+	if err := tmpl.Execute(&b, struct{ Routine *Routine }{routine}); err != nil {
+		log.Printf("Ошибка отображения плана ухода: %v", err)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// templateFuncs функции шаблона для языка loc
+func templateFuncs(loc *i18n.Localizer) template.FuncMap {
+	return template.FuncMap{
+		"t":  func(key string) string { return loc.T(key) },
+		"md": func(text string) string { return markdownEscaper.Replace(strings.TrimSpace(text)) },
+		"category": func(category Category) string {
+			if !category.Known() {
+				return ""
+			}
+			return loc.T("routine.category." + string(category))
+		},
+		// Частота «каждый день» подразумевается и не выводится
+		"frequency": func(frequency Frequency) string {
+			if !frequency.Known() || frequency == FrequencyDaily {
+				return ""
+			}
+			return loc.T("routine.frequency." + string(frequency))
+		},
+		"step": func(i int, step Step) numberedStep {
+			return numberedStep{Number: i + 1, Step: step}
+		},
+	}
+}
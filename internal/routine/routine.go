@@ -0,0 +1,306 @@
+// Package routine описывает план ухода — шаги утром и вечером с продуктами,
+// назначением и частотой — в виде данных: JSON-схема для ответа модели,
+// разбор и проверка ответа и отображение плана по нашему шаблону, — а также
+// уход, который пользователь составил сам из продуктов своей коллекции.
+package routine
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrInvalid ответ модели не соответствует схеме плана
+var ErrInvalid = errors.New("ответ модели не соответствует схеме плана ухода")
+
+// Category этап ухода
+type Category string
+
+// Этапы ухода
+const (
+	CategoryCleanser    Category = "cleanser"
+	CategoryToner       Category = "toner"
+	CategoryExfoliant   Category = "exfoliant"
+	CategorySerum       Category = "serum"
+	CategoryTreatment   Category = "treatment" // точечные и лечебные средства
+	CategoryEyeCare     Category = "eye_care"
+	CategoryMoisturizer Category = "moisturizer"
+	CategoryOil         Category = "oil"
+	CategorySPF         Category = "spf"
+	CategoryMask        Category = "mask"
+	CategoryOther       Category = "other"
+)
+
+// Categories все этапы ухода в порядке нанесения
+var Categories = []Category{
+	CategoryCleanser, CategoryToner, CategoryExfoliant, CategorySerum, CategoryTreatment,
+	CategoryEyeCare, CategoryMoisturizer, CategoryOil, CategorySPF, CategoryMask, CategoryOther,
+}
+
+// Known проверяет, что этап есть в списке
+func (c Category) Known() bool {
+	for _, category := range Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Frequency как часто выполняется шаг
+type Frequency string
+
+// Частота шагов
+const (
+	FrequencyDaily         Frequency = "daily"
+	FrequencyEveryOtherDay Frequency = "every_other_day"
+	FrequencyTwiceWeekly   Frequency = "twice_weekly"
+	FrequencyWeekly        Frequency = "weekly"
+	FrequencyAsNeeded      Frequency = "as_needed"
+)
+
+// Frequencies все варианты частоты, от частых к редким
+var Frequencies = []Frequency{
+	FrequencyDaily, FrequencyEveryOtherDay, FrequencyTwiceWeekly, FrequencyWeekly, FrequencyAsNeeded,
+}
+
+// Known проверяет, что частота есть в списке
+func (f Frequency) Known() bool {
+	for _, frequency := range Frequencies {
+		if f == frequency {
+			return true
+		}
+	}
+	return false
+}
+
+// Step шаг ухода
+type Step struct {
+	Category  Category  `json:"category"`
+	Product   string    `json:"product"`    // название продукта или описание средства, которого нет в коллекции
+	ProductID *int      `json:"product_id"` // продукт из коллекции пользователя; nil — средство не из коллекции
+	Purpose   string    `json:"purpose"`
+	Frequency Frequency `json:"frequency"`
+}
+
+// Missing этап, которого не хватает в уходе
+type Missing struct {
+	Category Category `json:"category"`
+	Reason   string   `json:"reason"`
+}
+
+// Routine план ухода из ответа модели
+type Routine struct {
+	Summary  string    `json:"summary"`
+	Morning  []Step    `json:"morning"`
+	Evening  []Step    `json:"evening"`
+	Warnings []string  `json:"warnings"`
+	Missing  []Missing `json:"missing"`
+	Tips     []string  `json:"tips"` // общие советы: образ жизни, питание, сезонность
+}
+
+// SchemaName название схемы в запросе к модели
+const SchemaName = "skincare_routine"
+
+// schema JSON-схема плана в формате strict-режима OpenAI: все свойства
+// обязательны, лишние запрещены, необязательный product_id допускает null
+var schema = func() json.RawMessage {
+	enum := func(values ...string) map[string]any {
+		return map[string]any{"type": "string", "enum": values}
+	}
+	var categories, frequencies []string
+	for _, category := range Categories {
+		categories = append(categories, string(category))
+	}
+	for _, frequency := range Frequencies {
+		frequencies = append(frequencies, string(frequency))
+	}
+	object := func(properties map[string]any) map[string]any {
+		required := make([]string, 0, len(properties))
+		for name := range properties {
+			required = append(required, name)
+		}
+		// Порядок должен быть постоянным: схема входит в ключ кэша ответов
+		sort.Strings(required)
+		return map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	}
+	array := func(items any) map[string]any {
+		return map[string]any{"type": "array", "items": items}
+	}
+	text := map[string]any{"type": "string"}
+
+	step := object(map[string]any{
+		"category":   enum(categories...),
+		"product":    text,
+		"product_id": map[string]any{"type": []string{"integer", "null"}},
+		"purpose":    text,
+		"frequency":  enum(frequencies...),
+	})
+	data, err := json.Marshal(object(map[string]any{
+		"summary":  text,
+		"morning":  array(step),
+		"evening":  array(step),
+		"warnings": array(text),
+		"missing": array(object(map[string]any{
+			"category": enum(categories...),
+			"reason":   text,
+		})),
+		"tips": array(text),
+	}))
+	if err != nil {
+		panic(err)
+	}
+	return data
+}()
+
+// Schema возвращает JSON-схему плана для response_format
+func Schema() json.RawMessage {
+	return schema
+}
+
+// Parse разбирает ответ модели и проверяет его по схеме.
+// Ошибка проверки оборачивает ErrInvalid и описывает первое нарушение.
+func Parse(content string) (*Routine, error) {
+	decoder := json.NewDecoder(strings.NewReader(stripFence(content)))
+	decoder.DisallowUnknownFields()
+
+	var routine Routine
+	if err := decoder.Decode(&routine); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("%w: после JSON-объекта есть лишний текст", ErrInvalid)
+	}
+	if err := routine.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return &routine, nil
+}
+
+// validate проверяет значения, которые схема не ограничивает или модель могла нарушить
+func (r *Routine) validate() error {
+	if len(r.Morning)+len(r.Evening)+len(r.Tips) == 0 {
+		return errors.New("нет ни шагов ухода, ни советов")
+	}
+	if err := validateSteps("morning", r.Morning); err != nil {
+		return err
+	}
+	if err := validateSteps("evening", r.Evening); err != nil {
+		return err
+	}
+	for i, missing := range r.Missing {
+		switch {
+		case !missing.Category.Known():
+			return fmt.Errorf("missing[%d]: неизвестный этап %q", i, missing.Category)
+		case strings.TrimSpace(missing.Reason) == "":
+			return fmt.Errorf("missing[%d]: не указана причина", i)
+		}
+	}
+	return nil
+}
+
+// validateSteps проверяет шаги утреннего или вечернего ухода
+func validateSteps(name string, steps []Step) error {
+	for i, step := range steps {
+		switch {
+		case !step.Category.Known():
+			return fmt.Errorf("%s[%d]: неизвестный этап %q", name, i, step.Category)
+		case !step.Frequency.Known():
+			return fmt.Errorf("%s[%d]: неизвестная частота %q", name, i, step.Frequency)
+		case strings.TrimSpace(step.Product) == "":
+			return fmt.Errorf("%s[%d]: не указан продукт", name, i)
+		case strings.TrimSpace(step.Purpose) == "":
+			return fmt.Errorf("%s[%d]: не указано назначение", name, i)
+		}
+	}
+	return nil
+}
+
+// ParsePartial разбирает незаконченный ответ, который модель еще генерирует:
+// открытые строки, массивы и объекты закрываются, так что оборванный текст
+// показывается как есть. Значения не проверяются. Если фрагмент пока
+// нельзя разобрать (например, оборван на имени поля), возвращает false.
+func ParsePartial(content string) (*Routine, bool) {
+	closed, ok := closeJSON(stripFence(content))
+	if !ok {
+		return nil, false
+	}
+	var routine Routine
+	if err := json.Unmarshal([]byte(closed), &routine); err != nil {
+		return nil, false
+	}
+	return &routine, true
+}
+
+// closeJSON дописывает к оборванному JSON закрывающие кавычки и скобки
+func closeJSON(partial string) (string, bool) {
+	var stack []byte
+	inString, escaped := false, false
+	for i := 0; i < len(partial); i++ {
+		c := partial[i]
+		switch {
+		case inString && escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{':
+			stack = append(stack, '}')
+		case c == '[':
+			stack = append(stack, ']')
+		case c == '}' || c == ']':
+			if len(stack) == 0 {
+				return "", false
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if len(stack) == 0 {
+		return partial, !inString && strings.TrimSpace(partial) != ""
+	}
+
+	var b bytes.Buffer
+	b.WriteString(partial)
+	if inString {
+		if escaped {
+			b.Truncate(b.Len() - 1)
+		}
+		b.WriteByte('"')
+	}
+	// Запятая или двоеточие в конце означают, что следующее значение еще не пришло
+	closed := strings.TrimRight(b.String(), " \t\r\n")
+	closed = strings.TrimSuffix(closed, ",")
+	if strings.HasSuffix(closed, ":") {
+		closed += "null"
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		closed += string(stack[i])
+	}
+	return closed, true
+}
+
+// stripFence убирает обрамление ```json ... ```, которое модели иногда
+// добавляют даже при ответе по схеме
+func stripFence(content string) string {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "```") {
+		return content
+	}
+	content = strings.TrimPrefix(content, "```")
+	if newline := strings.IndexByte(content, '\n'); newline >= 0 {
+		content = content[newline+1:]
+	} else {
+		content = strings.TrimPrefix(content, "json")
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(content), "```"))
+}
@@ -0,0 +1,181 @@
+package routine
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// ErrNotFound возвращается, если пользователь еще не составил свой уход
+var ErrNotFound = errors.New("уход пользователя не найден")
+
+// Slot время нанесения
+type Slot string
+
+// Время нанесения
+const (
+	SlotAM Slot = "am"
+	SlotPM Slot = "pm"
+)
+
+// Slots утренний и вечерний уход по порядку
+var Slots = []Slot{SlotAM, SlotPM}
+
+// Known проверяет, что время нанесения есть в списке
+func (s Slot) Known() bool {
+	return s == SlotAM || s == SlotPM
+}
+
+// UserFrequencies частота, которую пользователь выбирает для шага своего ухода
+var UserFrequencies = []Frequency{FrequencyDaily, FrequencyEveryOtherDay, FrequencyTwiceWeekly}
+
+// Entry шаг ухода, который пользователь составил сам из продуктов коллекции
+type Entry struct {
+	ProductID int       `json:"product_id"`
+	Brand     string    `json:"brand"`
+	Title     string    `json:"title"`
+	Category  Category  `json:"category"`
+	Frequency Frequency `json:"frequency"`
+}
+
+// UserRoutine уход пользователя: шаги утром и вечером в порядке нанесения
+type UserRoutine struct {
+	UserID    int64
+	Name      string // название, которое дал пользователь; может быть пустым
+	Morning   []Entry
+	Evening   []Entry
+	UpdatedAt time.Time
+}
+
+// Steps возвращает шаги утреннего или вечернего ухода
+func (r *UserRoutine) Steps(slot Slot) []Entry {
+	if slot == SlotPM {
+		return r.Evening
+	}
+	return r.Morning
+}
+
+// SetSteps заменяет шаги утреннего или вечернего ухода
+func (r *UserRoutine) SetSteps(slot Slot, steps []Entry) {
+	if slot == SlotPM {
+		r.Evening = steps
+	} else {
+		r.Morning = steps
+	}
+}
+
+// Empty проверяет, что в уходе нет ни одного шага
+func (r *UserRoutine) Empty() bool {
+	return len(r.Morning)+len(r.Evening) == 0
+}
+
+// RemoveProduct убирает шаги с продуктом из утреннего и вечернего ухода
+// и сообщает, был ли такой шаг
+func (r *UserRoutine) RemoveProduct(productID int) bool {
+	removed := false
+	for _, slot := range Slots {
+		var kept []Entry
+		for _, entry := range r.Steps(slot) {
+			if entry.ProductID == productID {
+				removed = true
+				continue
+			}
+			kept = append(kept, entry)
+		}
+		r.SetSteps(slot, kept)
+	}
+	return removed
+}
+
+// Store хранит уход, составленный пользователями
+type Store interface {
+	// Get возвращает уход пользователя или ErrNotFound
+	Get(ctx context.Context, userID int64) (*UserRoutine, error)
+	// Save сохраняет уход, заменяя предыдущий, и заполняет UpdatedAt
+	Save(ctx context.Context, routine *UserRoutine) error
+	// Delete удаляет уход пользователя; отсутствие ухода не ошибка
+	Delete(ctx context.Context, userID int64) error
+}
+
+// categoryRank место этапа в порядке нанесения: средства наносятся
+// от очищения к защите (очищение → тоник → сыворотка → крем → SPF).
+// Этапы с одинаковым местом можно менять местами; маски и прочие
+// средства в правилах не участвуют.
+var categoryRank = map[Category]int{
+	CategoryCleanser:    1,
+	CategoryToner:       2,
+	CategoryExfoliant:   2,
+	CategorySerum:       3,
+	CategoryTreatment:   3,
+	CategoryEyeCare:     4,
+	CategoryMoisturizer: 5,
+	CategoryOil:         6,
+	CategorySPF:         7,
+}
+
+// IssueKind вид нарушения правил ухода
+type IssueKind string
+
+// Нарушения правил ухода
+const (
+	IssueOrder      IssueKind = "order"       // этап стоит после того, который наносится позже
+	IssueEveningSPF IssueKind = "evening_spf" // солнцезащитное средство в вечернем уходе
+)
+
+// OrderIssue нарушение порядка в уходе пользователя
+type OrderIssue struct {
+	Kind  IssueKind
+	Slot  Slot
+	Step  int   // номер шага с нарушением, с нуля
+	Entry Entry // шаг с нарушением
+	After Entry // для IssueOrder — шаг выше, перед которым его нужно нанести
+}
+
+// Check проверяет порядок этапов утром и вечером. Для каждого шага
+// сообщается первый шаг выше, который по правилам наносится позже.
+func (r *UserRoutine) Check() []OrderIssue {
+	var issues []OrderIssue
+	for _, slot := range Slots {
+		steps := r.Steps(slot)
+		for i, entry := range steps {
+			if slot == SlotPM && entry.Category == CategorySPF {
+				issues = append(issues, OrderIssue{Kind: IssueEveningSPF, Slot: slot, Step: i, Entry: entry})
+			}
+			rank, ok := categoryRank[entry.Category]
+			if !ok {
+				continue
+			}
+			for _, earlier := range steps[:i] {
+				if earlierRank, ok := categoryRank[earlier.Category]; ok && earlierRank > rank {
+					issues = append(issues, OrderIssue{Kind: IssueOrder, Slot: slot, Step: i, Entry: entry, After: earlier})
+					break
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// Sort расставляет шаги в порядке нанесения. Шаги одного места сохраняют
+// порядок, выбранный пользователем, а маски и прочие средства остаются
+// на своих позициях.
+func (r *UserRoutine) Sort() {
+	for _, slot := range Slots {
+		steps := r.Steps(slot)
+		var positions []int
+		var ranked []Entry
+		for i, entry := range steps {
+			if _, ok := categoryRank[entry.Category]; ok {
+				positions = append(positions, i)
+				ranked = append(ranked, entry)
+			}
+		}
+		sort.SliceStable(ranked, func(i, j int) bool {
+			return categoryRank[ranked[i].Category] < categoryRank[ranked[j].Category]
+		})
+		for i, position := range positions {
+			steps[position] = ranked[i]
+		}
+	}
+}
@@ -0,0 +1,406 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"cos-ai-bot/internal/database"
+	"cos-ai-bot/internal/models"
+	"cos-ai-bot/internal/routine"
+)
+
+// ActiveClass группа активов, которые конфликтуют одинаково
+type ActiveClass string
+
+// Группы активов
+const (
+	ActiveRetinoid        ActiveClass = "retinoid"
+	ActiveAcid            ActiveClass = "acid" // AHA, BHA, PHA и другие отшелушивающие
+	ActiveVitaminC        ActiveClass = "vitamin_c"
+	ActiveBenzoylPeroxide ActiveClass = "benzoyl_peroxide"
+	ActiveCopperPeptide   ActiveClass = "copper_peptide"
+	ActiveHydroquinone    ActiveClass = "hydroquinone"
+)
+
+// ConflictAdvice как развести конфликтующие средства
+type ConflictAdvice string
+
+// Способы развести средства
+const (
+	AdviceSplit     ConflictAdvice = "split"     // одно утром, другое вечером
+	AdviceAlternate ConflictAdvice = "alternate" // в разные дни
+)
+
+// activeClassRule признаки ингредиента группы: slug ингредиента (шаблон
+// с * в конце совпадает с любым продолжением) или функция из APIIngredient.Functions
+type activeClassRule struct {
+	class     ActiveClass
+	slugs     []string
+	functions []string
+}
+
+// activeClassRules как ингредиенты относятся к группам активов
+var activeClassRules = []activeClassRule{
+	{
+		class: ActiveRetinoid,
+		slugs: []string{"retinol", "retinal", "retinaldehyde", "retinyl-*", "retinoic-acid",
+			"hydroxypinacolone-retinoate", "tretinoin", "isotretinoin", "adapalene", "tazarotene"},
+	},
+	{
+		class: ActiveAcid,
+		slugs: []string{"glycolic-acid", "lactic-acid", "mandelic-acid", "malic-acid", "tartaric-acid",
+			"salicylic-acid", "betaine-salicylate", "gluconolactone", "lactobionic-acid"},
+		functions: []string{"exfoliant"},
+	},
+	{
+		class: ActiveVitaminC,
+		slugs: []string{"ascorbic-acid", "3-o-ethyl-ascorbic-acid", "ethyl-ascorbic-acid"},
+	},
+	{
+		class: ActiveBenzoylPeroxide,
+		slugs: []string{"benzoyl-peroxide"},
+	},
+	{
+		class: ActiveCopperPeptide,
+		slugs: []string{"copper-tripeptide-1", "copper-palmitoyl-heptapeptide-14", "copper-peptide*"},
+	},
+	{
+		class: ActiveHydroquinone,
+		slugs: []string{"hydroquinone"},
+	},
+}
+
+// conflictRule строка матрицы конфликтов: средства групп a и b нельзя
+// наносить в одно время. Для AdviceSplit slotA и slotB — когда наносить каждое.
+type conflictRule struct {
+	id           string
+	a, b         ActiveClass
+	advice       ConflictAdvice
+	slotA, slotB routine.Slot
+}
+
+// conflictRules матрица конфликтов; пояснения — в переводах conflicts.rule.<id>
+var conflictRules = []conflictRule{
+	{id: "retinoid_acid", a: ActiveRetinoid, b: ActiveAcid, advice: AdviceAlternate},
+	{id: "retinoid_retinoid", a: ActiveRetinoid, b: ActiveRetinoid, advice: AdviceAlternate},
+	{id: "retinoid_bpo", a: ActiveRetinoid, b: ActiveBenzoylPeroxide, advice: AdviceSplit, slotA: routine.SlotPM, slotB: routine.SlotAM},
+	{id: "retinoid_vitamin_c", a: ActiveRetinoid, b: ActiveVitaminC, advice: AdviceSplit, slotA: routine.SlotPM, slotB: routine.SlotAM},
+	{id: "vitamin_c_acid", a: ActiveVitaminC, b: ActiveAcid, advice: AdviceSplit, slotA: routine.SlotAM, slotB: routine.SlotPM},
+	{id: "vitamin_c_bpo", a: ActiveVitaminC, b: ActiveBenzoylPeroxide, advice: AdviceSplit, slotA: routine.SlotAM, slotB: routine.SlotPM},
+	{id: "vitamin_c_copper", a: ActiveVitaminC, b: ActiveCopperPeptide, advice: AdviceSplit, slotA: routine.SlotAM, slotB: routine.SlotPM},
+	{id: "acid_copper", a: ActiveAcid, b: ActiveCopperPeptide, advice: AdviceSplit, slotA: routine.SlotPM, slotB: routine.SlotAM},
+	{id: "acid_bpo", a: ActiveAcid, b: ActiveBenzoylPeroxide, advice: AdviceAlternate},
+	{id: "bpo_hydroquinone", a: ActiveBenzoylPeroxide, b: ActiveHydroquinone, advice: AdviceSplit, slotA: routine.SlotAM, slotB: routine.SlotPM},
+}
+
+// ProductActive активы одной группы в продукте
+type ProductActive struct {
+	ProductID   int         `json:"product_id"`
+	Brand       string      `json:"brand"`
+	Title       string      `json:"title"`
+	Class       ActiveClass `json:"class"`
+	Ingredients []string    `json:"ingredients"`
+}
+
+// Conflict пара продуктов, которые нельзя наносить в одно время
+type Conflict struct {
+	Rule   string         `json:"rule"`
+	First  ProductActive  `json:"first"`
+	Second ProductActive  `json:"second"`
+	Advice ConflictAdvice `json:"advice"`
+	// Для AdviceSplit — когда наносить первый и второй продукт
+	FirstSlot  routine.Slot `json:"first_slot,omitempty"`
+	SecondSlot routine.Slot `json:"second_slot,omitempty"`
+}
+
+// ConflictAnalysis результат анализа совместимости коллекции
+type ConflictAnalysis struct {
+	Actives   []ProductActive `json:"actives"`
+	Conflicts []Conflict      `json:"conflicts"`
+	Unchecked []ProductActive `json:"unchecked,omitempty"` // продукты без известного состава; Class и Ingredients пусты
+	// Partial ингредиенты не успели загрузиться, и группы активов частично
+	// определены по названиям ингредиентов
+	Partial bool `json:"partial,omitempty"`
+}
+
+// AnalyzeCollection загружает коллекцию пользователя и ищет конфликтующие активы
+func AnalyzeCollection(ctx context.Context, userID int64) (*ConflictAnalysis, error) {
+	products, err := database.GetUserProducts(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения продуктов: %v", err)
+	}
+	details, err := loadProductDetails(ctx, products)
+	if err != nil {
+		return nil, err
+	}
+	return AnalyzeConflicts(ctx, details), nil
+}
+
+// AnalyzeConflicts ищет пары продуктов с конфликтующими активами. Функции и slug
+// ингредиентов загружаются из API; если ингредиент не загрузился, slug
+// выводится из названия, а если загрузка не уложилась в detailsBudget,
+// анализ помечается как Partial. Активы внутри одного продукта не считаются конфликтом:
+// производитель уже подобрал их сочетание.
+func AnalyzeConflicts(ctx context.Context, products []*models.APIProductDetail) *ConflictAnalysis {
+	analysis := &ConflictAnalysis{Partial: !loadIngredients(ctx, products)}
+	for _, product := range products {
+		if len(product.Ingredients) == 0 {
+			analysis.Unchecked = append(analysis.Unchecked, ProductActive{ProductID: product.ID, Brand: product.Brand, Title: product.Title})
+			continue
+		}
+		analysis.Actives = append(analysis.Actives, productActives(product)...)
+	}
+
+	for i, first := range analysis.Actives {
+		for _, second := range analysis.Actives[i+1:] {
+			if first.ProductID == second.ProductID {
+				continue
+			}
+			if conflict, ok := findConflict(first, second); ok {
+				analysis.Conflicts = append(analysis.Conflicts, conflict)
+			}
+		}
+	}
+	return analysis
+}
+
+// findConflict ищет строку матрицы для пары активов в любом порядке
+func findConflict(first, second ProductActive) (Conflict, bool) {
+	for _, rule := range conflictRules {
+		switch {
+		case rule.a == first.Class && rule.b == second.Class:
+			return Conflict{Rule: rule.id, First: first, Second: second, Advice: rule.advice, FirstSlot: rule.slotA, SecondSlot: rule.slotB}, true
+		case rule.a == second.Class && rule.b == first.Class:
+			return Conflict{Rule: rule.id, First: second, Second: first, Advice: rule.advice, FirstSlot: rule.slotA, SecondSlot: rule.slotB}, true
+		}
+	}
+	return Conflict{}, false
+}
+
+// productActives группирует активы продукта по группам в порядке activeClassRules
+func productActives(product *models.APIProductDetail) []ProductActive {
+	found := make(map[ActiveClass][]string)
+	for _, ref := range product.Ingredients {
+		slug, functions := ingredientTraits(ref)
+		for _, rule := range activeClassRules {
+			if rule.matches(slug, functions) {
+				found[rule.class] = append(found[rule.class], ref.Name)
+			}
+		}
+	}
+
+	var actives []ProductActive
+	for _, rule := range activeClassRules {
+		if names := found[rule.class]; len(names) > 0 {
+			actives = append(actives, ProductActive{
+				ProductID:   product.ID,
+				Brand:       product.Brand,
+				Title:       product.Title,
+				Class:       rule.class,
+				Ingredients: names,
+			})
+		}
+	}
+	return actives
+}
+
+// matches проверяет ингредиент по slug и функциям
+func (r activeClassRule) matches(slug string, functions []string) bool {
+	for _, pattern := range r.slugs {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(slug, prefix) {
+				return true
+			}
+		} else if slug == pattern {
+			return true
+		}
+	}
+	for _, function := range functions {
+		for _, wanted := range r.functions {
+			if strings.EqualFold(strings.TrimSpace(function), wanted) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Ограничения загрузки составов: запросы к API идут параллельно, но не более
+// detailsFetchers одновременно, и укладываются в detailsBudget. Что не успело
+// загрузиться, считается незагруженным.
+const (
+	detailsFetchers = 8
+	detailsBudget   = 10 * time.Second
+)
+
+// Кэш ингредиентов: состав ингредиента меняется редко, поэтому он хранится
+// ingredientCacheTTL, но не более ingredientCacheSize записей
+const (
+	ingredientCacheTTL  = 24 * time.Hour
+	ingredientCacheSize = 10000
+)
+
+// cachedIngredient ингредиент и время его загрузки
+type cachedIngredient struct {
+	ingredient *models.APIIngredient
+	loadedAt   time.Time
+}
+
+// ingredients ингредиенты, уже загруженные из API
+var ingredients = struct {
+	sync.Mutex
+	byID map[int]cachedIngredient
+}{byID: make(map[int]cachedIngredient)}
+
+// cachedIngredientByID возвращает ингредиент из кэша, если он не устарел
+func cachedIngredientByID(id int) (*models.APIIngredient, bool) {
+	ingredients.Lock()
+	defer ingredients.Unlock()
+
+	entry, ok := ingredients.byID[id]
+	if !ok || time.Since(entry.loadedAt) > ingredientCacheTTL {
+		return nil, false
+	}
+	return entry.ingredient, true
+}
+
+// cacheIngredient сохраняет ингредиент в кэш. Когда кэш заполнен, из него
+// удаляются устаревшие записи, а если их нет — произвольная запись.
+func cacheIngredient(id int, ingredient *models.APIIngredient) {
+	ingredients.Lock()
+	defer ingredients.Unlock()
+
+	if len(ingredients.byID) >= ingredientCacheSize {
+		for cachedID, entry := range ingredients.byID {
+			if time.Since(entry.loadedAt) > ingredientCacheTTL {
+				delete(ingredients.byID, cachedID)
+			}
+		}
+	}
+	if len(ingredients.byID) >= ingredientCacheSize {
+		for cachedID := range ingredients.byID {
+			delete(ingredients.byID, cachedID)
+			break
+		}
+	}
+	ingredients.byID[id] = cachedIngredient{ingredient: ingredient, loadedAt: time.Now()}
+}
+
+// loadIngredients загружает в кэш ингредиенты продуктов, которых там еще нет.
+// Возвращает false, если загрузка не уложилась в detailsBudget; ингредиенты,
+// которые не загрузились по другим причинам, на результат не влияют.
+func loadIngredients(ctx context.Context, products []*models.APIProductDetail) bool {
+	refs := make(map[int]models.APIIngredientRef)
+	for _, product := range products {
+		for _, ref := range product.Ingredients {
+			if ref.ID == 0 {
+				continue
+			}
+			if _, ok := cachedIngredientByID(ref.ID); !ok {
+				refs[ref.ID] = ref
+			}
+		}
+	}
+	if len(refs) == 0 {
+		return true
+	}
+
+	budgetCtx, cancel := context.WithTimeout(ctx, detailsBudget)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	fetchers := make(chan struct{}, detailsFetchers)
+	for _, ref := range refs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case fetchers <- struct{}{}:
+			case <-budgetCtx.Done():
+				return
+			}
+			defer func() { <-fetchers }()
+
+			ingredient, err := database.GetIngredient(budgetCtx, ref.ID)
+			if err != nil {
+				if budgetCtx.Err() == nil {
+					log.Printf("Ошибка получения ингредиента %d (%s): %v", ref.ID, ref.Name, err)
+				}
+				return
+			}
+			cacheIngredient(ref.ID, ingredient)
+		}()
+	}
+	wg.Wait()
+
+	if budgetCtx.Err() != nil && ctx.Err() == nil {
+		log.Printf("Ингредиенты не загрузились за %s (запрошено %d), анализ конфликтов неполный", detailsBudget, len(refs))
+		return false
+	}
+	return ctx.Err() == nil
+}
+
+// ingredientTraits возвращает slug и функции ингредиента из кэша; если
+// ингредиент не загружен, slug выводится из названия
+func ingredientTraits(ref models.APIIngredientRef) (string, []string) {
+	ingredient, _ := cachedIngredientByID(ref.ID)
+	if ingredient == nil || ingredient.Slug == "" {
+		slug := strings.ReplaceAll(normalizeINCI(ref.Name), " ", "-")
+		if ingredient == nil {
+			return slug, nil
+		}
+		return slug, ingredient.Functions
+	}
+	return ingredient.Slug, ingredient.Functions
+}
+
+// loadProductDetails загружает составы продуктов коллекции параллельно
+// в пределах detailsBudget; продукт, который не удалось загрузить или который
+// не успел загрузиться, остается без состава
+func loadProductDetails(ctx context.Context, products []models.APIUserProduct) ([]*models.APIProductDetail, error) {
+	budgetCtx, cancel := context.WithTimeout(ctx, detailsBudget)
+	defer cancel()
+
+	details := make([]*models.APIProductDetail, len(products))
+	var wg sync.WaitGroup
+	fetchers := make(chan struct{}, detailsFetchers)
+	for i, product := range products {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case fetchers <- struct{}{}:
+			case <-budgetCtx.Done():
+				return
+			}
+			defer func() { <-fetchers }()
+
+			detail, err := database.GetProduct(budgetCtx, product.ProductID)
+			if err != nil {
+				if budgetCtx.Err() == nil {
+					log.Printf("Ошибка получения состава продукта %d: %v", product.ProductID, err)
+				}
+				return
+			}
+			details[i] = detail
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if budgetCtx.Err() != nil {
+		log.Printf("Составы продуктов не загрузились за %s, недозагруженные остаются без состава", detailsBudget)
+	}
+	for i, product := range products {
+		if details[i] == nil {
+			details[i] = &models.APIProductDetail{ID: product.ProductID, Brand: product.Brand, Title: product.Title}
+		}
+	}
+	return details, nil
+}
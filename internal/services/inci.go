@@ -0,0 +1,184 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+// IngredientConcern группа ингредиентов, которых пользователь может избегать
+type IngredientConcern string
+
+// Группы ингредиентов
+const (
+	ConcernFragrance    IngredientConcern = "fragrance"    // отдушки и аллергены отдушек
+	ConcernLanolin      IngredientConcern = "lanolin"      // ланолин и его производные
+	ConcernPreservative IngredientConcern = "preservative" // консерванты
+	ConcernAlcohol      IngredientConcern = "alcohol"      // этиловый спирт (жирные спирты не считаются)
+	ConcernAnimal       IngredientConcern = "animal"       // любое животное происхождение
+	ConcernSlaughter    IngredientConcern = "slaughter"    // получено при забое животных
+	ConcernGluten       IngredientConcern = "gluten"       // пшеница, ячмень, рожь
+	ConcernCustom       IngredientConcern = "custom"       // указано пользователем в «Другое»
+)
+
+// inciRule правило распознавания ингредиента по названию INCI.
+// Шаблон сравнивается с нормализованным названием (см. normalizeINCI):
+//
+//	"=alcohol"  — название целиком;
+//	"~paraben"  — подстрока в любом месте, в том числе внутри слова;
+//	"wool wax"  — слова подряд в любом месте названия.
+type inciRule struct {
+	pattern  string
+	concerns []IngredientConcern
+}
+
+var (
+	inciFragrance    = []IngredientConcern{ConcernFragrance}
+	inciLanolin      = []IngredientConcern{ConcernLanolin, ConcernAnimal}
+	inciPreservative = []IngredientConcern{ConcernPreservative}
+	inciEthanol      = []IngredientConcern{ConcernAlcohol}
+	inciAnimal       = []IngredientConcern{ConcernAnimal}
+	inciSlaughter    = []IngredientConcern{ConcernAnimal, ConcernSlaughter}
+	inciGluten       = []IngredientConcern{ConcernGluten}
+)
+
+// inciRules список синонимов INCI для каждой группы. Список составлен вручную:
+// в него входят названия, которые встречаются на упаковках в ЕС, США и Корее,
+// включая 26 аллергенов отдушек из Регламента ЕС 1223/2009.
+var inciRules = []inciRule{
+	// Отдушки
+	{"parfum", inciFragrance},
+	{"fragrance", inciFragrance},
+	{"=aroma", inciFragrance},
+	{"perfume", inciFragrance},
+	{"essential oil", inciFragrance},
+	{"limonene", inciFragrance},
+	{"linalool", inciFragrance},
+	{"citronellol", inciFragrance},
+	{"geraniol", inciFragrance},
+	{"citral", inciFragrance},
+	{"eugenol", inciFragrance},
+	{"isoeugenol", inciFragrance},
+	{"coumarin", inciFragrance},
+	{"farnesol", inciFragrance},
+	{"benzyl salicylate", inciFragrance},
+	{"benzyl benzoate", inciFragrance},
+	{"benzyl cinnamate", inciFragrance},
+	{"cinnamal", inciFragrance},
+	{"cinnamyl alcohol", inciFragrance},
+	{"amyl cinnamal", inciFragrance},
+	{"amylcinnamyl alcohol", inciFragrance},
+	{"hexyl cinnamal", inciFragrance},
+	{"hydroxycitronellal", inciFragrance},
+	{"anise alcohol", inciFragrance},
+	{"alpha isomethyl ionone", inciFragrance},
+	{"butylphenyl methylpropional", inciFragrance},
+	{"methyl 2 octynoate", inciFragrance},
+	{"hydroxyisohexyl 3 cyclohexene carboxaldehyde", inciFragrance},
+	{"evernia prunastri", inciFragrance},
+	{"evernia furfuracea", inciFragrance},
+
+	// Ланолин
+	{"lanolin", inciLanolin},
+	{"laneth", inciLanolin},
+	{"adeps lanae", inciLanolin},
+	{"wool wax", inciLanolin},
+	{"wool fat", inciLanolin},
+
+	// Консерванты
+	{"~paraben", inciPreservative},
+	{"phenoxyethanol", inciPreservative},
+	{"~isothiazolinone", inciPreservative},
+	{"dmdm hydantoin", inciPreservative},
+	{"imidazolidinyl urea", inciPreservative},
+	{"diazolidinyl urea", inciPreservative},
+	{"quaternium 15", inciPreservative},
+	{"bronopol", inciPreservative},
+	{"2 bromo 2 nitropropane 1 3 diol", inciPreservative},
+	{"sodium hydroxymethylglycinate", inciPreservative},
+	{"formaldehyde", inciPreservative},
+	{"=benzyl alcohol", inciPreservative},
+	{"sodium benzoate", inciPreservative},
+	{"potassium sorbate", inciPreservative},
+	{"sorbic acid", inciPreservative},
+	{"benzoic acid", inciPreservative},
+	{"dehydroacetic acid", inciPreservative},
+	{"sodium dehydroacetate", inciPreservative},
+	{"chlorphenesin", inciPreservative},
+	{"iodopropynyl butylcarbamate", inciPreservative},
+	{"methyldibromo glutaronitrile", inciPreservative},
+	{"triclosan", inciPreservative},
+
+	// Этиловый спирт; cetyl alcohol и другие жирные спирты сюда не относятся
+	{"=alcohol", inciEthanol},
+	{"alcohol denat", inciEthanol},
+	{"denatured alcohol", inciEthanol},
+	{"sd alcohol", inciEthanol},
+	{"=ethanol", inciEthanol},
+	{"ethyl alcohol", inciEthanol},
+
+	// Животное происхождение без забоя
+	{"=cera alba", inciAnimal},
+	{"cera flava", inciAnimal},
+	{"beeswax", inciAnimal},
+	{"=mel", inciAnimal},
+	{"honey", inciAnimal},
+	{"propolis", inciAnimal},
+	{"royal jelly", inciAnimal},
+	{"=milk", inciAnimal},
+	{"milk protein", inciAnimal},
+	{"goat milk", inciAnimal},
+	{"lactis", inciAnimal},
+	{"whey", inciAnimal},
+	{"casein", inciAnimal},
+	{"snail", inciAnimal},
+	{"silk", inciAnimal},
+	{"sericin", inciAnimal},
+	{"shellac", inciAnimal},
+	{"ovum", inciAnimal},
+	{"egg", inciAnimal},
+
+	// Получено при забое животных
+	{"gelatin", inciSlaughter},
+	{"collagen", inciSlaughter},
+	{"elastin", inciSlaughter},
+	{"keratin", inciSlaughter},
+	{"tallow", inciSlaughter},
+	{"~tallowate", inciSlaughter},
+	{"carmine", inciSlaughter},
+	{"ci 75470", inciSlaughter},
+	{"squalene", inciSlaughter},
+	{"guanine", inciSlaughter},
+	{"placenta", inciSlaughter},
+	{"caviar", inciSlaughter},
+	{"mink oil", inciSlaughter},
+	{"emu oil", inciSlaughter},
+	{"fish oil", inciSlaughter},
+
+	// Глютен
+	{"wheat", inciGluten},
+	{"triticum", inciGluten},
+	{"hordeum", inciGluten},
+	{"secale", inciGluten},
+	{"gluten", inciGluten},
+}
+
+// matchINCI проверяет нормализованное название ингредиента по шаблону правила
+func matchINCI(name, pattern string) bool {
+	switch {
+	case strings.HasPrefix(pattern, "="):
+		return name == pattern[1:]
+	case strings.HasPrefix(pattern, "~"):
+		return strings.Contains(name, pattern[1:])
+	default:
+		return strings.Contains(" "+name+" ", " "+pattern+" ")
+	}
+}
+
+// normalizeINCI приводит название к виду для сравнения: нижний регистр,
+// знаки препинания и скобки заменены пробелами, пробелы не повторяются.
+// «Parfum (Fragrance)» → «parfum fragrance», «Alcohol Denat.» → «alcohol denat».
+func normalizeINCI(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
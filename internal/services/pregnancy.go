@@ -0,0 +1,231 @@
+package services
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cos-ai-bot/internal/catalog"
+	"cos-ai-bot/internal/models"
+)
+
+// SafetySeverity насколько строго стоит избегать актива при беременности или лактации
+type SafetySeverity string
+
+// Уровни строгости, от самого строгого
+const (
+	SeverityAvoid   SafetySeverity = "avoid"   // не использовать
+	SeverityLimit   SafetySeverity = "limit"   // допустимы только низкие концентрации
+	SeverityCaution SafetySeverity = "caution" // данных мало, обсудить с врачом
+)
+
+// rank порядок строгости: чем больше, тем строже; пустая строгость — 0
+func (s SafetySeverity) rank() int {
+	switch s {
+	case SeverityAvoid:
+		return 3
+	case SeverityLimit:
+		return 2
+	case SeverityCaution:
+		return 1
+	}
+	return 0
+}
+
+// safetyRule правило для одной группы активов
+type safetyRule struct {
+	id        string
+	patterns  []string       // шаблоны INCI, как в inciRules
+	pregnancy SafetySeverity // при беременности; пусто — не ограничено
+	lactation SafetySeverity // при лактации; пусто — не ограничено
+	// highDose концентрация в процентах, выше которой актив нужно избегать совсем;
+	// определяется по названию и описанию продукта, где упоминается одно из doseWords
+	highDose  float64
+	doseWords []string
+}
+
+// safetyRules активы, ограниченные при беременности и лактации
+var safetyRules = []safetyRule{
+	{
+		id: "retinoids",
+		patterns: []string{"retinol", "retinal", "retinaldehyde", "~retinyl", "retinoic acid",
+			"hydroxypinacolone retinoate", "tretinoin", "isotretinoin", "adapalene", "tazarotene"},
+		pregnancy: SeverityAvoid,
+		lactation: SeverityCaution,
+	},
+	{
+		id:        "hydroquinone",
+		patterns:  []string{"hydroquinone"},
+		pregnancy: SeverityAvoid,
+		lactation: SeverityAvoid,
+	},
+	{
+		id:        "salicylic",
+		patterns:  []string{"salicylic acid", "sodium salicylate", "betaine salicylate", "salix alba", "willow bark"},
+		pregnancy: SeverityLimit,
+		lactation: SeverityLimit,
+		highDose:  2,
+		doseWords: []string{"salicylic", "bha", "салицил"},
+	},
+	{
+		id:        "benzoyl_peroxide",
+		patterns:  []string{"benzoyl peroxide"},
+		pregnancy: SeverityLimit,
+		lactation: SeverityLimit,
+		highDose:  5,
+		doseWords: []string{"benzoyl", "бензоил"},
+	},
+	{
+		id:        "arbutin",
+		patterns:  []string{"arbutin"},
+		pregnancy: SeverityCaution,
+		lactation: SeverityCaution,
+	},
+	{
+		id:        "oxybenzone",
+		patterns:  []string{"oxybenzone", "benzophenone 3"},
+		pregnancy: SeverityCaution,
+		lactation: SeverityCaution,
+	},
+	{
+		id:        "kojic",
+		patterns:  []string{"kojic acid"},
+		pregnancy: SeverityCaution,
+	},
+}
+
+// percentPattern концентрация в описании продукта: «2%», «0,5 %»
+var percentPattern = regexp.MustCompile(`(\d+(?:[.,]\d+)?)\s*%`)
+
+// SafetyFlag ограниченный актив, найденный в продукте
+type SafetyFlag struct {
+	Rule        string // идентификатор правила; название и пояснение — в переводах safety.rule.<id>
+	Severity    SafetySeverity
+	Ingredients []string // названия ингредиентов как в составе; пусто для общих ограничений
+	HighDose    bool     // строгость повышена из-за высокой концентрации в описании
+}
+
+// PregnancySafety проверяет продукты для беременных и кормящих пользователей
+type PregnancySafety struct {
+	status    string
+	pregnant  bool
+	lactating bool
+}
+
+// NewPregnancySafety определяет по анкете, нужны ли проверки
+func NewPregnancySafety(state *models.UserState) *PregnancySafety {
+	p := &PregnancySafety{}
+	if state == nil {
+		return p
+	}
+	p.status = state.Pregnancy
+	switch catalog.Pregnancy(state.Pregnancy) {
+	case catalog.PregnancyPregnant:
+		p.pregnant = true
+	case catalog.PregnancyLactation:
+		p.lactating = true
+	case catalog.PregnancyBoth:
+		p.pregnant, p.lactating = true, true
+	}
+	return p
+}
+
+// Applies проверяет, что пользователь беременна или кормит грудью
+func (p *PregnancySafety) Applies() bool {
+	return p.pregnant || p.lactating
+}
+
+// Status код ответа анкеты о беременности и лактации
+func (p *PregnancySafety) Status() string {
+	return p.status
+}
+
+// severity строгость правила для пользователя: при беременности и лактации
+// одновременно берется более строгая
+func (p *PregnancySafety) severity(rule safetyRule) SafetySeverity {
+	var severity SafetySeverity
+	if p.pregnant {
+		severity = rule.pregnancy
+	}
+	if p.lactating && rule.lactation.rank() > severity.rank() {
+		severity = rule.lactation
+	}
+	return severity
+}
+
+// Constraints ограничения, которые действуют для пользователя независимо от продуктов
+func (p *PregnancySafety) Constraints() []SafetyFlag {
+	var flags []SafetyFlag
+	for _, rule := range safetyRules {
+		if severity := p.severity(rule); severity != "" {
+			flags = append(flags, SafetyFlag{Rule: rule.id, Severity: severity})
+		}
+	}
+	return flags
+}
+
+// Check находит в продукте ограниченные активы, от самых строгих к мягким
+func (p *PregnancySafety) Check(product *models.APIProductDetail) []SafetyFlag {
+	if !p.Applies() {
+		return nil
+	}
+
+	description := strings.ToLower(product.Title + " " + product.Details)
+	var flags []SafetyFlag
+	for _, rule := range safetyRules {
+		severity := p.severity(rule)
+		if severity == "" {
+			continue
+		}
+
+		var names []string
+		for _, ingredient := range product.Ingredients {
+			name := normalizeINCI(ingredient.Name)
+			for _, pattern := range rule.patterns {
+				if matchINCI(name, pattern) {
+					names = append(names, ingredient.Name)
+					break
+				}
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+
+		flag := SafetyFlag{Rule: rule.id, Severity: severity, Ingredients: names}
+		if rule.highDose > 0 && severity != SeverityAvoid && mentionsHighDose(description, rule) {
+			flag.Severity, flag.HighDose = SeverityAvoid, true
+		}
+		flags = append(flags, flag)
+	}
+
+	// Самые строгие ограничения показываются первыми
+	sort.SliceStable(flags, func(i, j int) bool {
+		return flags[i].Severity.rank() > flags[j].Severity.rank()
+	})
+	return flags
+}
+
+// mentionsHighDose проверяет, что описание продукта упоминает актив
+// и концентрацию выше допустимой
+func mentionsHighDose(description string, rule safetyRule) bool {
+	mentioned := false
+	for _, word := range rule.doseWords {
+		if strings.Contains(description, word) {
+			mentioned = true
+			break
+		}
+	}
+	if !mentioned {
+		return false
+	}
+
+	for _, match := range percentPattern.FindAllStringSubmatch(description, -1) {
+		percent, err := strconv.ParseFloat(strings.Replace(match[1], ",", ".", 1), 64)
+		if err == nil && percent > rule.highDose {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"fmt"
+
+	"cos-ai-bot/internal/api"
+	"cos-ai-bot/internal/config"
+	"cos-ai-bot/internal/llmcache"
+)
+
+// NewLLMProvider создает провайдер языковой модели, выбранный в конфигурации,
+// с повторными попытками и переходом на резервные модели. Если передан cache,
+// одинаковые запросы обслуживаются из него без обращения к модели.
+func NewLLMProvider(cfg *config.Config, cache llmcache.Store) (api.LLMProvider, error) {
+	provider, err := newBaseLLMProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	retrying := api.NewRetryingProvider(provider, api.RetryPolicy{
+		MaxAttempts: cfg.LLMMaxAttempts,
+		BaseDelay:   cfg.LLMRetryBaseDelay,
+		MaxDelay:    cfg.LLMRetryMaxDelay,
+	})
+	if cache == nil {
+		return retrying, nil
+	}
+	return llmcache.NewProvider(retrying, cache), nil
+}
+
+// newBaseLLMProvider создает клиент выбранного API без обертки повторов
+func newBaseLLMProvider(cfg *config.Config) (api.LLMProvider, error) {
+	switch cfg.LLMProvider {
+	case config.LLMProviderOpenRouter:
+		fmt.Printf("LLM провайдер: OpenRouter, API ключ длиной %d символов\n", len(cfg.OpenRouterAPIKey))
+		return api.NewOpenRouterClient(cfg.OpenRouterAPIKey), nil
+	case config.LLMProviderOpenAI:
+		fmt.Printf("LLM провайдер: OpenAI-совместимый API %s\n", cfg.LLMBaseURL)
+		return api.NewOpenAIClient(cfg.LLMBaseURL, cfg.LLMAPIKey), nil
+	case config.LLMProviderFake:
+		fmt.Printf("LLM провайдер: фейковый, запросы в сеть не отправляются\n")
+		return api.NewFakeLLMProvider(), nil
+	default:
+		return nil, fmt.Errorf("неизвестный LLM провайдер: %s", cfg.LLMProvider)
+	}
+}
+
+// ModelsFromConfig возвращает параметры модели для каждого типа рекомендаций
+func ModelsFromConfig(cfg *config.Config) map[RecommendationType]config.ModelSettings {
+	return map[RecommendationType]config.ModelSettings{
+		RecommendationAnketa:   cfg.AnketaModel,
+		RecommendationProducts: cfg.ProductsModel,
+		RecommendationGeneral:  cfg.GeneralModel,
+	}
+}
@@ -1,186 +1,549 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"math"
 	"strings"
 
 	"cos-ai-bot/internal/api"
+	"cos-ai-bot/internal/config"
 	"cos-ai-bot/internal/database"
+	"cos-ai-bot/internal/history"
+	"cos-ai-bot/internal/i18n"
+	"cos-ai-bot/internal/llmcache"
 	"cos-ai-bot/internal/models"
+	"cos-ai-bot/internal/questionnaire"
+	"cos-ai-bot/internal/routine"
+)
+
+// RecommendationType тип рекомендаций; для каждого типа свои параметры модели
+type RecommendationType string
+
+// Типы рекомендаций
+const (
+	RecommendationAnketa   RecommendationType = "anketa"
+	RecommendationProducts RecommendationType = "products"
+	RecommendationGeneral  RecommendationType = "general"
 )
 
 // RecommendationService сервис для работы с рекомендациями
 type RecommendationService struct {
-	openRouterClient *api.OpenRouterClient
+	provider api.LLMProvider
+	models   map[RecommendationType]config.ModelSettings
+	history  history.Store // выданные рекомендации; nil — не сохранять
+	routines routine.Store // уход, составленный пользователями; nil — не учитывать
 }
 
-// NewRecommendationService создает новый сервис рекомендаций
-func NewRecommendationService(openRouterAPIKey string) *RecommendationService {
-	fmt.Printf("Инициализация сервиса рекомендаций с API ключом длиной %d символов\n", len(openRouterAPIKey))
+// NewRecommendationService создает новый сервис рекомендаций.
+// Каждая выданная рекомендация сохраняется в history; уход из routines
+// заменяет в рекомендациях по продуктам простой список коллекции.
+func NewRecommendationService(provider api.LLMProvider, models map[RecommendationType]config.ModelSettings, history history.Store, routines routine.Store) *RecommendationService {
+	for kind, settings := range models {
+		fmt.Printf("Рекомендации %s: модель %s, temperature %.2f, max_tokens %d\n", kind, settings.Model, settings.Temperature, settings.MaxTokens)
+	}
 	return &RecommendationService{
-		openRouterClient: api.NewOpenRouterClient(openRouterAPIKey),
+		provider: provider,
+		models:   models,
+		history:  history,
+		routines: routines,
 	}
 }
 
+// ProgressFunc получает незаконченную рекомендацию в Markdown целиком
+// каждый раз, когда от модели приходит новый фрагмент. Пустая строка
+// означает, что показанный текст больше не действителен: ответ отклонен
+// и запрос повторяется.
+type ProgressFunc func(partial string)
+
+// recommendation план ухода, полученный от модели
+type recommendation struct {
+	model   string
+	routine *routine.Routine
+	content string // план в Markdown на языке пользователя
+	cached  bool   // ответ взят из кэша, а не сгенерирован заново
+}
+
+// complete отправляет промпт модели, настроенной для данного типа рекомендаций,
+// и требует ответ в виде JSON по схеме плана ухода (см. routine.Schema).
+// Системные сообщения просят модель отвечать на языке пользователя из ctx
+// и описывают поля плана; непустые constraints передаются отдельным
+// системным сообщением как обязательные ограничения.
+// Ответ проверяется; если он не соответствует схеме, модель один раз
+// получает описание ошибки и отвечает заново. products — коллекция,
+// на которую могут ссылаться шаги плана.
+// Если передан onProgress и провайдер поддерживает потоковую генерацию,
+// незаконченный план передается в onProgress по мере поступления.
+func (s *RecommendationService) complete(ctx context.Context, kind RecommendationType, prompt, constraints string, products []models.APIUserProduct, onProgress ProgressFunc) (*recommendation, error) {
+	settings, ok := s.models[kind]
+	if !ok {
+		return nil, fmt.Errorf("не настроена модель для рекомендаций %s", kind)
+	}
+
+	loc := i18n.FromContext(ctx)
+	messages := []api.Message{
+		{Role: api.RoleSystem, Content: loc.T("prompt.language")},
+		{Role: api.RoleSystem, Content: loc.T("prompt.routine")},
+	}
+	if constraints != "" {
+		messages = append(messages, api.Message{Role: api.RoleSystem, Content: constraints})
+	}
+	messages = append(messages, api.Message{Role: api.RoleUser, Content: prompt})
+
+	req := api.ChatRequest{
+		Model:          settings.Model,
+		FallbackModels: settings.FallbackModels,
+		Messages:       messages,
+		MaxTokens:      settings.MaxTokens,
+		Temperature:    settings.Temperature,
+		ResponseFormat: api.NewJSONSchemaFormat(routine.SchemaName, routine.Schema()),
+	}
+
+	// Негодный ответ не должен попасть в кэш и вернуться из него при повторе
+	ctx = llmcache.WithValidator(ctx, func(content string) error {
+		_, err := routine.Parse(content)
+		return err
+	})
+
+	var onDelta api.DeltaFunc
+	if onProgress != nil {
+		var raw strings.Builder
+		onDelta = func(delta string) {
+			raw.WriteString(delta)
+			if partial, ok := routine.ParsePartial(raw.String()); ok {
+				onProgress(routine.Render(loc, partial))
+			}
+		}
+	}
+
+	response, err := s.send(ctx, req, onDelta)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := routine.Parse(response.Content)
+	if errors.Is(err, routine.ErrInvalid) {
+		log.Printf("Модель %s вернула план не по схеме, повторяем запрос: %v", response.Model, err)
+		// Показанный по ходу генерации план отклонен; повтор идет без потока
+		if onProgress != nil {
+			onProgress("")
+		}
+		req.Messages = append(req.Messages,
+			api.Message{Role: api.RoleAssistant, Content: response.Content},
+			api.Message{Role: api.RoleUser, Content: loc.T("prompt.routine.retry", err.Error())},
+		)
+		if response, err = s.send(ctx, req, nil); err != nil {
+			return nil, err
+		}
+		plan, err = routine.Parse(response.Content)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dropUnknownProducts(plan, products)
+	return &recommendation{model: response.Model, routine: plan, content: routine.Render(loc, plan), cached: response.Cached}, nil
+}
+
+// send отправляет запрос модели, потоком, если передан onDelta и провайдер это умеет
+func (s *RecommendationService) send(ctx context.Context, req api.ChatRequest, onDelta api.DeltaFunc) (*api.ChatResponse, error) {
+	if streaming, ok := s.provider.(api.StreamingLLMProvider); ok && onDelta != nil {
+		return streaming.ChatCompletionStream(ctx, req, onDelta)
+	}
+	return s.provider.ChatCompletion(ctx, req)
+}
+
+// dropUnknownProducts убирает из шагов ссылки на продукты, которых нет
+// в коллекции: модель могла выдумать идентификатор. Название продукта остается.
+func dropUnknownProducts(plan *routine.Routine, products []models.APIUserProduct) {
+	known := make(map[int]bool, len(products))
+	for _, product := range products {
+		known[product.ProductID] = true
+	}
+	for _, steps := range [][]routine.Step{plan.Morning, plan.Evening} {
+		for i := range steps {
+			if id := steps[i].ProductID; id != nil && !known[*id] {
+				log.Printf("План ссылается на продукт %d не из коллекции, ссылка убрана", *id)
+				steps[i].ProductID = nil
+			}
+		}
+	}
+}
+
+// remember сохраняет рекомендацию в историю вместе с анкетой и коллекцией,
+// на которых она основана. Ошибка сохранения не мешает показать рекомендацию.
+func (s *RecommendationService) remember(ctx context.Context, userID int64, kind RecommendationType, state *models.UserState, products []models.APIUserProduct, rec *recommendation) {
+	if s.history == nil || (rec.cached && s.alreadyRemembered(ctx, userID, kind, rec)) {
+		return
+	}
+
+	record := &history.Record{
+		UserID:  userID,
+		Type:    string(kind),
+		Profile: state,
+		Model:   rec.model,
+		Content: rec.content,
+		Routine: rec.routine,
+	}
+	if kind != RecommendationAnketa {
+		record.Products = history.ProductsSnapshot(products)
+	}
+	if err := s.history.Add(ctx, record); err != nil {
+		log.Printf("Ошибка сохранения рекомендации пользователя %d в историю: %v", userID, err)
+	}
+}
+
+// alreadyRemembered проверяет, что последняя рекомендация пользователя этого
+// типа совпадает с ответом из кэша: повтор из кэша не записывается в историю
+// еще раз, иначе сравнение с прошлой показало бы одинаковые тексты. Кэш общий
+// для пользователей, поэтому ответ, впервые показанный этому пользователю,
+// записывается.
+func (s *RecommendationService) alreadyRemembered(ctx context.Context, userID int64, kind RecommendationType, rec *recommendation) bool {
+	latest, err := s.history.Previous(ctx, &history.Record{UserID: userID, Type: string(kind), ID: math.MaxInt64})
+	if err != nil {
+		if !errors.Is(err, history.ErrNotFound) {
+			log.Printf("Ошибка получения последней рекомендации пользователя %d: %v", userID, err)
+		}
+		return false
+	}
+	return latest.Content == rec.content
+}
+
 // Рекомендации на основе анкеты
-func (s *RecommendationService) GetAnketaRecommendations(userID int64) (string, error) {
+func (s *RecommendationService) GetAnketaRecommendations(ctx context.Context, userID int64, onProgress ProgressFunc) (string, error) {
 	// Получаем профиль пользователя
-	profile, err := database.GetUserProfile(userID)
+	profile, err := database.GetUserProfile(ctx, userID)
 	if err != nil {
 		return "", fmt.Errorf("ошибка получения профиля: %v", err)
 	}
 
+	loc := i18n.FromContext(ctx)
+
 	// Формируем анкету для промпта
-	anketaText := s.formatAnketaForPrompt(profile)
+	state := database.StateFromProfile(profile)
+	anketaText := s.formatAnketaForPrompt(loc, state)
 
 	// Создаем промпт
-	prompt := fmt.Sprintf(`Ты — профессиональный косметолог и дерматолог-консультант.
+	prompt := loc.T("prompt.anketa", anketaText)
 
-На основе анкеты, составь рекомендации:
-- Распиши оптимальный план ухода (утро/вечер) с последовательностью применения (step-by-step).
-- Учитывай тип кожи, возраст, пол, беременность, аллергию на ингредиенты, климат и цели ухода.
-- Если найден потенциальный аллерген в составе — предупреди.
-- Рекомендации должны быть понятными и аккуратными, как будто ты — дерматолог-консультант.
-- Укажи, какие продукты можно использовать утром, какие вечером, какие через день, какие несовместимы между собой.
-- В конце дай 2–3 рекомендации по продуктам, которых явно не хватает.
+	constraints := s.safetyConstraints(loc, state, nil)
 
-**Анкета пользователя:**
-%s`, anketaText)
+	rec, err := s.complete(ctx, RecommendationAnketa, prompt, constraints, nil, onProgress)
+	if err != nil {
+		return "", err
+	}
 
-	return s.openRouterClient.GetRecommendation(prompt)
+	s.remember(ctx, userID, RecommendationAnketa, state, nil, rec)
+	return rec.content, nil
 }
 
 // GetProductsRecommendations получает рекомендации с учётом продуктов пользователя
-func (s *RecommendationService) GetProductsRecommendations(userID int64) (string, error) {
+func (s *RecommendationService) GetProductsRecommendations(ctx context.Context, userID int64, onProgress ProgressFunc) (string, error) {
 	// Получаем профиль пользователя
-	profile, err := database.GetUserProfile(userID)
+	profile, err := database.GetUserProfile(ctx, userID)
 	if err != nil {
 		return "", fmt.Errorf("ошибка получения профиля: %v", err)
 	}
 
 	// Получаем продукты пользователя
-	products, err := database.GetUserProducts(userID)
+	products, err := database.GetUserProducts(ctx, userID)
 	if err != nil {
 		return "", fmt.Errorf("ошибка получения продуктов: %v", err)
 	}
 
-	// Формируем анкету и продукты для промпта
-	anketaText := s.formatAnketaForPrompt(profile)
-	productsText := s.formatProductsForPrompt(products)
+	loc := i18n.FromContext(ctx)
 
-	// Создаем промпт
-	prompt := fmt.Sprintf(`Ты — профессиональный косметолог и дерматолог-консультант.
+	// Составы нужны для проверки совместимости и ограничений безопасности
+	details, err := loadProductDetails(ctx, products)
+	if err != nil {
+		return "", err
+	}
 
-На основе анкеты и списка косметических средств, составь рекомендации:
-- Распиши оптимальный план ухода (утро/вечер) с последовательностью применения (step-by-step).
-- Используй только средства, которые уже есть у пользователя, **но укажи, если какого-то этапа не хватает**.
-- Учитывай тип кожи, возраст, пол, беременность, аллергию на ингредиенты, климат и цели ухода.
-- Если найден потенциальный аллерген в составе — предупреди.
-- Рекомендации должны быть понятными и аккуратными, как будто ты — дерматолог-консультант.
-- Укажи, какие продукты можно использовать утром, какие вечером, какие через день, какие несовместимы между собой.
-- В конце дай 2–3 рекомендации по продуктам, которых явно не хватает.
+	// Формируем анкету и продукты для промпта; если пользователь составил
+	// свой уход, модель получает его вместо простого списка продуктов
+	state := database.StateFromProfile(profile)
+	anketaText := s.formatAnketaForPrompt(loc, state)
+	productsText := s.formatProductsForPrompt(loc, products)
+	if own := s.userRoutine(ctx, userID); own != nil {
+		productsText = s.formatUserRoutineForPrompt(loc, own, products)
+	}
+
+	// Создаем промпт
+	prompt := loc.T("prompt.products", anketaText, productsText)
+	// Неполный анализ мог пропустить конфликты, поэтому модель его не получает
+	if analysis := AnalyzeConflicts(ctx, details); !analysis.Partial {
+		prompt += s.formatConflictsForPrompt(loc, analysis)
+	}
 
-**Анкета пользователя:**
-%s
+	constraints := s.safetyConstraints(loc, state, details)
 
-**Продукты пользователя:**
-%s`, anketaText, productsText)
+	rec, err := s.complete(ctx, RecommendationProducts, prompt, constraints, products, onProgress)
+	if err != nil {
+		return "", err
+	}
 
-	return s.openRouterClient.GetRecommendation(prompt)
+	s.remember(ctx, userID, RecommendationProducts, state, products, rec)
+	return rec.content, nil
 }
 
 // GetGeneralRecommendations получает общие рекомендации
-func (s *RecommendationService) GetGeneralRecommendations(userID int64) (string, error) {
+func (s *RecommendationService) GetGeneralRecommendations(ctx context.Context, userID int64, onProgress ProgressFunc) (string, error) {
 	// Получаем профиль пользователя
-	profile, err := database.GetUserProfile(userID)
+	profile, err := database.GetUserProfile(ctx, userID)
 	if err != nil {
 		return "", fmt.Errorf("ошибка получения профиля: %v", err)
 	}
 
 	// Получаем продукты пользователя
-	products, err := database.GetUserProducts(userID)
+	products, err := database.GetUserProducts(ctx, userID)
 	if err != nil {
 		return "", fmt.Errorf("ошибка получения продуктов: %v", err)
 	}
 
+	loc := i18n.FromContext(ctx)
+
 	// Формируем анкету и продукты для промпта
-	anketaText := s.formatAnketaForPrompt(profile)
-	productsText := s.formatProductsForPrompt(products)
+	state := database.StateFromProfile(profile)
+	anketaText := s.formatAnketaForPrompt(loc, state)
+	productsText := s.formatProductsForPrompt(loc, products)
 
 	// Создаем промпт для общих рекомендаций
-	prompt := fmt.Sprintf(`Ты — профессиональный косметолог и дерматолог-консультант.
-
-На основе анкеты и списка косметических средств, составь общие рекомендации:
-- Проанализируй текущий уход и дай общие советы по улучшению.
-- Укажи, какие этапы ухода отсутствуют или недостаточно проработаны.
-- Дай рекомендации по изменению образа жизни для улучшения состояния кожи.
-- Предложи общие принципы ухода, которые подходят для данного типа кожи и возраста.
-- Укажи сезонные особенности ухода.
-- Дай советы по питанию и образу жизни для здоровья кожи.
+	prompt := loc.T("prompt.general", anketaText, productsText)
 
-**Анкета пользователя:**
-%s
+	// Составы нужны только для проверки безопасности
+	var details []*models.APIProductDetail
+	if NewPregnancySafety(state).Applies() {
+		if details, err = loadProductDetails(ctx, products); err != nil {
+			return "", err
+		}
+	}
+	constraints := s.safetyConstraints(loc, state, details)
 
-**Продукты пользователя:**
-%s`, anketaText, productsText)
+	rec, err := s.complete(ctx, RecommendationGeneral, prompt, constraints, products, onProgress)
+	if err != nil {
+		return "", err
+	}
 
-	return s.openRouterClient.GetRecommendation(prompt)
+	s.remember(ctx, userID, RecommendationGeneral, state, products, rec)
+	return rec.content, nil
 }
 
-// formatAnketaForPrompt форматирует анкету для промпта
-func (s *RecommendationService) formatAnketaForPrompt(profile *models.APIUserProfile) string {
-	var parts []string
-
-	if profile.SkinType != "" {
-		parts = append(parts, fmt.Sprintf("Тип кожи: %s", profile.SkinType))
+// userRoutine возвращает уход, составленный пользователем, или nil, если
+// его нет, он пуст или не загрузился
+func (s *RecommendationService) userRoutine(ctx context.Context, userID int64) *routine.UserRoutine {
+	if s.routines == nil {
+		return nil
 	}
-	if profile.Age != "" {
-		parts = append(parts, fmt.Sprintf("Возраст: %s", profile.Age))
+	own, err := s.routines.Get(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, routine.ErrNotFound) {
+			log.Printf("Ошибка получения ухода пользователя %d: %v", userID, err)
+		}
+		return nil
 	}
-	if profile.Gender != "" {
-		parts = append(parts, fmt.Sprintf("Пол: %s", profile.Gender))
+	if own.Empty() {
+		return nil
 	}
-	if profile.Pregnancy != "" {
-		parts = append(parts, fmt.Sprintf("Беременность/лактация: %s", profile.Pregnancy))
+	return own
+}
+
+// safetyConstraints формирует обязательные ограничения для беременных и кормящих:
+// активы, которых нужно избегать, и продукты коллекции, в которых они найдены.
+// Если ограничения не нужны, возвращает пустую строку.
+func (s *RecommendationService) safetyConstraints(loc *i18n.Localizer, state *models.UserState, products []*models.APIProductDetail) string {
+	safety := NewPregnancySafety(state)
+	if !safety.Applies() {
+		return ""
 	}
-	if profile.Concern != "" {
-		parts = append(parts, fmt.Sprintf("Проблемы: %s", profile.Concern))
+
+	status := safety.Status()
+	if question, ok := questionnaire.Skincare.Question("pregnancy"); ok {
+		status = question.AnswerLabel(loc, status)
 	}
-	if profile.Goal != "" {
-		parts = append(parts, fmt.Sprintf("Цель: %s", profile.Goal))
+
+	var b strings.Builder
+	b.WriteString(loc.T("prompt.safety.header", status))
+	for _, flag := range safety.Constraints() {
+		b.WriteString(loc.T("prompt.safety.rule",
+			loc.T("safety.rule."+flag.Rule+".name"),
+			loc.T("prompt.safety.severity."+string(flag.Severity))))
 	}
-	if profile.Climate != "" {
-		parts = append(parts, fmt.Sprintf("Климат: %s", profile.Climate))
+
+	var flagged strings.Builder
+	for _, detail := range products {
+		for _, flag := range safety.Check(detail) {
+			flagged.WriteString(loc.T("prompt.safety.product",
+				detail.Brand, detail.Title,
+				loc.T("safety.rule."+flag.Rule+".name"),
+				strings.Join(flag.Ingredients, ", "),
+				loc.T("prompt.safety.severity."+string(flag.Severity))))
+		}
 	}
-	if profile.Fitzpatrick != "" {
-		parts = append(parts, fmt.Sprintf("Тип кожи по Фитцпатрику: %s", profile.Fitzpatrick))
+	if flagged.Len() > 0 {
+		b.WriteString(loc.T("prompt.safety.products"))
+		b.WriteString(flagged.String())
 	}
-	if profile.Lifestyle != "" {
-		parts = append(parts, fmt.Sprintf("Образ жизни: %s", profile.Lifestyle))
+	return b.String()
+}
+
+// promptConflictProduct продукт из пары конфликтующих в контексте для модели
+type promptConflictProduct struct {
+	Product     string       `json:"product"`
+	Active      string       `json:"active"`
+	Ingredients []string     `json:"ingredients"`
+	Slot        routine.Slot `json:"slot,omitempty"`
+}
+
+// promptConflict конфликт в контексте для модели
+type promptConflict struct {
+	Products [2]promptConflictProduct `json:"products"`
+	Advice   ConflictAdvice           `json:"advice"`
+	Reason   string                   `json:"reason"`
+}
+
+// formatConflictsForPrompt передает найденные конфликты активов в виде JSON,
+// чтобы модель не ставила конфликтующие средства в один шаг ухода.
+// Если конфликтов нет, возвращает пустую строку.
+func (s *RecommendationService) formatConflictsForPrompt(loc *i18n.Localizer, analysis *ConflictAnalysis) string {
+	if len(analysis.Conflicts) == 0 {
+		return ""
 	}
-	if profile.Diet != "" {
-		parts = append(parts, fmt.Sprintf("Питание: %s", profile.Diet))
+
+	conflicts := make([]promptConflict, 0, len(analysis.Conflicts))
+	for _, conflict := range analysis.Conflicts {
+		product := func(active ProductActive, slot routine.Slot) promptConflictProduct {
+			return promptConflictProduct{
+				Product:     active.Brand + " " + active.Title,
+				Active:      loc.T("conflicts.class." + string(active.Class)),
+				Ingredients: active.Ingredients,
+				Slot:        slot,
+			}
+		}
+		conflicts = append(conflicts, promptConflict{
+			Products: [2]promptConflictProduct{
+				product(conflict.First, conflict.FirstSlot),
+				product(conflict.Second, conflict.SecondSlot),
+			},
+			Advice: conflict.Advice,
+			Reason: loc.T("conflicts.rule." + conflict.Rule),
+		})
 	}
-	if profile.Allergy != "" {
-		parts = append(parts, fmt.Sprintf("Аллергии: %s", profile.Allergy))
+
+	data, err := json.Marshal(conflicts)
+	if err != nil {
+		log.Printf("Ошибка формирования конфликтов для промпта: %v", err)
+		return ""
+	}
+	return loc.T("prompt.conflicts", string(data))
+}
+
+// formatAnketaForPrompt форматирует анкету для промпта на языке пользователя.
+// Вопросы и порядок берутся из анкеты, названия ответов — из переводов.
+func (s *RecommendationService) formatAnketaForPrompt(loc *i18n.Localizer, state *models.UserState) string {
+	var parts []string
+	for _, question := range questionnaire.Skincare.Questions() {
+		answer := question.Labels(loc, state)
+		if answer == "" {
+			continue
+		}
+		if other := question.Other(state); other != "" {
+			parts = append(parts, loc.T("prompt.answer_other", question.Title(loc), answer, other))
+		} else {
+			parts = append(parts, loc.T("prompt.answer", question.Title(loc), answer))
+		}
 	}
 
 	return strings.Join(parts, "\n")
 }
 
 // formatProductsForPrompt форматирует продукты для промпта
-func (s *RecommendationService) formatProductsForPrompt(products []models.APIUserProduct) string {
+func (s *RecommendationService) formatProductsForPrompt(loc *i18n.Localizer, products []models.APIUserProduct) string {
 	if len(products) == 0 {
-		return "У пользователя пока нет добавленных продуктов."
+		return loc.T("prompt.no_products")
 	}
 
 	var parts []string
 	for _, product := range products {
-		parts = append(parts, fmt.Sprintf("- %s (%s)", product.Title, product.Brand))
+		parts = append(parts, loc.T("prompt.product", product.Title, product.Brand, product.ProductID))
 		if product.Details != "" {
-			parts = append(parts, fmt.Sprintf("  Описание: %s", product.Details))
+			parts = append(parts, loc.T("prompt.product_details", product.Details))
+		}
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// formatUserRoutineForPrompt форматирует уход, составленный пользователем:
+// шаги утром и вечером с этапом и частотой, продукты коллекции вне ухода
+// и нарушения порядка нанесения. Шаги с продуктами, которых уже нет
+// в коллекции, пропускаются.
+func (s *RecommendationService) formatUserRoutineForPrompt(loc *i18n.Localizer, own *routine.UserRoutine, products []models.APIUserProduct) string {
+	collection := make(map[int]models.APIUserProduct, len(products))
+	for _, product := range products {
+		collection[product.ProductID] = product
+	}
+
+	var parts []string
+	if own.Name != "" {
+		parts = append(parts, loc.T("prompt.user_routine.named", own.Name))
+	} else {
+		parts = append(parts, loc.T("prompt.user_routine"))
+	}
+
+	used := make(map[int]bool)
+	for _, slot := range routine.Slots {
+		parts = append(parts, loc.T("prompt.user_routine.slot."+string(slot)))
+		number := 0
+		for _, entry := range own.Steps(slot) {
+			product, ok := collection[entry.ProductID]
+			if !ok {
+				continue
+			}
+			used[entry.ProductID] = true
+			number++
+			parts = append(parts, loc.T("prompt.user_routine.step", number, product.Title, product.Brand, product.ProductID,
+				loc.T("routine.category."+string(entry.Category)), loc.T("routine.frequency."+string(entry.Frequency))))
+			if product.Details != "" {
+				parts = append(parts, loc.T("prompt.product_details", product.Details))
+			}
+		}
+		if number == 0 {
+			parts = append(parts, loc.T("prompt.user_routine.slot_empty"))
+		}
+	}
+
+	var unused []string
+	for _, product := range products {
+		if !used[product.ProductID] {
+			unused = append(unused, loc.T("prompt.product", product.Title, product.Brand, product.ProductID))
 		}
 	}
+	if len(unused) > 0 {
+		parts = append(parts, loc.T("prompt.user_routine.unused"))
+		parts = append(parts, unused...)
+	}
+
+	var issues []string
+	for _, issue := range own.Check() {
+		if _, ok := collection[issue.Entry.ProductID]; !ok {
+			continue
+		}
+		slot := loc.T("prompt.user_routine.slot." + string(issue.Slot))
+		switch issue.Kind {
+		case routine.IssueOrder:
+			issues = append(issues, loc.T("prompt.user_routine.issue.order", slot,
+				issue.Entry.Title, loc.T("routine.category."+string(issue.Entry.Category)),
+				issue.After.Title, loc.T("routine.category."+string(issue.After.Category))))
+		case routine.IssueEveningSPF:
+			issues = append(issues, loc.T("prompt.user_routine.issue.evening_spf", issue.Entry.Title))
+		}
+	}
+	if len(issues) > 0 {
+		parts = append(parts, loc.T("prompt.user_routine.issues"))
+		parts = append(parts, issues...)
+	}
 
 	return strings.Join(parts, "\n")
 }
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"cos-ai-bot/internal/catalog"
+	"cos-ai-bot/internal/database"
+	"cos-ai-bot/internal/models"
+)
+
+// answerConcerns группы ингредиентов, которые проверяются для ответов анкеты
+var answerConcerns = map[string][]IngredientConcern{
+	string(catalog.AllergyFragrance):     {ConcernFragrance},
+	string(catalog.AllergyLanolin):       {ConcernLanolin},
+	string(catalog.AllergyPreservatives): {ConcernPreservative},
+	string(catalog.DietVegan):            {ConcernAnimal},
+	string(catalog.DietNoAnimal):         {ConcernAnimal},
+	string(catalog.DietVegetarian):       {ConcernSlaughter},
+	string(catalog.DietHalal):            {ConcernSlaughter, ConcernAlcohol},
+	string(catalog.DietNoAlcohol):        {ConcernAlcohol},
+	string(catalog.DietGlutenFree):       {ConcernGluten},
+}
+
+// concernOrder порядок групп в отчетах
+var concernOrder = []IngredientConcern{
+	ConcernFragrance, ConcernLanolin, ConcernPreservative, ConcernAlcohol,
+	ConcernAnimal, ConcernSlaughter, ConcernGluten, ConcernCustom,
+}
+
+// IngredientFlag найденные в составе ингредиенты одной группы
+type IngredientFlag struct {
+	Concern IngredientConcern
+	// Reasons коды ответов анкеты, из-за которых группа проверяется;
+	// для ConcernCustom — название, которое пользователь указал сам
+	Reasons     []string
+	Ingredients []string // названия ингредиентов как в составе продукта
+}
+
+// ProductScreening результат проверки одного продукта
+type ProductScreening struct {
+	ProductID     int
+	Brand         string
+	Title         string
+	Flags         []IngredientFlag
+	NoIngredients bool // состав неизвестен, проверить продукт нельзя
+}
+
+// IngredientScreener проверяет составы по аллергиям и особенностям питания из анкеты
+type IngredientScreener struct {
+	reasons map[IngredientConcern][]string
+	custom  []string // названия ингредиентов из ответа «Другое» на вопрос об аллергиях
+}
+
+// NewIngredientScreener собирает ограничения из анкеты
+func NewIngredientScreener(state *models.UserState) *IngredientScreener {
+	s := &IngredientScreener{reasons: make(map[IngredientConcern][]string)}
+	if state == nil {
+		return s
+	}
+
+	for _, code := range append(append([]string(nil), state.Allergies...), state.Diet...) {
+		for _, concern := range answerConcerns[code] {
+			s.reasons[concern] = append(s.reasons[concern], code)
+		}
+	}
+	for _, term := range strings.FieldsFunc(state.AllergiesOther, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n'
+	}) {
+		if term = strings.TrimSpace(term); utf8.RuneCountInString(term) >= 3 {
+			s.custom = append(s.custom, term)
+		}
+	}
+	return s
+}
+
+// Empty проверяет, что в анкете нет ограничений, которые можно проверить по составу
+func (s *IngredientScreener) Empty() bool {
+	return len(s.reasons) == 0 && len(s.custom) == 0
+}
+
+// Screen находит в составе ингредиенты, которых пользователю стоит избегать.
+// Группы возвращаются в порядке concernOrder, ингредиенты — в порядке состава.
+func (s *IngredientScreener) Screen(ingredients []models.APIIngredientRef) []IngredientFlag {
+	found := make(map[IngredientConcern][]string)
+	customFound := make(map[string][]string)
+
+	for _, ingredient := range ingredients {
+		name := normalizeINCI(ingredient.Name)
+		if name == "" {
+			continue
+		}
+
+		matched := make(map[IngredientConcern]bool)
+		for _, rule := range inciRules {
+			if !matchINCI(name, rule.pattern) {
+				continue
+			}
+			for _, concern := range rule.concerns {
+				if _, ok := s.reasons[concern]; ok && !matched[concern] {
+					matched[concern] = true
+					found[concern] = append(found[concern], ingredient.Name)
+				}
+			}
+		}
+		for _, term := range s.custom {
+			if matchINCI(name, normalizeINCI(term)) {
+				customFound[term] = append(customFound[term], ingredient.Name)
+			}
+		}
+	}
+
+	var flags []IngredientFlag
+	for _, concern := range concernOrder {
+		if names := found[concern]; len(names) > 0 {
+			flags = append(flags, IngredientFlag{Concern: concern, Reasons: s.reasons[concern], Ingredients: names})
+		}
+	}
+	for _, term := range s.custom {
+		if names := customFound[term]; len(names) > 0 {
+			flags = append(flags, IngredientFlag{Concern: ConcernCustom, Reasons: []string{term}, Ingredients: names})
+		}
+	}
+	return flags
+}
+
+// ScreenProduct проверяет состав продукта
+func (s *IngredientScreener) ScreenProduct(product *models.APIProductDetail) ProductScreening {
+	return ProductScreening{
+		ProductID:     product.ID,
+		Brand:         product.Brand,
+		Title:         product.Title,
+		Flags:         s.Screen(product.Ingredients),
+		NoIngredients: len(product.Ingredients) == 0,
+	}
+}
+
+// ScreenCollection проверяет составы всех продуктов из коллекции пользователя.
+// Продукт, состав которого не удалось загрузить, помечается как непроверенный.
+func (s *IngredientScreener) ScreenCollection(ctx context.Context, userID int64) ([]ProductScreening, error) {
+	products, err := database.GetUserProducts(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения продуктов: %v", err)
+	}
+
+	details, err := loadProductDetails(ctx, products)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ProductScreening, 0, len(details))
+	for _, detail := range details {
+		results = append(results, s.ScreenProduct(detail))
+	}
+	return results, nil
+}
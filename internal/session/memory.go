@@ -0,0 +1,83 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"cos-ai-bot/internal/models"
+)
+
+// memorySession сессия в памяти с временем последнего обновления
+type memorySession struct {
+	state     models.UserState
+	updatedAt time.Time
+}
+
+// MemoryStore потокобезопасное хранилище сессий в памяти процесса.
+// Не переживает перезапуск; используется, когда база данных не настроена.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	ttl      time.Duration
+	sessions map[int64]memorySession
+}
+
+// NewMemoryStore создает хранилище сессий в памяти
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{
+		ttl:      ttl,
+		sessions: make(map[int64]memorySession),
+	}
+}
+
+// Get возвращает копию сохраненного состояния
+func (s *MemoryStore) Get(ctx context.Context, userID int64) (*models.UserState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, ok := s.sessions[userID]
+	if !ok || s.expired(session, time.Now()) {
+		return nil, ErrNotFound
+	}
+	state := session.state
+	return &state, nil
+}
+
+// Save сохраняет копию состояния
+func (s *MemoryStore) Save(ctx context.Context, userID int64, state *models.UserState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions[userID] = memorySession{state: *state, updatedAt: time.Now()}
+	return nil
+}
+
+// Delete удаляет сессию пользователя
+func (s *MemoryStore) Delete(ctx context.Context, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, userID)
+	return nil
+}
+
+// DeleteExpired удаляет сессии старше TTL
+func (s *MemoryStore) DeleteExpired(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var deleted int64
+	for userID, session := range s.sessions {
+		if s.expired(session, now) {
+			delete(s.sessions, userID)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// expired проверяет, истек ли срок жизни сессии
+func (s *MemoryStore) expired(session memorySession, now time.Time) bool {
+	return s.ttl > 0 && now.Sub(session.updatedAt) > s.ttl
+}
@@ -0,0 +1,103 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"cos-ai-bot/internal/models"
+
+	_ "github.com/lib/pq" // драйвер PostgreSQL для database/sql
+)
+
+// PostgresStore хранилище сессий в таблице form_sessions
+// (см. migrations/002_form_sessions.sql)
+type PostgresStore struct {
+	db  *sql.DB
+	ttl time.Duration
+}
+
+// NewPostgresStore создает хранилище поверх открытого подключения
+func NewPostgresStore(db *sql.DB, ttl time.Duration) *PostgresStore {
+	return &PostgresStore{db: db, ttl: ttl}
+}
+
+// OpenPostgres открывает подключение к PostgreSQL и проверяет его
+func OpenPostgres(ctx context.Context, databaseURL string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия подключения к PostgreSQL: %v", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("PostgreSQL недоступен: %v", err)
+	}
+	return db, nil
+}
+
+// Get возвращает состояние анкеты, если сессия не истекла
+func (s *PostgresStore) Get(ctx context.Context, userID int64) (*models.UserState, error) {
+	var data []byte
+	err := s.db.QueryRowContext(ctx, `
+		SELECT state FROM form_sessions
+		WHERE user_id = $1 AND ($2::float8 <= 0 OR updated_at > CURRENT_TIMESTAMP - make_interval(secs => $2::float8))`,
+		userID, s.ttl.Seconds(),
+	).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения сессии анкеты: %v", err)
+	}
+
+	var state models.UserState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("ошибка разбора сессии анкеты: %v", err)
+	}
+	return &state, nil
+}
+
+// Save сохраняет состояние анкеты и обновляет время сессии
+func (s *PostgresStore) Save(ctx context.Context, userID int64, state *models.UserState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("ошибка маршалинга сессии анкеты: %v", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO form_sessions (user_id, state, updated_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP)
+		ON CONFLICT (user_id) DO UPDATE
+		SET state = EXCLUDED.state, updated_at = EXCLUDED.updated_at`,
+		userID, data)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения сессии анкеты: %v", err)
+	}
+	return nil
+}
+
+// Delete удаляет сессию пользователя
+func (s *PostgresStore) Delete(ctx context.Context, userID int64) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM form_sessions WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("ошибка удаления сессии анкеты: %v", err)
+	}
+	return nil
+}
+
+// DeleteExpired удаляет сессии, не обновлявшиеся дольше TTL
+func (s *PostgresStore) DeleteExpired(ctx context.Context) (int64, error) {
+	if s.ttl <= 0 {
+		return 0, nil
+	}
+
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM form_sessions WHERE updated_at < CURRENT_TIMESTAMP - make_interval(secs => $1::float8)`,
+		s.ttl.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("ошибка удаления истекших сессий: %v", err)
+	}
+	return result.RowsAffected()
+}
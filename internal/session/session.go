@@ -0,0 +1,24 @@
+package session
+
+import (
+	"context"
+	"errors"
+
+	"cos-ai-bot/internal/models"
+)
+
+// ErrNotFound возвращается, если незавершенной анкеты нет или она истекла
+var ErrNotFound = errors.New("сессия анкеты не найдена")
+
+// Store хранит прогресс заполнения анкеты между перезапусками бота.
+// Сессии, не обновлявшиеся дольше TTL хранилища, считаются брошенными.
+type Store interface {
+	// Get возвращает сохраненное состояние анкеты или ErrNotFound
+	Get(ctx context.Context, userID int64) (*models.UserState, error)
+	// Save сохраняет состояние и продлевает срок жизни сессии
+	Save(ctx context.Context, userID int64, state *models.UserState) error
+	// Delete удаляет сессию пользователя
+	Delete(ctx context.Context, userID int64) error
+	// DeleteExpired удаляет брошенные сессии и возвращает их количество
+	DeleteExpired(ctx context.Context) (int64, error)
+}
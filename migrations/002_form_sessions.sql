@@ -0,0 +1,9 @@
+-- Незавершенные анкеты: текущий шаг и частичные ответы
+CREATE TABLE IF NOT EXISTS form_sessions (
+    user_id BIGINT PRIMARY KEY,
+    state JSONB NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Индекс для очистки брошенных анкет
+CREATE INDEX IF NOT EXISTS idx_form_sessions_updated_at ON form_sessions(updated_at);
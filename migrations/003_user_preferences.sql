@@ -0,0 +1,6 @@
+-- Настройки пользователей, выбранные в боте
+CREATE TABLE IF NOT EXISTS user_preferences (
+    user_id BIGINT PRIMARY KEY,
+    language VARCHAR(8),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
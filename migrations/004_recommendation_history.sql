@@ -0,0 +1,14 @@
+-- История выданных рекомендаций вместе с данными, на которых они основаны
+CREATE TABLE IF NOT EXISTS recommendation_history (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    profile JSONB NOT NULL,
+    products JSONB,
+    model VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Индекс для просмотра истории пользователя от новых записей к старым
+CREATE INDEX IF NOT EXISTS idx_recommendation_history_user ON recommendation_history(user_id, id DESC);
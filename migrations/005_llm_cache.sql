@@ -0,0 +1,10 @@
+-- Кэш ответов языковой модели: ключ — SHA-256 от промпта, модели и параметров
+CREATE TABLE IF NOT EXISTS llm_cache (
+    key CHAR(64) PRIMARY KEY,
+    model VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Индекс для удаления устаревших ответов
+CREATE INDEX IF NOT EXISTS idx_llm_cache_created_at ON llm_cache(created_at);
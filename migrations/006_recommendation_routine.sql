@@ -0,0 +1,3 @@
+-- План ухода из ответа модели в виде данных (см. internal/routine).
+-- Для рекомендаций, выданных до перехода на план, остается NULL.
+ALTER TABLE recommendation_history ADD COLUMN IF NOT EXISTS routine JSONB;
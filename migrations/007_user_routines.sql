@@ -0,0 +1,9 @@
+-- Уход, который пользователь составил из продуктов своей коллекции (см. internal/routine).
+-- Шаги утром и вечером хранятся в порядке нанесения.
+CREATE TABLE IF NOT EXISTS user_routines (
+    user_id BIGINT PRIMARY KEY,
+    name VARCHAR(64) NOT NULL DEFAULT '',
+    morning JSONB NOT NULL DEFAULT '[]',
+    evening JSONB NOT NULL DEFAULT '[]',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
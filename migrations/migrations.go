@@ -0,0 +1,66 @@
+// Package migrations содержит SQL-миграции базы данных и применяет
+// миграции хранилищ бота при запуске.
+//
+// 001_initial_schema.sql — исходная схема, которую создает контейнер базы
+// при первом запуске (docker-entrypoint-initdb.d); бот ее не применяет.
+// Миграции начиная с 002 относятся к хранилищам бота и должны оставаться
+// идемпотентными (IF NOT EXISTS): они выполняются при каждом запуске, поэтому
+// существующая база получает новые таблицы и колонки без ручных шагов.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+)
+
+// files SQL-файлы миграций, встроенные в бинарник
+//
+//go:embed *.sql
+var files embed.FS
+
+// first первая миграция, которую применяет бот
+const first = "002"
+
+// lockID ключ advisory-блокировки: несколько экземпляров бота,
+// запущенных одновременно, применяют миграции по очереди
+const lockID = 7_240_021
+
+// Apply применяет миграции хранилищ бота по порядку имен файлов
+func Apply(ctx context.Context, db *sql.DB) error {
+	names, err := fs.Glob(files, "*.sql")
+	if err != nil {
+		return fmt.Errorf("ошибка чтения списка миграций: %v", err)
+	}
+	sort.Strings(names)
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка подключения для миграций: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return fmt.Errorf("ошибка блокировки миграций: %v", err)
+	}
+	defer conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, lockID)
+
+	for _, name := range names {
+		if name < first {
+			continue
+		}
+		query, err := files.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("ошибка чтения миграции %s: %v", name, err)
+		}
+		if _, err := conn.ExecContext(ctx, string(query)); err != nil {
+			return fmt.Errorf("ошибка применения миграции %s: %v", name, err)
+		}
+		log.Printf("Миграция %s применена", name)
+	}
+	return nil
+}